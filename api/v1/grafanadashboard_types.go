@@ -1,39 +1,151 @@
 package v1
 
 import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DashboardFolderAnnotation can be used to select the folder in which a
+// dashboard is created.
+const DashboardFolderAnnotation = "dark/folder"
+
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 // Important: Run "make" to regenerate code after modifying this file
 
 // GrafanaDashboardStatus defines the observed state of a GrafanaDashboard
 type GrafanaDashboardStatus struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
+	v1alpha1.SyncStatus `json:",inline"`
+
+	// UID of the dashboard in Grafana.
+	UID string `json:"uid,omitempty"`
+
+	// URL of the dashboard in Grafana.
+	URL string `json:"url,omitempty"`
+
+	// Version of the dashboard in Grafana.
+	Version int64 `json:"version,omitempty"`
+
+	// UID of the folder holding the dashboard in Grafana.
+	FolderUID string `json:"folderUID,omitempty"`
+
+	// Whether DARK created the folder holding the dashboard. Only such folders
+	// are deleted once they are empty.
+	FolderCreated bool `json:"folderCreated,omitempty"`
+
+	// Hash of the dashboard spec last applied, after resolving its source.
+	SpecHash string `json:"specHash,omitempty"`
+
+	// Whether the permissions of the dashboard were replaced by the ones of the
+	// manifest. They are reset once the manifest doesn't define any.
+	PermissionsApplied bool `json:"permissionsApplied,omitempty"`
+}
+
+// DashboardSource describes a dashboard defined as a raw Grafana JSON
+// dashboard instead of an inline spec.
+// Only one of the following may be specified.
+type DashboardSource struct {
+	// FromConfigMap selects a key of a ConfigMap holding a Grafana JSON dashboard.
+	FromConfigMap *corev1.ConfigMapKeySelector `json:"fromConfigMap,omitempty"`
+
+	// FromJSON holds a Grafana JSON dashboard.
+	FromJSON string `json:"fromJSON,omitempty"`
+}
+
+// IsSet tells whether the source is defined.
+func (in DashboardSource) IsSet() bool {
+	return in.FromConfigMap != nil || in.FromJSON != ""
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:shortName=dashboards;dashboard;gd;grafana-dashboards
-//+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`
-//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.message`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].message`
+//+kubebuilder:printcolumn:name="UID",type=string,JSONPath=`.status.uid`
+//+kubebuilder:printcolumn:name="Version",type=integer,JSONPath=`.status.version`
+//+kubebuilder:printcolumn:name="Folder",type=string,JSONPath=`.status.folderUID`,priority=1
+//+kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.status.url`,priority=1
+//+kubebuilder:printcolumn:name="Last sync",type=date,JSONPath=`.status.lastSyncTime`
 
 // GrafanaDashboard is the Schema for the grafanadashboards API
 type GrafanaDashboard struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
+	// Spec of the dashboard. It may also hold an instanceRef, referencing the
+	// GrafanaInstance the dashboard should be synchronized with.
 	//+kubebuilder:pruning:PreserveUnknownFields
 	Spec runtime.RawExtension `json:"spec"`
 	//+kubebuilder:validation:Optional
 	Folder string `json:"folder"`
+	// References the GrafanaFolder holding the dashboard. Takes precedence over folder.
+	//+kubebuilder:validation:Optional
+	FolderRef *v1alpha1.FolderRef `json:"folderRef,omitempty"`
+	// Permissions on the dashboard. When set, they replace the dashboard's
+	// existing permissions. When emptied, the dashboard inherits the
+	// permissions of its folder again.
+	//+kubebuilder:validation:Optional
+	Permissions []v1alpha1.Permission `json:"permissions,omitempty"`
 
 	Status GrafanaDashboardStatus `json:"status,omitempty"`
 }
 
+// Source returns the source of the dashboard, if its spec isn't inlined.
+func (in *GrafanaDashboard) Source() (DashboardSource, error) {
+	source := DashboardSource{}
+
+	if err := json.Unmarshal(in.Spec.Raw, &source); err != nil {
+		return source, fmt.Errorf("could not unmarshall dashboard json spec: %w", err)
+	}
+
+	if source.FromConfigMap != nil && source.FromJSON != "" {
+		return source, fmt.Errorf("only one of fromConfigMap and fromJSON may be specified")
+	}
+
+	return source, nil
+}
+
+// InstanceRef returns the GrafanaInstance the dashboard should be synchronized
+// with, referenced by spec.instanceRef.
+func (in *GrafanaDashboard) InstanceRef() *v1alpha1.InstanceRef {
+	settings := struct {
+		InstanceRef *v1alpha1.InstanceRef `json:"instanceRef,omitempty"`
+	}{}
+
+	// invalid specs are reported when reading their source
+	_ = json.Unmarshal(in.Spec.Raw, &settings)
+
+	return settings.InstanceRef
+}
+
+// DashboardSpec returns the inline spec of the dashboard, without the
+// reference to its GrafanaInstance.
+func (in *GrafanaDashboard) DashboardSpec() ([]byte, error) {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(in.Spec.Raw, &fields); err != nil {
+		return nil, fmt.Errorf("could not unmarshall dashboard json spec: %w", err)
+	}
+
+	if _, ok := fields["instanceRef"]; !ok {
+		return in.Spec.Raw, nil
+	}
+
+	delete(fields, "instanceRef")
+
+	return json.Marshal(fields)
+}
+
+// GetSyncStatus returns the synchronization status of the GrafanaDashboard.
+func (in *GrafanaDashboard) GetSyncStatus() *v1alpha1.SyncStatus {
+	return &in.Status.SyncStatus
+}
+
 //+kubebuilder:object:root=true
 
 // GrafanaDashboardList contains a list of GrafanaDashboard
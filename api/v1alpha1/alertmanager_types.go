@@ -19,6 +19,13 @@ type AlertManagerSpec struct {
 	ContactPoints    []ContactPoint    `json:"contact_points,omitempty"`
 	Routing          []RoutingPolicy   `json:"routing,omitempty"`
 	MessageTemplates map[string]string `json:"message_templates,omitempty"`
+
+	// MuteTimings are recurring time windows during which the routing
+	// policies referencing them don't send notifications.
+	MuteTimings []MuteTiming `json:"mute_timings,omitempty"`
+
+	// Grafana instance to configure. Defaults to the operator-wide instance.
+	InstanceRef *InstanceRef `json:"instanceRef,omitempty"`
 }
 
 type ContactPoint struct {
@@ -32,6 +39,12 @@ type ContactPointType struct {
 	Slack    *SlackContactType    `json:"slack,omitempty"`
 	Opsgenie *OpsgenieContactType `json:"opsgenie,omitempty"`
 	Discord  *DiscordContactType  `json:"discord,omitempty"`
+
+	PagerDuty  *PagerDutyContactType  `json:"pagerduty,omitempty"`
+	Teams      *TeamsContactType      `json:"teams,omitempty"`
+	Webhook    *WebhookContactType    `json:"webhook,omitempty"`
+	Telegram   *TelegramContactType   `json:"telegram,omitempty"`
+	GoogleChat *GoogleChatContactType `json:"googlechat,omitempty"`
 }
 
 type EmailContactType struct {
@@ -47,10 +60,13 @@ type SlackContactType struct {
 }
 
 type OpsgenieContactType struct {
-	APIURL           string     `json:"api_url,omitempty"`
-	APIKey           ValueOrRef `json:"api_key,omitempty"`
-	AutoClose        bool       `json:"auto_close,omitempty"`
-	OverridePriority bool       `json:"override_priority,omitempty"`
+	// URL of the Opsgenie API, as a plain string or a ValueOrRef.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	APIURL           *StringOrRef `json:"api_url,omitempty"`
+	APIKey           ValueOrRef   `json:"api_key,omitempty"`
+	AutoClose        bool         `json:"auto_close,omitempty"`
+	OverridePriority bool         `json:"override_priority,omitempty"`
 }
 
 type DiscordContactType struct {
@@ -58,10 +74,81 @@ type DiscordContactType struct {
 	UseDiscordUsername bool       `json:"use_discord_username,omitempty"`
 }
 
-type RoutingPolicy struct {
+type PagerDutyContactType struct {
+	IntegrationKey ValueOrRef `json:"integration_key,omitempty"`
+	// +kubebuilder:validation:Enum=critical;error;warning;info
+	Severity  string `json:"severity,omitempty"`
+	Class     string `json:"class,omitempty"`
+	Component string `json:"component,omitempty"`
+	Group     string `json:"group,omitempty"`
+	Summary   string `json:"summary,omitempty"`
+}
+
+type TeamsContactType struct {
+	Webhook      ValueOrRef `json:"webhook,omitempty"`
+	Title        string     `json:"title,omitempty"`
+	SectionTitle string     `json:"section_title,omitempty"`
+	Message      string     `json:"message,omitempty"`
+}
+
+type WebhookContactType struct {
+	URL ValueOrRef `json:"url,omitempty"`
+	// +kubebuilder:validation:Enum=POST;PUT
+	HTTPMethod string `json:"http_method,omitempty"`
+
+	// Basic authentication.
+	Username string      `json:"username,omitempty"`
+	Password *ValueOrRef `json:"password,omitempty"`
+
+	// Authorization header, such as "Bearer some-token". Can not be used with
+	// basic authentication.
+	AuthorizationScheme      string      `json:"authorization_scheme,omitempty"`
+	AuthorizationCredentials *ValueOrRef `json:"authorization_credentials,omitempty"`
+
+	// Maximum number of alerts sent in a single request. Zero means no limit.
+	// +kubebuilder:validation:Minimum=0
+	MaxAlerts int `json:"max_alerts,omitempty"`
+}
+
+type TelegramContactType struct {
+	BotToken ValueOrRef `json:"bot_token,omitempty"`
 	// +kubebuilder:validation:Required
-	ContactPoint string               `json:"to"`
+	ChatID               string `json:"chat_id"`
+	Message              string `json:"message,omitempty"`
+	DisableNotifications bool   `json:"disable_notifications,omitempty"`
+}
+
+type GoogleChatContactType struct {
+	Webhook ValueOrRef `json:"webhook,omitempty"`
+	Title   string     `json:"title,omitempty"`
+	Message string     `json:"message,omitempty"`
+}
+
+type RoutingPolicy struct {
+	// Contact point receiving the alerts matched by this policy. Defaults to
+	// the contact point of the parent policy.
+	ContactPoint string               `json:"to,omitempty"`
 	Rules        []LabelsMatchingRule `json:"if_labels,omitempty"`
+
+	// Continue matching the next sibling policies after this one matched.
+	Continue bool `json:"continue,omitempty"`
+
+	// Labels to group alerts by. Defaults to the grouping of the parent policy.
+	GroupBy []string `json:"group_by,omitempty"`
+
+	// Timings overriding the ones of the parent policy.
+	GroupWait      string `json:"group_wait,omitempty"`
+	GroupInterval  string `json:"group_interval,omitempty"`
+	RepeatInterval string `json:"repeat_interval,omitempty"`
+
+	// Names of the mute timings silencing this policy, as declared in
+	// mute_timings.
+	MuteTimings []string `json:"mute_timings,omitempty"`
+
+	// Child policies, matched against the alerts matched by this policy.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Routing []RoutingPolicy `json:"routing,omitempty"`
 }
 
 type LabelsMatchingRule struct {
@@ -71,16 +158,54 @@ type LabelsMatchingRule struct {
 	NotMatches map[string]string `json:"not_matches,omitempty"`
 }
 
+type MuteTiming struct {
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// The mute timing applies when any of these intervals matches.
+	// +kubebuilder:validation:MinItems=1
+	TimeIntervals []TimeInterval `json:"time_intervals"`
+}
+
+// TimeInterval describes a recurring time window. Every field is optional, and
+// an empty interval matches any time.
+type TimeInterval struct {
+	Times []TimeRange `json:"times,omitempty"`
+	// Days of the week, or ranges of days, such as "monday:friday".
+	Weekdays []string `json:"weekdays,omitempty"`
+	// Days of the month, or ranges of days, such as "1:5". Negative days
+	// count from the end of the month.
+	DaysOfMonth []string `json:"days_of_month,omitempty"`
+	// Months, or ranges of months, such as "january:march" or "1:3".
+	Months []string `json:"months,omitempty"`
+	// Years, or ranges of years, such as "2023:2025".
+	Years []string `json:"years,omitempty"`
+	// Time zone in which the interval is evaluated, such as "Europe/Paris".
+	// Defaults to UTC.
+	Location string `json:"location,omitempty"`
+}
+
+// TimeRange describes a range of time within a day, as HH:MM.
+type TimeRange struct {
+	// +kubebuilder:validation:Pattern=`^\d{2}:\d{2}$`
+	StartTime string `json:"start_time"`
+	// +kubebuilder:validation:Pattern=`^\d{2}:\d{2}$`
+	EndTime string `json:"end_time"`
+}
+
 // AlertManagerStatus defines the observed state of AlertManager
 type AlertManagerStatus struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
+	SyncStatus `json:",inline"`
+
+	// Names of the mute timings declared by the manifest when it was last
+	// synchronized. Other mute timings found in Grafana are left untouched.
+	MuteTimings []string `json:"muteTimings,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
-//+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`
-//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.message`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].message`
+//+kubebuilder:printcolumn:name="Last sync",type=date,JSONPath=`.status.lastSyncTime`
 
 // AlertManager is the Schema for the alertmanagers API
 type AlertManager struct {
@@ -91,6 +216,11 @@ type AlertManager struct {
 	Status AlertManagerStatus `json:"status,omitempty"`
 }
 
+// GetSyncStatus returns the synchronization status of the AlertManager.
+func (in *AlertManager) GetSyncStatus() *SyncStatus {
+	return &in.Status.SyncStatus
+}
+
 //+kubebuilder:object:root=true
 
 // AlertManagerList contains a list of AlertManager
@@ -13,19 +13,91 @@ type APIKeySpec struct {
 	// +kubebuilder:validation:Enum=admin;editor;viewer
 	// +kubebuilder:validation:Required
 	Role string `json:"role"`
+
+	// Kind of credentials created in Grafana: a legacy API key, or a service
+	// account and its token. Switching an existing key to "serviceAccount"
+	// migrates it to a service account.
+	// +kubebuilder:validation:Enum=apiKey;serviceAccount
+	// +kubebuilder:default=apiKey
+	Mode string `json:"mode,omitempty"`
+
+	// Lifetime of the keys created in Grafana, in seconds. Keys are replaced
+	// before they expire. Defaults to keys that never expire.
+	// +kubebuilder:validation:Minimum=0
+	SecondsToLive int64 `json:"secondsToLive,omitempty"`
+
+	// Delay after which the key is replaced by a new one, as a duration (ex: "720h").
+	RotateEvery string `json:"rotateEvery,omitempty"`
+
+	// Delay during which a replaced key remains valid, as a duration (ex: "1h").
+	// Defaults to revoking replaced keys immediately.
+	RotationOverlap string `json:"rotationOverlap,omitempty"`
+
+	// Kubernetes secret in which the key is exposed.
+	Secret *APIKeySecret `json:"secret,omitempty"`
+
+	// Grafana instance in which the key is created. Defaults to the operator-wide instance.
+	InstanceRef *InstanceRef `json:"instanceRef,omitempty"`
+}
+
+// APIKeySecret describes the Kubernetes secret in which an API key is exposed.
+type APIKeySecret struct {
+	// Name of the secret. Defaults to the name of the APIKey.
+	Name string `json:"name,omitempty"`
+
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Key under which the token is stored. Defaults to "token".
+	TokenKey string `json:"tokenKey,omitempty"`
+
+	// Extra keys to store in the secret, rendered as Go templates.
+	// The templates can reference the token ({{ .Token }}), the URL of the
+	// Grafana instance ({{ .Host }}), the name of the key in Grafana
+	// ({{ .Name }}) and its role ({{ .Role }}).
+	Templates map[string]string `json:"templates,omitempty"`
 }
 
 // APIKeyStatus defines the observed state of APIKey
 type APIKeyStatus struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
+	SyncStatus `json:",inline"`
+
+	// Name in Grafana of the key currently exposed in the secret.
+	KeyName string `json:"keyName,omitempty"`
+
+	// Creation time of the current key.
+	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
+
+	// Expiry time of the current key.
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// Time at which the current key will be replaced.
+	NextRotationTime *metav1.Time `json:"nextRotationTime,omitempty"`
+
+	// Last time the key was replaced by a new one.
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+
+	// Name in Grafana of the replaced key that is still valid.
+	PreviousKeyName string `json:"previousKeyName,omitempty"`
+
+	// Time at which the replaced key will be revoked.
+	PreviousKeyRevocationTime *metav1.Time `json:"previousKeyRevocationTime,omitempty"`
+
+	// SHA-256 of the token exposed in the secret. A secret holding another
+	// token was tampered with, and leads to the key being re-created.
+	TokenHash string `json:"tokenHash,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:shortName=api-keys;apikeys;api-key;apikey;grafana-api-keys
-//+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`
-//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.message`
+//+kubebuilder:printcolumn:name="Role",type=string,JSONPath=`.spec.role`
+//+kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`
+//+kubebuilder:printcolumn:name="Expires",type=date,JSONPath=`.status.expiresAt`,priority=1
+//+kubebuilder:printcolumn:name="Next rotation",type=date,JSONPath=`.status.nextRotationTime`,priority=1
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].message`
+//+kubebuilder:printcolumn:name="Last sync",type=date,JSONPath=`.status.lastSyncTime`
 
 // APIKey is the Schema for the apikeys API
 type APIKey struct {
@@ -36,6 +108,11 @@ type APIKey struct {
 	Status APIKeyStatus `json:"status,omitempty"`
 }
 
+// GetSyncStatus returns the synchronization status of the APIKey.
+func (in *APIKey) GetSyncStatus() *SyncStatus {
+	return &in.Status.SyncStatus
+}
+
 //+kubebuilder:object:root=true
 
 // APIKeyList contains a list of APIKey
@@ -1,7 +1,10 @@
 package v1alpha1
 
 import (
+	"encoding/json"
+
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type ValueOrRef struct {
@@ -10,6 +13,118 @@ type ValueOrRef struct {
 	ValueRef *ValueRef `json:"valueFrom,omitempty"`
 }
 
+// ValueRef references a value. Only one of its fields may be specified.
 type ValueRef struct {
-	SecretKeyRef *v1.SecretKeySelector `json:"secretKeyRef,omitempty" protobuf:"bytes,4,opt,name=secretKeyRef"`
+	SecretKeyRef    *v1.SecretKeySelector    `json:"secretKeyRef,omitempty" protobuf:"bytes,4,opt,name=secretKeyRef"`
+	ConfigMapKeyRef *v1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty" protobuf:"bytes,3,opt,name=configMapKeyRef"`
+	ExternalKeyRef  *ExternalKeySelector     `json:"externalKeyRef,omitempty"`
+}
+
+// ExternalKeySelector selects a key of a secret held by one of the external
+// secret backends configured in the operator.
+type ExternalKeySelector struct {
+	// Name of the backend holding the secret: vault or file.
+	// +kubebuilder:validation:Required
+	Backend string `json:"backend"`
+
+	// Path of the secret, relative to the root of the namespace in the backend.
+	// +kubebuilder:validation:Required
+	Path string `json:"path"`
+
+	// The key to select: an entry of a Vault secret, or a file in a directory.
+	// Left empty to read a file.
+	Key string `json:"key,omitempty"`
+
+	// Specify whether the key must be defined.
+	Optional *bool `json:"optional,omitempty"`
+}
+
+// StringOrRef is a ValueOrRef that can also be written as a plain string.
+// It is used for fields that used to only accept plain strings, which must be
+// marked as schemaless.
+type StringOrRef struct {
+	ValueOrRef `json:",inline"`
+}
+
+// NewStringOrRef returns a StringOrRef holding the given plain value.
+func NewStringOrRef(value string) StringOrRef {
+	return StringOrRef{ValueOrRef: ValueOrRef{Value: value}}
+}
+
+// UnmarshalJSON accepts either a plain string or a ValueOrRef object.
+func (in *StringOrRef) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		in.ValueRef = nil
+
+		return json.Unmarshal(data, &in.Value)
+	}
+
+	return json.Unmarshal(data, &in.ValueOrRef)
+}
+
+// MarshalJSON renders plain values as plain strings.
+func (in StringOrRef) MarshalJSON() ([]byte, error) {
+	if in.ValueRef == nil {
+		return json.Marshal(in.Value)
+	}
+
+	return json.Marshal(in.ValueOrRef)
+}
+
+// InstanceRef references the GrafanaInstance an object should be synchronized with.
+type InstanceRef struct {
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Namespace of the GrafanaInstance. Defaults to the namespace of the referencing object.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// TeamRef references a GrafanaTeam living in the same namespace.
+type TeamRef struct {
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+}
+
+// Permission grants a permission on a folder or a dashboard to a team or to a
+// role. Only one of team, teamRef or role may be specified.
+type Permission struct {
+	// Name of the team the permission is granted to.
+	Team string `json:"team,omitempty"`
+
+	// GrafanaTeam the permission is granted to.
+	TeamRef *TeamRef `json:"teamRef,omitempty"`
+
+	// Role the permission is granted to.
+	// +kubebuilder:validation:Enum=Viewer;Editor
+	Role string `json:"role,omitempty"`
+
+	// +kubebuilder:validation:Enum=view;edit;admin
+	Permission string `json:"permission"`
+}
+
+// Types of the conditions reported by resources synchronized with Grafana.
+const (
+	// ReadyCondition is true when all the other conditions are true.
+	ReadyCondition = "Ready"
+
+	// SynchronizedCondition is true when the resource was successfully synchronized with Grafana.
+	SynchronizedCondition = "Synchronized"
+
+	// SecretsResolvedCondition is true when all the values referenced by the resource could be read.
+	SecretsResolvedCondition = "SecretsResolved"
+
+	// GrafanaReachableCondition is true when the Grafana instance could be reached.
+	GrafanaReachableCondition = "GrafanaReachable"
+)
+
+// SyncStatus is the observed state shared by all the resources synchronized with Grafana.
+type SyncStatus struct {
+	// Generation of the resource last handled by the operator.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Last time the resource was successfully synchronized with Grafana.
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -14,15 +15,19 @@ func init() {
 
 // DatasourceStatus defines the observed state of Datasource
 type DatasourceStatus struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
+	SyncStatus `json:",inline"`
+
+	// UID under which the datasource was last synchronized in Grafana.
+	UID string `json:"uid,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:shortName=datasources;datasource;grafana-datasources
-//+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`
-//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.message`
+//+kubebuilder:printcolumn:name="UID",type=string,JSONPath=`.status.uid`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].message`
+//+kubebuilder:printcolumn:name="Last sync",type=date,JSONPath=`.status.lastSyncTime`
 
 // Datasource is the Schema for the datasources API
 type Datasource struct {
@@ -33,6 +38,11 @@ type Datasource struct {
 	Status DatasourceStatus `json:"status,omitempty"`
 }
 
+// GetSyncStatus returns the synchronization status of the Datasource.
+func (in *Datasource) GetSyncStatus() *SyncStatus {
+	return &in.Status.SyncStatus
+}
+
 //+kubebuilder:object:root=true
 
 // DatasourceList contains a list of Datasource
@@ -43,24 +53,46 @@ type DatasourceList struct {
 }
 
 type DatasourceSpec struct {
+	// UID of the datasource in Grafana. Defaults to a UID derived from the
+	// namespace and name of the manifest.
+	// +kubebuilder:validation:MaxLength=40
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]+$`
+	UID string `json:"uid,omitempty"`
+	// Name of the datasource in Grafana. Defaults to the name of the manifest.
+	DisplayName string `json:"displayName,omitempty"`
+
 	Prometheus  *PrometheusDatasource  `json:"prometheus,omitempty"`
 	Stackdriver *StackdriverDatasource `json:"stackdriver,omitempty"`
 	Jaeger      *JaegerDatasource      `json:"jaeger,omitempty"`
 	Loki        *LokiDatasource        `json:"loki,omitempty"`
 	Tempo       *TempoDatasource       `json:"tempo,omitempty"`
 	CloudWatch  *CloudWatchDatasource  `json:"cloudwatch,omitempty"`
+
+	InfluxDB      *InfluxDBDatasource      `json:"influxdb,omitempty"`
+	Graphite      *GraphiteDatasource      `json:"graphite,omitempty"`
+	Elasticsearch *ElasticsearchDatasource `json:"elasticsearch,omitempty"`
+	PostgreSQL    *PostgreSQLDatasource    `json:"postgresql,omitempty"`
+	MySQL         *MySQLDatasource         `json:"mysql,omitempty"`
+
+	Generic *GenericDatasource `json:"generic,omitempty"`
+
+	// Grafana instance in which the datasource is created. Defaults to the operator-wide instance.
+	InstanceRef *InstanceRef `json:"instanceRef,omitempty"`
 }
 
 type PrometheusDatasource struct {
+	// URL of the datasource, as a plain string or a ValueOrRef.
 	// +kubebuilder:validation:Required
-	URL                string   `json:"url"`
-	Default            *bool    `json:"default,omitempty"`
-	ForwardOauth       *bool    `json:"forward_oauth,omitempty"`
-	ForwardCredentials *bool    `json:"forward_credentials,omitempty"`
-	SkipTLSVerify      *bool    `json:"skip_tls_verify,omitempty"`
-	ForwardCookies     []string `json:"forward_cookies,omitempty"`
-	ScrapeInterval     string   `json:"scrape_interval,omitempty"`
-	QueryTimeout       string   `json:"query_timeout,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	URL                StringOrRef `json:"url"`
+	Default            *bool       `json:"default,omitempty"`
+	ForwardOauth       *bool       `json:"forward_oauth,omitempty"`
+	ForwardCredentials *bool       `json:"forward_credentials,omitempty"`
+	SkipTLSVerify      *bool       `json:"skip_tls_verify,omitempty"`
+	ForwardCookies     []string    `json:"forward_cookies,omitempty"`
+	ScrapeInterval     string      `json:"scrape_interval,omitempty"`
+	QueryTimeout       string      `json:"query_timeout,omitempty"`
 	// +kubebuilder:validation:Enum=POST;GET
 	HTTPMethod string `json:"http_method,omitempty"`
 	// +kubebuilder:validation:Enum=proxy;direct
@@ -83,9 +115,12 @@ type PrometheusExemplar struct {
 }
 
 type LokiDatasource struct {
+	// URL of the datasource, as a plain string or a ValueOrRef.
 	// +kubebuilder:validation:Required
-	URL     string `json:"url"`
-	Default *bool  `json:"default,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	URL     StringOrRef `json:"url"`
+	Default *bool       `json:"default,omitempty"`
 
 	ForwardOauth       *bool       `json:"forward_oauth,omitempty"`
 	ForwardCredentials *bool       `json:"forward_credentials,omitempty"`
@@ -116,9 +151,12 @@ type LokiDerivedField struct {
 }
 
 type TempoDatasource struct {
+	// URL of the datasource, as a plain string or a ValueOrRef.
 	// +kubebuilder:validation:Required
-	URL     string `json:"url"`
-	Default *bool  `json:"default,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	URL     StringOrRef `json:"url"`
+	Default *bool       `json:"default,omitempty"`
 
 	ForwardOauth       *bool       `json:"forward_oauth,omitempty"`
 	ForwardCredentials *bool       `json:"forward_credentials,omitempty"`
@@ -148,9 +186,12 @@ type StackdriverDatasource struct {
 }
 
 type JaegerDatasource struct {
+	// URL of the datasource, as a plain string or a ValueOrRef.
 	// +kubebuilder:validation:Required
-	URL     string `json:"url"`
-	Default *bool  `json:"default,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	URL     StringOrRef `json:"url"`
+	Default *bool       `json:"default,omitempty"`
 
 	ForwardOauth       *bool       `json:"forward_oauth,omitempty"`
 	ForwardCredentials *bool       `json:"forward_credentials,omitempty"`
@@ -206,3 +247,181 @@ type ValueOrDatasourceRef struct {
 	UID  string `json:"uid,omitempty"`
 	Name string `json:"name,omitempty"`
 }
+
+type InfluxDBDatasource struct {
+	// URL of the datasource, as a plain string or a ValueOrRef.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	URL     StringOrRef `json:"url"`
+	Default *bool       `json:"default,omitempty"`
+
+	// Query language of the datasource.
+	// +kubebuilder:validation:Enum=InfluxQL;Flux
+	QueryLanguage string `json:"query_language,omitempty"`
+
+	// InfluxQL only: database to query, and credentials to query it with.
+	Database string      `json:"database,omitempty"`
+	User     *ValueOrRef `json:"user,omitempty"`
+	Password *ValueOrRef `json:"password,omitempty"`
+	// +kubebuilder:validation:Enum=POST;GET
+	HTTPMethod string `json:"http_method,omitempty"`
+
+	// Flux only: organization and default bucket to query, and token to query them with.
+	Organization  string      `json:"organization,omitempty"`
+	DefaultBucket string      `json:"default_bucket,omitempty"`
+	Token         *ValueOrRef `json:"token,omitempty"`
+
+	// Lower limit for the auto group by time interval, such as "10s".
+	MinTimeInterval string `json:"min_time_interval,omitempty"`
+
+	SkipTLSVerify *bool       `json:"skip_tls_verify,omitempty"`
+	Timeout       string      `json:"timeout,omitempty"`
+	BasicAuth     *BasicAuth  `json:"basic_auth,omitempty"`
+	CACertificate *ValueOrRef `json:"ca_certificate,omitempty"`
+}
+
+type GraphiteDatasource struct {
+	// URL of the datasource, as a plain string or a ValueOrRef.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	URL     StringOrRef `json:"url"`
+	Default *bool       `json:"default,omitempty"`
+
+	// Version of Graphite, such as "1.1".
+	Version string `json:"version,omitempty"`
+	// +kubebuilder:validation:Enum=default;metrictank
+	Type string `json:"type,omitempty"`
+
+	SkipTLSVerify *bool       `json:"skip_tls_verify,omitempty"`
+	Timeout       string      `json:"timeout,omitempty"`
+	BasicAuth     *BasicAuth  `json:"basic_auth,omitempty"`
+	CACertificate *ValueOrRef `json:"ca_certificate,omitempty"`
+}
+
+type ElasticsearchDatasource struct {
+	// URL of the datasource, as a plain string or a ValueOrRef.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	URL     StringOrRef `json:"url"`
+	Default *bool       `json:"default,omitempty"`
+
+	// Flavor of the cluster. OpenSearch clusters require the OpenSearch plugin in Grafana.
+	// +kubebuilder:validation:Enum=elasticsearch;opensearch
+	Flavor string `json:"flavor,omitempty"`
+	// Version of the cluster, such as "8.0.0".
+	Version string `json:"version,omitempty"`
+
+	// Name or pattern of the index to query.
+	// +kubebuilder:validation:Required
+	Index string `json:"index"`
+	// Pattern used to name indices, if any.
+	// +kubebuilder:validation:Enum=Hourly;Daily;Weekly;Monthly;Yearly
+	IndexInterval string `json:"index_interval,omitempty"`
+	// Field holding the time of documents. Default: "@timestamp".
+	TimeField string `json:"time_field,omitempty"`
+
+	MaxConcurrentShardRequests *int   `json:"max_concurrent_shard_requests,omitempty"`
+	LogMessageField            string `json:"log_message_field,omitempty"`
+	LogLevelField              string `json:"log_level_field,omitempty"`
+
+	// Lower limit for the auto group by time interval, such as "10s".
+	MinTimeInterval string `json:"min_time_interval,omitempty"`
+
+	SkipTLSVerify *bool       `json:"skip_tls_verify,omitempty"`
+	Timeout       string      `json:"timeout,omitempty"`
+	BasicAuth     *BasicAuth  `json:"basic_auth,omitempty"`
+	CACertificate *ValueOrRef `json:"ca_certificate,omitempty"`
+}
+
+type PostgreSQLDatasource struct {
+	// Address of the server, as "host:port", as a plain string or a ValueOrRef.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	URL     StringOrRef `json:"url"`
+	Default *bool       `json:"default,omitempty"`
+
+	// +kubebuilder:validation:Required
+	Database string `json:"database"`
+	// +kubebuilder:validation:Required
+	User ValueOrRef `json:"user"`
+	// +kubebuilder:validation:Required
+	Password ValueOrRef `json:"password"`
+
+	// Maximum number of open connections to the database. Zero means unlimited.
+	MaxOpenConnections *int `json:"max_open_connections,omitempty"`
+	// Maximum number of idle connections.
+	MaxIdleConnections *int `json:"max_idle_connections,omitempty"`
+	// Maximum amount of time, in seconds, a connection may be reused.
+	ConnectionMaxLifetime *int `json:"connection_max_lifetime,omitempty"`
+	// Lower limit for the $__interval and $__interval_ms variables, such as "1m".
+	MinTimeInterval string `json:"min_time_interval,omitempty"`
+
+	// +kubebuilder:validation:Enum=disable;require;verify-ca;verify-full
+	SSLMode string `json:"ssl_mode,omitempty"`
+	// Version of PostgreSQL, such as 1200 for 12.
+	Version     *int  `json:"version,omitempty"`
+	TimescaleDB *bool `json:"timescaledb,omitempty"`
+}
+
+type MySQLDatasource struct {
+	// Address of the server, as "host:port", as a plain string or a ValueOrRef.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	URL     StringOrRef `json:"url"`
+	Default *bool       `json:"default,omitempty"`
+
+	// +kubebuilder:validation:Required
+	Database string `json:"database"`
+	// +kubebuilder:validation:Required
+	User ValueOrRef `json:"user"`
+	// +kubebuilder:validation:Required
+	Password ValueOrRef `json:"password"`
+
+	// Maximum number of open connections to the database. Zero means unlimited.
+	MaxOpenConnections *int `json:"max_open_connections,omitempty"`
+	// Maximum number of idle connections.
+	MaxIdleConnections *int `json:"max_idle_connections,omitempty"`
+	// Maximum amount of time, in seconds, a connection may be reused.
+	ConnectionMaxLifetime *int `json:"connection_max_lifetime,omitempty"`
+	// Lower limit for the $__interval and $__interval_ms variables, such as "1m".
+	MinTimeInterval string `json:"min_time_interval,omitempty"`
+
+	// Time zone used to convert dates, such as "Europe/Paris" or "+02:00".
+	Timezone      string      `json:"timezone,omitempty"`
+	SkipTLSVerify *bool       `json:"skip_tls_verify,omitempty"`
+	CACertificate *ValueOrRef `json:"ca_certificate,omitempty"`
+}
+
+// GenericDatasource describes a datasource of any type, passed as-is to
+// Grafana. It allows using plugins that don't have a dedicated spec.
+type GenericDatasource struct {
+	// Type of the datasource, as known by Grafana. Example: "grafana-clickhouse-datasource"
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Type string `json:"type"`
+
+	// URL of the datasource, as a plain string or a ValueOrRef.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	URL     *StringOrRef `json:"url,omitempty"`
+	Default *bool        `json:"default,omitempty"`
+
+	// +kubebuilder:validation:Enum=proxy;direct
+	Access string `json:"access,omitempty"`
+
+	BasicAuth *BasicAuth `json:"basic_auth,omitempty"`
+
+	// Settings of the datasource, as expected by its plugin.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	JSONData *runtime.RawExtension `json:"jsonData,omitempty"`
+
+	// Sensitive settings of the datasource, as expected by its plugin.
+	SecureJSONData map[string]ValueOrRef `json:"secureJsonData,omitempty"`
+}
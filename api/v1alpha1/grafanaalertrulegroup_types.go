@@ -0,0 +1,187 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GrafanaAlertRuleGroupSpec defines the desired state of a group of Grafana
+// alert rules.
+type GrafanaAlertRuleGroupSpec struct {
+	// Name of the rule group in Grafana. Defaults to the name of the resource.
+	Name string `json:"name,omitempty"`
+
+	// Title of the folder holding the rule group. The folder is created if needed.
+	Folder string `json:"folder,omitempty"`
+	// References the GrafanaFolder holding the rule group. Takes precedence over folder.
+	FolderRef *FolderRef `json:"folderRef,omitempty"`
+
+	// Interval at which the rules of the group are evaluated, such as "1m".
+	Interval string `json:"interval,omitempty"`
+
+	// Datasource queried by the rules of the group.
+	// +kubebuilder:validation:Required
+	Datasource ValueOrDatasourceRef `json:"datasource"`
+
+	// +kubebuilder:validation:MinItems=1
+	Rules []AlertRule `json:"rules"`
+
+	InstanceRef *InstanceRef `json:"instanceRef,omitempty"`
+}
+
+// AlertRule describes a Grafana alert rule. Its fields mirror the ones of
+// alerts defined in dashboard panels.
+type AlertRule struct {
+	// +kubebuilder:validation:Required
+	Title string `json:"title"`
+
+	Summary     string `json:"summary,omitempty"`
+	Description string `json:"description,omitempty"`
+	Runbook     string `json:"runbook,omitempty"`
+
+	// Duration during which the conditions must hold before the alert fires, such as "5m".
+	For string `json:"for,omitempty"`
+	// +kubebuilder:validation:Enum=no_data;alerting;ok
+	OnNoData string `json:"on_no_data,omitempty"`
+	// +kubebuilder:validation:Enum=alerting;error;ok
+	OnExecutionError string `json:"on_execution_error,omitempty"`
+
+	// Labels attached to the alerts, used to route their notifications.
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// +kubebuilder:validation:MinItems=1
+	If []AlertCondition `json:"if"`
+	// +kubebuilder:validation:MinItems=1
+	Targets []AlertTarget `json:"targets"`
+}
+
+// AlertCondition reduces the result of a query to a single value, and
+// compares it to a threshold.
+// Only one reducer and one threshold may be specified.
+type AlertCondition struct {
+	// +kubebuilder:validation:Enum=and;or
+	Operand string `json:"operand,omitempty"`
+
+	// Reducers, applied to the query with the given ref.
+	Avg         string `json:"avg,omitempty"`
+	Sum         string `json:"sum,omitempty"`
+	Count       string `json:"count,omitempty"`
+	Last        string `json:"last,omitempty"`
+	Min         string `json:"min,omitempty"`
+	Max         string `json:"max,omitempty"`
+	Median      string `json:"median,omitempty"`
+	Diff        string `json:"diff,omitempty"`
+	PercentDiff string `json:"percent_diff,omitempty"`
+
+	// Thresholds.
+	HasNoValue bool    `json:"has_no_value,omitempty"`
+	Above      *Number `json:"above,omitempty"`
+	Below      *Number `json:"below,omitempty"`
+	// +kubebuilder:validation:MinItems=2
+	// +kubebuilder:validation:MaxItems=2
+	OutsideRange []Number `json:"outside_range,omitempty"`
+	// +kubebuilder:validation:MinItems=2
+	// +kubebuilder:validation:MaxItems=2
+	WithinRange []Number `json:"within_range,omitempty"`
+}
+
+// AlertTarget describes a query evaluated by an alert rule.
+// Only one of the following may be specified.
+type AlertTarget struct {
+	Prometheus *AlertQuery `json:"prometheus,omitempty"`
+	Loki       *AlertQuery `json:"loki,omitempty"`
+	Graphite   *AlertQuery `json:"graphite,omitempty"`
+}
+
+type AlertQuery struct {
+	// Identifier of the query, referenced by conditions.
+	// +kubebuilder:validation:Required
+	Ref string `json:"ref"`
+	// +kubebuilder:validation:Required
+	Query  string `json:"query"`
+	Legend string `json:"legend,omitempty"`
+	// Time range queried, such as "5m".
+	Lookback string `json:"lookback,omitempty"`
+}
+
+// Number is a decimal number, stored as a string as CRDs don't support floats.
+// +kubebuilder:validation:Type=number
+type Number string
+
+// Float64 returns the value of the number.
+func (in Number) Float64() (float64, error) {
+	return strconv.ParseFloat(string(in), 64)
+}
+
+func (in *Number) UnmarshalJSON(data []byte) error {
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return err
+	}
+
+	*in = Number(number)
+
+	return nil
+}
+
+func (in Number) MarshalJSON() ([]byte, error) {
+	return json.Marshal(json.Number(in))
+}
+
+// GrafanaAlertRuleGroupStatus defines the observed state of GrafanaAlertRuleGroup
+type GrafanaAlertRuleGroupStatus struct {
+	SyncStatus `json:",inline"`
+
+	// Title of the folder holding the rule group in Grafana.
+	Folder string `json:"folder,omitempty"`
+	// Name of the rule group in Grafana.
+	Group string `json:"group,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=alert-rule-groups;alert-rule-group;garg
+//+kubebuilder:printcolumn:name="Folder",type=string,JSONPath=`.status.folder`
+//+kubebuilder:printcolumn:name="Group",type=string,JSONPath=`.status.group`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].message`
+//+kubebuilder:printcolumn:name="Last sync",type=date,JSONPath=`.status.lastSyncTime`
+
+// GrafanaAlertRuleGroup is the Schema for the grafanaalertrulegroups API
+type GrafanaAlertRuleGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaAlertRuleGroupSpec   `json:"spec"`
+	Status GrafanaAlertRuleGroupStatus `json:"status,omitempty"`
+}
+
+// GetSyncStatus returns the synchronization status of the GrafanaAlertRuleGroup.
+func (in *GrafanaAlertRuleGroup) GetSyncStatus() *SyncStatus {
+	return &in.Status.SyncStatus
+}
+
+// GroupName returns the name of the rule group in Grafana.
+func (in *GrafanaAlertRuleGroup) GroupName() string {
+	if in.Spec.Name != "" {
+		return in.Spec.Name
+	}
+
+	return in.Name
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaAlertRuleGroupList contains a list of GrafanaAlertRuleGroup
+type GrafanaAlertRuleGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaAlertRuleGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaAlertRuleGroup{}, &GrafanaAlertRuleGroupList{})
+}
@@ -0,0 +1,92 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+// Important: Run "make" to regenerate code after modifying this file
+
+// GrafanaFolderSpec defines the desired state of a Grafana folder
+type GrafanaFolderSpec struct {
+	// +kubebuilder:validation:Required
+	Title string `json:"title"`
+
+	// UID of the folder in Grafana. Defaults to the name of the resource.
+	// +kubebuilder:validation:MaxLength=40
+	UID string `json:"uid,omitempty"`
+
+	// Parent folder, for nested folders. Requires Grafana's nested folders feature.
+	ParentRef *FolderRef `json:"parentRef,omitempty"`
+
+	// Permissions on the folder. When set, they replace the folder's existing
+	// permissions. When emptied, the folder's permissions are reset to Grafana's
+	// defaults.
+	Permissions []Permission `json:"permissions,omitempty"`
+
+	InstanceRef *InstanceRef `json:"instanceRef,omitempty"`
+}
+
+// FolderRef references a GrafanaFolder living in the same namespace.
+type FolderRef struct {
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+}
+
+// GrafanaFolderStatus defines the observed state of GrafanaFolder
+type GrafanaFolderStatus struct {
+	SyncStatus `json:",inline"`
+
+	// UID of the folder in Grafana.
+	UID string `json:"uid,omitempty"`
+
+	// Whether the permissions of the folder were replaced by the ones of the
+	// manifest. They are reset once the manifest doesn't define any.
+	PermissionsApplied bool `json:"permissionsApplied,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=grafana-folders;grafana-folder;folders;folder;gf
+//+kubebuilder:printcolumn:name="Title",type=string,JSONPath=`.spec.title`
+//+kubebuilder:printcolumn:name="UID",type=string,JSONPath=`.status.uid`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].message`
+//+kubebuilder:printcolumn:name="Last sync",type=date,JSONPath=`.status.lastSyncTime`
+
+// GrafanaFolder is the Schema for the grafanafolders API
+type GrafanaFolder struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaFolderSpec   `json:"spec"`
+	Status GrafanaFolderStatus `json:"status,omitempty"`
+}
+
+// GetSyncStatus returns the synchronization status of the GrafanaFolder.
+func (in *GrafanaFolder) GetSyncStatus() *SyncStatus {
+	return &in.Status.SyncStatus
+}
+
+// FolderUID returns the UID of the folder in Grafana.
+func (in *GrafanaFolder) FolderUID() string {
+	if in.Spec.UID != "" {
+		return in.Spec.UID
+	}
+
+	return in.Name
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaFolderList contains a list of GrafanaFolder
+type GrafanaFolderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaFolder `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaFolder{}, &GrafanaFolderList{})
+}
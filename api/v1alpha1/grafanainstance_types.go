@@ -0,0 +1,77 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+// Important: Run "make" to regenerate code after modifying this file
+
+// GrafanaInstanceSpec defines how to reach and authenticate to a Grafana instance
+type GrafanaInstanceSpec struct {
+	// URL of the Grafana instance.
+	// +kubebuilder:validation:Required
+	URL string `json:"url"`
+
+	// API key (or service account token) used to authenticate.
+	// Takes precedence over basic_auth.
+	APIKey *ValueOrRef `json:"api_key,omitempty"`
+
+	// Credentials used to authenticate when no API key is given.
+	BasicAuth *BasicAuth `json:"basic_auth,omitempty"`
+
+	TLS *GrafanaInstanceTLS `json:"tls,omitempty"`
+
+	// Namespaces, other than the instance's own, whose objects may reference
+	// this instance. "*" allows every namespace.
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+}
+
+type GrafanaInstanceTLS struct {
+	// Skips SSL certificates verification. Useful when self-signed certificates are used, but can be insecure.
+	SkipTLSVerify *bool `json:"skip_tls_verify,omitempty"`
+
+	// PEM-encoded CA certificate used to verify the instance's certificate.
+	CACertificate *ValueOrRef `json:"ca_certificate,omitempty"`
+}
+
+// GrafanaInstanceStatus defines the observed state of GrafanaInstance
+type GrafanaInstanceStatus struct {
+	SyncStatus `json:",inline"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=grafana-instances;grafana-instance;gi
+//+kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.spec.url`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].message`
+//+kubebuilder:printcolumn:name="Last sync",type=date,JSONPath=`.status.lastSyncTime`
+
+// GrafanaInstance is the Schema for the grafanainstances API
+type GrafanaInstance struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaInstanceSpec   `json:"spec"`
+	Status GrafanaInstanceStatus `json:"status,omitempty"`
+}
+
+// GetSyncStatus returns the synchronization status of the GrafanaInstance.
+func (in *GrafanaInstance) GetSyncStatus() *SyncStatus {
+	return &in.Status.SyncStatus
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaInstanceList contains a list of GrafanaInstance
+type GrafanaInstanceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaInstance `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaInstance{}, &GrafanaInstanceList{})
+}
@@ -0,0 +1,69 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GrafanaSilenceSpec defines the desired state of GrafanaSilence
+type GrafanaSilenceSpec struct {
+	// Alerts matching all of these rules are silenced.
+	// +kubebuilder:validation:MinItems=1
+	Matchers []LabelsMatchingRule `json:"matchers"`
+
+	// Start of the silence. Defaults to the creation of the silence.
+	StartsAt *metav1.Time `json:"starts_at,omitempty"`
+	// End of the silence.
+	// +kubebuilder:validation:Required
+	EndsAt metav1.Time `json:"ends_at"`
+
+	// +kubebuilder:validation:Required
+	Comment string `json:"comment"`
+	// Author of the silence. Defaults to "DARK".
+	CreatedBy string `json:"created_by,omitempty"`
+
+	// Grafana instance to configure. Defaults to the operator-wide instance.
+	InstanceRef *InstanceRef `json:"instanceRef,omitempty"`
+}
+
+// GrafanaSilenceStatus defines the observed state of GrafanaSilence
+type GrafanaSilenceStatus struct {
+	SyncStatus `json:",inline"`
+
+	// ID of the silence in Grafana.
+	ID string `json:"id,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=grafana-silences;grafana-silence;silences;silence;gs
+//+kubebuilder:printcolumn:name="Ends at",type=date,JSONPath=`.spec.ends_at`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].message`
+//+kubebuilder:printcolumn:name="Last sync",type=date,JSONPath=`.status.lastSyncTime`
+
+// GrafanaSilence is the Schema for the grafanasilences API
+type GrafanaSilence struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaSilenceSpec   `json:"spec"`
+	Status GrafanaSilenceStatus `json:"status,omitempty"`
+}
+
+// GetSyncStatus returns the synchronization status of the GrafanaSilence.
+func (in *GrafanaSilence) GetSyncStatus() *SyncStatus {
+	return &in.Status.SyncStatus
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaSilenceList contains a list of GrafanaSilence
+type GrafanaSilenceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaSilence `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaSilence{}, &GrafanaSilenceList{})
+}
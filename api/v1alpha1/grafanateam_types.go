@@ -0,0 +1,92 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+// Important: Run "make" to regenerate code after modifying this file
+
+// GrafanaTeamSpec defines the desired state of a Grafana team
+type GrafanaTeamSpec struct {
+	// Name of the team in Grafana. Defaults to the name of the resource.
+	Name string `json:"name,omitempty"`
+
+	Email string `json:"email,omitempty"`
+
+	// Logins or emails of the members of the team. Members not listed here are
+	// removed from the team.
+	Members []string `json:"members,omitempty"`
+
+	Preferences *TeamPreferences `json:"preferences,omitempty"`
+
+	InstanceRef *InstanceRef `json:"instanceRef,omitempty"`
+}
+
+// TeamPreferences describes the preferences applied to the members of a team.
+type TeamPreferences struct {
+	// +kubebuilder:validation:Enum=light;dark;system
+	Theme string `json:"theme,omitempty"`
+
+	// UID of the home dashboard of the team.
+	HomeDashboardUID string `json:"homeDashboardUID,omitempty"`
+
+	// Timezone, as "utc", "browser" or an IANA timezone.
+	Timezone string `json:"timezone,omitempty"`
+
+	// First day of the week, as "monday", "saturday" or "sunday".
+	WeekStart string `json:"weekStart,omitempty"`
+}
+
+// GrafanaTeamStatus defines the observed state of GrafanaTeam
+type GrafanaTeamStatus struct {
+	SyncStatus `json:",inline"`
+
+	// ID of the team in Grafana.
+	ID int64 `json:"id,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=grafana-teams;grafana-team;teams;team
+//+kubebuilder:printcolumn:name="Team",type=string,JSONPath=`.spec.name`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].message`
+//+kubebuilder:printcolumn:name="Last sync",type=date,JSONPath=`.status.lastSyncTime`
+
+// GrafanaTeam is the Schema for the grafanateams API
+type GrafanaTeam struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaTeamSpec   `json:"spec,omitempty"`
+	Status GrafanaTeamStatus `json:"status,omitempty"`
+}
+
+// GetSyncStatus returns the synchronization status of the GrafanaTeam.
+func (in *GrafanaTeam) GetSyncStatus() *SyncStatus {
+	return &in.Status.SyncStatus
+}
+
+// TeamName returns the name of the team in Grafana.
+func (in *GrafanaTeam) TeamName() string {
+	if in.Spec.Name != "" {
+		return in.Spec.Name
+	}
+
+	return in.Name
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaTeamList contains a list of GrafanaTeam
+type GrafanaTeamList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaTeam `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaTeam{}, &GrafanaTeamList{})
+}
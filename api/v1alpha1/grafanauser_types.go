@@ -0,0 +1,85 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+// Important: Run "make" to regenerate code after modifying this file
+
+// GrafanaUserSpec defines the desired state of a Grafana user
+type GrafanaUserSpec struct {
+	// Login of the user in Grafana. Defaults to the name of the resource.
+	Login string `json:"login,omitempty"`
+
+	// +kubebuilder:validation:Required
+	Email string `json:"email"`
+
+	// Display name of the user.
+	Name string `json:"name,omitempty"`
+
+	// Initial password of the user. It is only used when the user is created.
+	// +kubebuilder:validation:Required
+	Password ValueOrRef `json:"password"`
+
+	// Role of the user in the organization of the Grafana instance.
+	// +kubebuilder:validation:Enum=Viewer;Editor;Admin
+	// +kubebuilder:default=Viewer
+	OrgRole string `json:"orgRole,omitempty"`
+
+	InstanceRef *InstanceRef `json:"instanceRef,omitempty"`
+}
+
+// GrafanaUserStatus defines the observed state of GrafanaUser
+type GrafanaUserStatus struct {
+	SyncStatus `json:",inline"`
+
+	// ID of the user in Grafana.
+	ID int64 `json:"id,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=grafana-users;grafana-user
+//+kubebuilder:printcolumn:name="Email",type=string,JSONPath=`.spec.email`
+//+kubebuilder:printcolumn:name="Role",type=string,JSONPath=`.spec.orgRole`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].message`
+//+kubebuilder:printcolumn:name="Last sync",type=date,JSONPath=`.status.lastSyncTime`
+
+// GrafanaUser is the Schema for the grafanausers API
+type GrafanaUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaUserSpec   `json:"spec,omitempty"`
+	Status GrafanaUserStatus `json:"status,omitempty"`
+}
+
+// GetSyncStatus returns the synchronization status of the GrafanaUser.
+func (in *GrafanaUser) GetSyncStatus() *SyncStatus {
+	return &in.Status.SyncStatus
+}
+
+// UserLogin returns the login of the user in Grafana.
+func (in *GrafanaUser) UserLogin() string {
+	if in.Spec.Login != "" {
+		return in.Spec.Login
+	}
+
+	return in.Name
+}
+
+//+kubebuilder:object:root=true
+
+// GrafanaUserList contains a list of GrafanaUser
+type GrafanaUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaUser{}, &GrafanaUserList{})
+}
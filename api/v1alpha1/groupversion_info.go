@@ -1,6 +1,6 @@
 // Package v1alpha1 contains API Schema definitions for the  v1alpha1 API group
-//+kubebuilder:object:generate=true
-//+groupName=k8s.kevingomez.fr
+// +kubebuilder:object:generate=true
+// +groupName=k8s.kevingomez.fr
 package v1alpha1
 
 import (
@@ -7,7 +7,8 @@ package v1alpha1
 
 import (
 	"k8s.io/api/core/v1"
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -15,8 +16,8 @@ func (in *APIKey) DeepCopyInto(out *APIKey) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIKey.
@@ -69,9 +70,55 @@ func (in *APIKeyList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIKeySecret) DeepCopyInto(out *APIKeySecret) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIKeySecret.
+func (in *APIKeySecret) DeepCopy() *APIKeySecret {
+	if in == nil {
+		return nil
+	}
+	out := new(APIKeySecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIKeySpec) DeepCopyInto(out *APIKeySpec) {
 	*out = *in
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(APIKeySecret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstanceRef != nil {
+		in, out := &in.InstanceRef, &out.InstanceRef
+		*out = new(InstanceRef)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIKeySpec.
@@ -87,6 +134,27 @@ func (in *APIKeySpec) DeepCopy() *APIKeySpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIKeyStatus) DeepCopyInto(out *APIKeyStatus) {
 	*out = *in
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
+	if in.CreatedAt != nil {
+		in, out := &in.CreatedAt, &out.CreatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.NextRotationTime != nil {
+		in, out := &in.NextRotationTime, &out.NextRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PreviousKeyRevocationTime != nil {
+		in, out := &in.PreviousKeyRevocationTime, &out.PreviousKeyRevocationTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIKeyStatus.
@@ -99,13 +167,48 @@ func (in *APIKeyStatus) DeepCopy() *APIKeyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertCondition) DeepCopyInto(out *AlertCondition) {
+	*out = *in
+	if in.Above != nil {
+		in, out := &in.Above, &out.Above
+		*out = new(Number)
+		**out = **in
+	}
+	if in.Below != nil {
+		in, out := &in.Below, &out.Below
+		*out = new(Number)
+		**out = **in
+	}
+	if in.OutsideRange != nil {
+		in, out := &in.OutsideRange, &out.OutsideRange
+		*out = make([]Number, len(*in))
+		copy(*out, *in)
+	}
+	if in.WithinRange != nil {
+		in, out := &in.WithinRange, &out.WithinRange
+		*out = make([]Number, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertCondition.
+func (in *AlertCondition) DeepCopy() *AlertCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertManager) DeepCopyInto(out *AlertManager) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertManager.
@@ -187,6 +290,18 @@ func (in *AlertManagerSpec) DeepCopyInto(out *AlertManagerSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.MuteTimings != nil {
+		in, out := &in.MuteTimings, &out.MuteTimings
+		*out = make([]MuteTiming, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InstanceRef != nil {
+		in, out := &in.InstanceRef, &out.InstanceRef
+		*out = new(InstanceRef)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertManagerSpec.
@@ -202,6 +317,12 @@ func (in *AlertManagerSpec) DeepCopy() *AlertManagerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertManagerStatus) DeepCopyInto(out *AlertManagerStatus) {
 	*out = *in
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
+	if in.MuteTimings != nil {
+		in, out := &in.MuteTimings, &out.MuteTimings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertManagerStatus.
@@ -214,6 +335,94 @@ func (in *AlertManagerStatus) DeepCopy() *AlertManagerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertQuery) DeepCopyInto(out *AlertQuery) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertQuery.
+func (in *AlertQuery) DeepCopy() *AlertQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertRule) DeepCopyInto(out *AlertRule) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.If != nil {
+		in, out := &in.If, &out.If
+		*out = make([]AlertCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]AlertTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertRule.
+func (in *AlertRule) DeepCopy() *AlertRule {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertTarget) DeepCopyInto(out *AlertTarget) {
+	*out = *in
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(AlertQuery)
+		**out = **in
+	}
+	if in.Loki != nil {
+		in, out := &in.Loki, &out.Loki
+		*out = new(AlertQuery)
+		**out = **in
+	}
+	if in.Graphite != nil {
+		in, out := &in.Graphite, &out.Graphite
+		*out = new(AlertQuery)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertTarget.
+func (in *AlertTarget) DeepCopy() *AlertTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BasicAuth) DeepCopyInto(out *BasicAuth) {
 	*out = *in
@@ -346,6 +555,31 @@ func (in *ContactPointType) DeepCopyInto(out *ContactPointType) {
 		*out = new(DiscordContactType)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PagerDuty != nil {
+		in, out := &in.PagerDuty, &out.PagerDuty
+		*out = new(PagerDutyContactType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Teams != nil {
+		in, out := &in.Teams, &out.Teams
+		*out = new(TeamsContactType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(WebhookContactType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Telegram != nil {
+		in, out := &in.Telegram, &out.Telegram
+		*out = new(TelegramContactType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GoogleChat != nil {
+		in, out := &in.GoogleChat, &out.GoogleChat
+		*out = new(GoogleChatContactType)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContactPointType.
@@ -364,7 +598,7 @@ func (in *Datasource) DeepCopyInto(out *Datasource) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Datasource.
@@ -450,6 +684,41 @@ func (in *DatasourceSpec) DeepCopyInto(out *DatasourceSpec) {
 		*out = new(CloudWatchDatasource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.InfluxDB != nil {
+		in, out := &in.InfluxDB, &out.InfluxDB
+		*out = new(InfluxDBDatasource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Graphite != nil {
+		in, out := &in.Graphite, &out.Graphite
+		*out = new(GraphiteDatasource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Elasticsearch != nil {
+		in, out := &in.Elasticsearch, &out.Elasticsearch
+		*out = new(ElasticsearchDatasource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostgreSQL != nil {
+		in, out := &in.PostgreSQL, &out.PostgreSQL
+		*out = new(PostgreSQLDatasource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MySQL != nil {
+		in, out := &in.MySQL, &out.MySQL
+		*out = new(MySQLDatasource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Generic != nil {
+		in, out := &in.Generic, &out.Generic
+		*out = new(GenericDatasource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstanceRef != nil {
+		in, out := &in.InstanceRef, &out.InstanceRef
+		*out = new(InstanceRef)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatasourceSpec.
@@ -465,6 +734,7 @@ func (in *DatasourceSpec) DeepCopy() *DatasourceSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatasourceStatus) DeepCopyInto(out *DatasourceStatus) {
 	*out = *in
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatasourceStatus.
@@ -494,41 +764,17 @@ func (in *DiscordContactType) DeepCopy() *DiscordContactType {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EmailContactType) DeepCopyInto(out *EmailContactType) {
-	*out = *in
-	if in.To != nil {
-		in, out := &in.To, &out.To
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailContactType.
-func (in *EmailContactType) DeepCopy() *EmailContactType {
-	if in == nil {
-		return nil
-	}
-	out := new(EmailContactType)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JaegerDatasource) DeepCopyInto(out *JaegerDatasource) {
+func (in *ElasticsearchDatasource) DeepCopyInto(out *ElasticsearchDatasource) {
 	*out = *in
+	in.URL.DeepCopyInto(&out.URL)
 	if in.Default != nil {
 		in, out := &in.Default, &out.Default
 		*out = new(bool)
 		**out = **in
 	}
-	if in.ForwardOauth != nil {
-		in, out := &in.ForwardOauth, &out.ForwardOauth
-		*out = new(bool)
-		**out = **in
-	}
-	if in.ForwardCredentials != nil {
-		in, out := &in.ForwardCredentials, &out.ForwardCredentials
-		*out = new(bool)
+	if in.MaxConcurrentShardRequests != nil {
+		in, out := &in.MaxConcurrentShardRequests, &out.MaxConcurrentShardRequests
+		*out = new(int)
 		**out = **in
 	}
 	if in.SkipTLSVerify != nil {
@@ -536,11 +782,6 @@ func (in *JaegerDatasource) DeepCopyInto(out *JaegerDatasource) {
 		*out = new(bool)
 		**out = **in
 	}
-	if in.ForwardCookies != nil {
-		in, out := &in.ForwardCookies, &out.ForwardCookies
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 	if in.BasicAuth != nil {
 		in, out := &in.BasicAuth, &out.BasicAuth
 		*out = new(BasicAuth)
@@ -551,91 +792,1016 @@ func (in *JaegerDatasource) DeepCopyInto(out *JaegerDatasource) {
 		*out = new(ValueOrRef)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.NodeGraph != nil {
-		in, out := &in.NodeGraph, &out.NodeGraph
-		*out = new(bool)
-		**out = **in
-	}
-	if in.TraceToLogs != nil {
-		in, out := &in.TraceToLogs, &out.TraceToLogs
-		*out = new(TraceToLogs)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JaegerDatasource.
-func (in *JaegerDatasource) DeepCopy() *JaegerDatasource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchDatasource.
+func (in *ElasticsearchDatasource) DeepCopy() *ElasticsearchDatasource {
 	if in == nil {
 		return nil
 	}
-	out := new(JaegerDatasource)
+	out := new(ElasticsearchDatasource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LabelsMatchingRule) DeepCopyInto(out *LabelsMatchingRule) {
+func (in *EmailContactType) DeepCopyInto(out *EmailContactType) {
 	*out = *in
-	if in.Eq != nil {
-		in, out := &in.Eq, &out.Eq
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Neq != nil {
-		in, out := &in.Neq, &out.Neq
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Matches != nil {
-		in, out := &in.Matches, &out.Matches
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.NotMatches != nil {
-		in, out := &in.NotMatches, &out.NotMatches
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LabelsMatchingRule.
-func (in *LabelsMatchingRule) DeepCopy() *LabelsMatchingRule {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailContactType.
+func (in *EmailContactType) DeepCopy() *EmailContactType {
 	if in == nil {
 		return nil
 	}
-	out := new(LabelsMatchingRule)
+	out := new(EmailContactType)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LokiDatasource) DeepCopyInto(out *LokiDatasource) {
+func (in *ExternalKeySelector) DeepCopyInto(out *ExternalKeySelector) {
 	*out = *in
-	if in.Default != nil {
-		in, out := &in.Default, &out.Default
-		*out = new(bool)
-		**out = **in
-	}
-	if in.ForwardOauth != nil {
-		in, out := &in.ForwardOauth, &out.ForwardOauth
+	if in.Optional != nil {
+		in, out := &in.Optional, &out.Optional
 		*out = new(bool)
 		**out = **in
 	}
-	if in.ForwardCredentials != nil {
-		in, out := &in.ForwardCredentials, &out.ForwardCredentials
-		*out = new(bool)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalKeySelector.
+func (in *ExternalKeySelector) DeepCopy() *ExternalKeySelector {
+	if in == nil {
+		return nil
 	}
-	if in.SkipTLSVerify != nil {
-		in, out := &in.SkipTLSVerify, &out.SkipTLSVerify
+	out := new(ExternalKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FolderRef) DeepCopyInto(out *FolderRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FolderRef.
+func (in *FolderRef) DeepCopy() *FolderRef {
+	if in == nil {
+		return nil
+	}
+	out := new(FolderRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GenericDatasource) DeepCopyInto(out *GenericDatasource) {
+	*out = *in
+	if in.URL != nil {
+		in, out := &in.URL, &out.URL
+		*out = new(StringOrRef)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BasicAuth != nil {
+		in, out := &in.BasicAuth, &out.BasicAuth
+		*out = new(BasicAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JSONData != nil {
+		in, out := &in.JSONData, &out.JSONData
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecureJSONData != nil {
+		in, out := &in.SecureJSONData, &out.SecureJSONData
+		*out = make(map[string]ValueOrRef, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenericDatasource.
+func (in *GenericDatasource) DeepCopy() *GenericDatasource {
+	if in == nil {
+		return nil
+	}
+	out := new(GenericDatasource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GoogleChatContactType) DeepCopyInto(out *GoogleChatContactType) {
+	*out = *in
+	in.Webhook.DeepCopyInto(&out.Webhook)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleChatContactType.
+func (in *GoogleChatContactType) DeepCopy() *GoogleChatContactType {
+	if in == nil {
+		return nil
+	}
+	out := new(GoogleChatContactType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaAlertRuleGroup) DeepCopyInto(out *GrafanaAlertRuleGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaAlertRuleGroup.
+func (in *GrafanaAlertRuleGroup) DeepCopy() *GrafanaAlertRuleGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaAlertRuleGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaAlertRuleGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaAlertRuleGroupList) DeepCopyInto(out *GrafanaAlertRuleGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaAlertRuleGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaAlertRuleGroupList.
+func (in *GrafanaAlertRuleGroupList) DeepCopy() *GrafanaAlertRuleGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaAlertRuleGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaAlertRuleGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaAlertRuleGroupSpec) DeepCopyInto(out *GrafanaAlertRuleGroupSpec) {
+	*out = *in
+	if in.FolderRef != nil {
+		in, out := &in.FolderRef, &out.FolderRef
+		*out = new(FolderRef)
+		**out = **in
+	}
+	out.Datasource = in.Datasource
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]AlertRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InstanceRef != nil {
+		in, out := &in.InstanceRef, &out.InstanceRef
+		*out = new(InstanceRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaAlertRuleGroupSpec.
+func (in *GrafanaAlertRuleGroupSpec) DeepCopy() *GrafanaAlertRuleGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaAlertRuleGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaAlertRuleGroupStatus) DeepCopyInto(out *GrafanaAlertRuleGroupStatus) {
+	*out = *in
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaAlertRuleGroupStatus.
+func (in *GrafanaAlertRuleGroupStatus) DeepCopy() *GrafanaAlertRuleGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaAlertRuleGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaFolder) DeepCopyInto(out *GrafanaFolder) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaFolder.
+func (in *GrafanaFolder) DeepCopy() *GrafanaFolder {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaFolder)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaFolder) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaFolderList) DeepCopyInto(out *GrafanaFolderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaFolder, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaFolderList.
+func (in *GrafanaFolderList) DeepCopy() *GrafanaFolderList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaFolderList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaFolderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaFolderSpec) DeepCopyInto(out *GrafanaFolderSpec) {
+	*out = *in
+	if in.ParentRef != nil {
+		in, out := &in.ParentRef, &out.ParentRef
+		*out = new(FolderRef)
+		**out = **in
+	}
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]Permission, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InstanceRef != nil {
+		in, out := &in.InstanceRef, &out.InstanceRef
+		*out = new(InstanceRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaFolderSpec.
+func (in *GrafanaFolderSpec) DeepCopy() *GrafanaFolderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaFolderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaFolderStatus) DeepCopyInto(out *GrafanaFolderStatus) {
+	*out = *in
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaFolderStatus.
+func (in *GrafanaFolderStatus) DeepCopy() *GrafanaFolderStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaFolderStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaInstance) DeepCopyInto(out *GrafanaInstance) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaInstance.
+func (in *GrafanaInstance) DeepCopy() *GrafanaInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaInstance) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaInstanceList) DeepCopyInto(out *GrafanaInstanceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaInstance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaInstanceList.
+func (in *GrafanaInstanceList) DeepCopy() *GrafanaInstanceList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaInstanceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaInstanceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaInstanceSpec) DeepCopyInto(out *GrafanaInstanceSpec) {
+	*out = *in
+	if in.APIKey != nil {
+		in, out := &in.APIKey, &out.APIKey
+		*out = new(ValueOrRef)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BasicAuth != nil {
+		in, out := &in.BasicAuth, &out.BasicAuth
+		*out = new(BasicAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(GrafanaInstanceTLS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaInstanceSpec.
+func (in *GrafanaInstanceSpec) DeepCopy() *GrafanaInstanceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaInstanceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaInstanceStatus) DeepCopyInto(out *GrafanaInstanceStatus) {
+	*out = *in
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaInstanceStatus.
+func (in *GrafanaInstanceStatus) DeepCopy() *GrafanaInstanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaInstanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaInstanceTLS) DeepCopyInto(out *GrafanaInstanceTLS) {
+	*out = *in
+	if in.SkipTLSVerify != nil {
+		in, out := &in.SkipTLSVerify, &out.SkipTLSVerify
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CACertificate != nil {
+		in, out := &in.CACertificate, &out.CACertificate
+		*out = new(ValueOrRef)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaInstanceTLS.
+func (in *GrafanaInstanceTLS) DeepCopy() *GrafanaInstanceTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaInstanceTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaSilence) DeepCopyInto(out *GrafanaSilence) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaSilence.
+func (in *GrafanaSilence) DeepCopy() *GrafanaSilence {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaSilence)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaSilence) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaSilenceList) DeepCopyInto(out *GrafanaSilenceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaSilence, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaSilenceList.
+func (in *GrafanaSilenceList) DeepCopy() *GrafanaSilenceList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaSilenceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaSilenceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaSilenceSpec) DeepCopyInto(out *GrafanaSilenceSpec) {
+	*out = *in
+	if in.Matchers != nil {
+		in, out := &in.Matchers, &out.Matchers
+		*out = make([]LabelsMatchingRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StartsAt != nil {
+		in, out := &in.StartsAt, &out.StartsAt
+		*out = (*in).DeepCopy()
+	}
+	in.EndsAt.DeepCopyInto(&out.EndsAt)
+	if in.InstanceRef != nil {
+		in, out := &in.InstanceRef, &out.InstanceRef
+		*out = new(InstanceRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaSilenceSpec.
+func (in *GrafanaSilenceSpec) DeepCopy() *GrafanaSilenceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaSilenceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaSilenceStatus) DeepCopyInto(out *GrafanaSilenceStatus) {
+	*out = *in
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaSilenceStatus.
+func (in *GrafanaSilenceStatus) DeepCopy() *GrafanaSilenceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaSilenceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaTeam) DeepCopyInto(out *GrafanaTeam) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaTeam.
+func (in *GrafanaTeam) DeepCopy() *GrafanaTeam {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaTeam)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaTeam) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaTeamList) DeepCopyInto(out *GrafanaTeamList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaTeam, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaTeamList.
+func (in *GrafanaTeamList) DeepCopy() *GrafanaTeamList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaTeamList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaTeamList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaTeamSpec) DeepCopyInto(out *GrafanaTeamSpec) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Preferences != nil {
+		in, out := &in.Preferences, &out.Preferences
+		*out = new(TeamPreferences)
+		**out = **in
+	}
+	if in.InstanceRef != nil {
+		in, out := &in.InstanceRef, &out.InstanceRef
+		*out = new(InstanceRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaTeamSpec.
+func (in *GrafanaTeamSpec) DeepCopy() *GrafanaTeamSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaTeamSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaTeamStatus) DeepCopyInto(out *GrafanaTeamStatus) {
+	*out = *in
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaTeamStatus.
+func (in *GrafanaTeamStatus) DeepCopy() *GrafanaTeamStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaTeamStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaUser) DeepCopyInto(out *GrafanaUser) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaUser.
+func (in *GrafanaUser) DeepCopy() *GrafanaUser {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaUserList) DeepCopyInto(out *GrafanaUserList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrafanaUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaUserList.
+func (in *GrafanaUserList) DeepCopy() *GrafanaUserList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaUserList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrafanaUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaUserSpec) DeepCopyInto(out *GrafanaUserSpec) {
+	*out = *in
+	in.Password.DeepCopyInto(&out.Password)
+	if in.InstanceRef != nil {
+		in, out := &in.InstanceRef, &out.InstanceRef
+		*out = new(InstanceRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaUserSpec.
+func (in *GrafanaUserSpec) DeepCopy() *GrafanaUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaUserStatus) DeepCopyInto(out *GrafanaUserStatus) {
+	*out = *in
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaUserStatus.
+func (in *GrafanaUserStatus) DeepCopy() *GrafanaUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GraphiteDatasource) DeepCopyInto(out *GraphiteDatasource) {
+	*out = *in
+	in.URL.DeepCopyInto(&out.URL)
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SkipTLSVerify != nil {
+		in, out := &in.SkipTLSVerify, &out.SkipTLSVerify
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BasicAuth != nil {
+		in, out := &in.BasicAuth, &out.BasicAuth
+		*out = new(BasicAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CACertificate != nil {
+		in, out := &in.CACertificate, &out.CACertificate
+		*out = new(ValueOrRef)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GraphiteDatasource.
+func (in *GraphiteDatasource) DeepCopy() *GraphiteDatasource {
+	if in == nil {
+		return nil
+	}
+	out := new(GraphiteDatasource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InfluxDBDatasource) DeepCopyInto(out *InfluxDBDatasource) {
+	*out = *in
+	in.URL.DeepCopyInto(&out.URL)
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(bool)
+		**out = **in
+	}
+	if in.User != nil {
+		in, out := &in.User, &out.User
+		*out = new(ValueOrRef)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(ValueOrRef)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Token != nil {
+		in, out := &in.Token, &out.Token
+		*out = new(ValueOrRef)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SkipTLSVerify != nil {
+		in, out := &in.SkipTLSVerify, &out.SkipTLSVerify
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BasicAuth != nil {
+		in, out := &in.BasicAuth, &out.BasicAuth
+		*out = new(BasicAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CACertificate != nil {
+		in, out := &in.CACertificate, &out.CACertificate
+		*out = new(ValueOrRef)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfluxDBDatasource.
+func (in *InfluxDBDatasource) DeepCopy() *InfluxDBDatasource {
+	if in == nil {
+		return nil
+	}
+	out := new(InfluxDBDatasource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceRef) DeepCopyInto(out *InstanceRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceRef.
+func (in *InstanceRef) DeepCopy() *InstanceRef {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JaegerDatasource) DeepCopyInto(out *JaegerDatasource) {
+	*out = *in
+	in.URL.DeepCopyInto(&out.URL)
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ForwardOauth != nil {
+		in, out := &in.ForwardOauth, &out.ForwardOauth
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ForwardCredentials != nil {
+		in, out := &in.ForwardCredentials, &out.ForwardCredentials
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SkipTLSVerify != nil {
+		in, out := &in.SkipTLSVerify, &out.SkipTLSVerify
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ForwardCookies != nil {
+		in, out := &in.ForwardCookies, &out.ForwardCookies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BasicAuth != nil {
+		in, out := &in.BasicAuth, &out.BasicAuth
+		*out = new(BasicAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CACertificate != nil {
+		in, out := &in.CACertificate, &out.CACertificate
+		*out = new(ValueOrRef)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeGraph != nil {
+		in, out := &in.NodeGraph, &out.NodeGraph
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TraceToLogs != nil {
+		in, out := &in.TraceToLogs, &out.TraceToLogs
+		*out = new(TraceToLogs)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JaegerDatasource.
+func (in *JaegerDatasource) DeepCopy() *JaegerDatasource {
+	if in == nil {
+		return nil
+	}
+	out := new(JaegerDatasource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LabelsMatchingRule) DeepCopyInto(out *LabelsMatchingRule) {
+	*out = *in
+	if in.Eq != nil {
+		in, out := &in.Eq, &out.Eq
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Neq != nil {
+		in, out := &in.Neq, &out.Neq
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Matches != nil {
+		in, out := &in.Matches, &out.Matches
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NotMatches != nil {
+		in, out := &in.NotMatches, &out.NotMatches
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LabelsMatchingRule.
+func (in *LabelsMatchingRule) DeepCopy() *LabelsMatchingRule {
+	if in == nil {
+		return nil
+	}
+	out := new(LabelsMatchingRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LokiDatasource) DeepCopyInto(out *LokiDatasource) {
+	*out = *in
+	in.URL.DeepCopyInto(&out.URL)
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ForwardOauth != nil {
+		in, out := &in.ForwardOauth, &out.ForwardOauth
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ForwardCredentials != nil {
+		in, out := &in.ForwardCredentials, &out.ForwardCredentials
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SkipTLSVerify != nil {
+		in, out := &in.SkipTLSVerify, &out.SkipTLSVerify
 		*out = new(bool)
 		**out = **in
 	}
@@ -698,9 +1864,84 @@ func (in *LokiDerivedField) DeepCopy() *LokiDerivedField {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MuteTiming) DeepCopyInto(out *MuteTiming) {
+	*out = *in
+	if in.TimeIntervals != nil {
+		in, out := &in.TimeIntervals, &out.TimeIntervals
+		*out = make([]TimeInterval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MuteTiming.
+func (in *MuteTiming) DeepCopy() *MuteTiming {
+	if in == nil {
+		return nil
+	}
+	out := new(MuteTiming)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MySQLDatasource) DeepCopyInto(out *MySQLDatasource) {
+	*out = *in
+	in.URL.DeepCopyInto(&out.URL)
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(bool)
+		**out = **in
+	}
+	in.User.DeepCopyInto(&out.User)
+	in.Password.DeepCopyInto(&out.Password)
+	if in.MaxOpenConnections != nil {
+		in, out := &in.MaxOpenConnections, &out.MaxOpenConnections
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxIdleConnections != nil {
+		in, out := &in.MaxIdleConnections, &out.MaxIdleConnections
+		*out = new(int)
+		**out = **in
+	}
+	if in.ConnectionMaxLifetime != nil {
+		in, out := &in.ConnectionMaxLifetime, &out.ConnectionMaxLifetime
+		*out = new(int)
+		**out = **in
+	}
+	if in.SkipTLSVerify != nil {
+		in, out := &in.SkipTLSVerify, &out.SkipTLSVerify
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CACertificate != nil {
+		in, out := &in.CACertificate, &out.CACertificate
+		*out = new(ValueOrRef)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MySQLDatasource.
+func (in *MySQLDatasource) DeepCopy() *MySQLDatasource {
+	if in == nil {
+		return nil
+	}
+	out := new(MySQLDatasource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpsgenieContactType) DeepCopyInto(out *OpsgenieContactType) {
 	*out = *in
+	if in.APIURL != nil {
+		in, out := &in.APIURL, &out.APIURL
+		*out = new(StringOrRef)
+		(*in).DeepCopyInto(*out)
+	}
 	in.APIKey.DeepCopyInto(&out.APIKey)
 }
 
@@ -714,9 +1955,94 @@ func (in *OpsgenieContactType) DeepCopy() *OpsgenieContactType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDutyContactType) DeepCopyInto(out *PagerDutyContactType) {
+	*out = *in
+	in.IntegrationKey.DeepCopyInto(&out.IntegrationKey)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyContactType.
+func (in *PagerDutyContactType) DeepCopy() *PagerDutyContactType {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDutyContactType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Permission) DeepCopyInto(out *Permission) {
+	*out = *in
+	if in.TeamRef != nil {
+		in, out := &in.TeamRef, &out.TeamRef
+		*out = new(TeamRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Permission.
+func (in *Permission) DeepCopy() *Permission {
+	if in == nil {
+		return nil
+	}
+	out := new(Permission)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgreSQLDatasource) DeepCopyInto(out *PostgreSQLDatasource) {
+	*out = *in
+	in.URL.DeepCopyInto(&out.URL)
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(bool)
+		**out = **in
+	}
+	in.User.DeepCopyInto(&out.User)
+	in.Password.DeepCopyInto(&out.Password)
+	if in.MaxOpenConnections != nil {
+		in, out := &in.MaxOpenConnections, &out.MaxOpenConnections
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxIdleConnections != nil {
+		in, out := &in.MaxIdleConnections, &out.MaxIdleConnections
+		*out = new(int)
+		**out = **in
+	}
+	if in.ConnectionMaxLifetime != nil {
+		in, out := &in.ConnectionMaxLifetime, &out.ConnectionMaxLifetime
+		*out = new(int)
+		**out = **in
+	}
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(int)
+		**out = **in
+	}
+	if in.TimescaleDB != nil {
+		in, out := &in.TimescaleDB, &out.TimescaleDB
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgreSQLDatasource.
+func (in *PostgreSQLDatasource) DeepCopy() *PostgreSQLDatasource {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgreSQLDatasource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PrometheusDatasource) DeepCopyInto(out *PrometheusDatasource) {
 	*out = *in
+	in.URL.DeepCopyInto(&out.URL)
 	if in.Default != nil {
 		in, out := &in.Default, &out.Default
 		*out = new(bool)
@@ -801,6 +2127,23 @@ func (in *RoutingPolicy) DeepCopyInto(out *RoutingPolicy) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.GroupBy != nil {
+		in, out := &in.GroupBy, &out.GroupBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MuteTimings != nil {
+		in, out := &in.MuteTimings, &out.MuteTimings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Routing != nil {
+		in, out := &in.Routing, &out.Routing
+		*out = make([]RoutingPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoutingPolicy.
@@ -854,9 +2197,114 @@ func (in *StackdriverDatasource) DeepCopy() *StackdriverDatasource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StringOrRef) DeepCopyInto(out *StringOrRef) {
+	*out = *in
+	in.ValueOrRef.DeepCopyInto(&out.ValueOrRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StringOrRef.
+func (in *StringOrRef) DeepCopy() *StringOrRef {
+	if in == nil {
+		return nil
+	}
+	out := new(StringOrRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncStatus) DeepCopyInto(out *SyncStatus) {
+	*out = *in
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncStatus.
+func (in *SyncStatus) DeepCopy() *SyncStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TeamPreferences) DeepCopyInto(out *TeamPreferences) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamPreferences.
+func (in *TeamPreferences) DeepCopy() *TeamPreferences {
+	if in == nil {
+		return nil
+	}
+	out := new(TeamPreferences)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TeamRef) DeepCopyInto(out *TeamRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamRef.
+func (in *TeamRef) DeepCopy() *TeamRef {
+	if in == nil {
+		return nil
+	}
+	out := new(TeamRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TeamsContactType) DeepCopyInto(out *TeamsContactType) {
+	*out = *in
+	in.Webhook.DeepCopyInto(&out.Webhook)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamsContactType.
+func (in *TeamsContactType) DeepCopy() *TeamsContactType {
+	if in == nil {
+		return nil
+	}
+	out := new(TeamsContactType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TelegramContactType) DeepCopyInto(out *TelegramContactType) {
+	*out = *in
+	in.BotToken.DeepCopyInto(&out.BotToken)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TelegramContactType.
+func (in *TelegramContactType) DeepCopy() *TelegramContactType {
+	if in == nil {
+		return nil
+	}
+	out := new(TelegramContactType)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TempoDatasource) DeepCopyInto(out *TempoDatasource) {
 	*out = *in
+	in.URL.DeepCopyInto(&out.URL)
 	if in.Default != nil {
 		in, out := &in.Default, &out.Default
 		*out = new(bool)
@@ -914,6 +2362,61 @@ func (in *TempoDatasource) DeepCopy() *TempoDatasource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeInterval) DeepCopyInto(out *TimeInterval) {
+	*out = *in
+	if in.Times != nil {
+		in, out := &in.Times, &out.Times
+		*out = make([]TimeRange, len(*in))
+		copy(*out, *in)
+	}
+	if in.Weekdays != nil {
+		in, out := &in.Weekdays, &out.Weekdays
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DaysOfMonth != nil {
+		in, out := &in.DaysOfMonth, &out.DaysOfMonth
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Months != nil {
+		in, out := &in.Months, &out.Months
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Years != nil {
+		in, out := &in.Years, &out.Years
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeInterval.
+func (in *TimeInterval) DeepCopy() *TimeInterval {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeInterval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeRange) DeepCopyInto(out *TimeRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeRange.
+func (in *TimeRange) DeepCopy() *TimeRange {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TraceToLogs) DeepCopyInto(out *TraceToLogs) {
 	*out = *in
@@ -988,6 +2491,16 @@ func (in *ValueRef) DeepCopyInto(out *ValueRef) {
 		*out = new(v1.SecretKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalKeyRef != nil {
+		in, out := &in.ExternalKeyRef, &out.ExternalKeyRef
+		*out = new(ExternalKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValueRef.
@@ -999,3 +2512,29 @@ func (in *ValueRef) DeepCopy() *ValueRef {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookContactType) DeepCopyInto(out *WebhookContactType) {
+	*out = *in
+	in.URL.DeepCopyInto(&out.URL)
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(ValueOrRef)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthorizationCredentials != nil {
+		in, out := &in.AuthorizationCredentials, &out.AuthorizationCredentials
+		*out = new(ValueOrRef)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookContactType.
+func (in *WebhookContactType) DeepCopy() *WebhookContactType {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookContactType)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -1,10 +1,11 @@
 package main
 
 import (
-	"crypto/tls"
+	"errors"
 	"flag"
-	"net/http"
+	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -14,18 +15,29 @@ import (
 	k8skevingomezfrv1 "github.com/K-Phoen/dark/api/v1"
 	k8skevingomezfrv1alpha1 "github.com/K-Phoen/dark/api/v1alpha1"
 	"github.com/K-Phoen/dark/internal/pkg/controllers"
-	"github.com/K-Phoen/grabana"
+	"github.com/K-Phoen/dark/internal/pkg/grafana"
+	"github.com/K-Phoen/dark/internal/pkg/kubernetes"
+	"github.com/K-Phoen/dark/internal/pkg/metrics"
+	"github.com/K-Phoen/dark/internal/pkg/secretbackends"
+	"github.com/K-Phoen/dark/internal/pkg/webhooks"
+	"github.com/go-logr/logr"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	//+kubebuilder:scaffold:imports
 )
 
+var errInvalidPrometheusRulesDatasource = errors.New("prometheus-rules-datasource must be given as namespace/name")
+
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
@@ -47,6 +59,23 @@ func main() {
 	var grafanaHost string
 	var grafanaToken string
 	var insecureSkipVerify bool
+	var dashboardsResyncInterval time.Duration
+	var dashboardsDriftReportOnly bool
+	var enableWebhooks bool
+	var vaultAddress string
+	var vaultToken string
+	var vaultKubernetesRole string
+	var vaultKubernetesAuthPath string
+	var vaultMount string
+	var vaultPathPrefix string
+	var vaultCacheTTL time.Duration
+	var secretsFilesRoot string
+	var secretsFilesPathPrefix string
+	var secretsFilesCacheTTL time.Duration
+	var importPrometheusRules bool
+	var prometheusRulesSelector string
+	var prometheusRulesDatasource string
+	var prometheusRulesFolder string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
@@ -55,6 +84,23 @@ func main() {
 	flag.StringVar(&grafanaHost, "grafana-host", "http://localhost:3000", "The host to use to reach Grafana.")
 	flag.StringVar(&grafanaToken, "grafana-api-key", "", "The API key to use to authenticate to Grafana.")
 	flag.BoolVar(&insecureSkipVerify, "insecure-skip-verify", false, "Skips SSL certificates verification. Useful when self-signed certificates are used, but can be insecure. Enabled at your own risks.")
+	flag.DurationVar(&dashboardsResyncInterval, "dashboards-resync-interval", 0, "Interval at which dashboards are compared with Grafana to detect and correct drifts. Zero disables drift detection.")
+	flag.BoolVar(&dashboardsDriftReportOnly, "dashboards-drift-report-only", false, "Only report drifted dashboards, without overwriting them.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false, "Enables the validating admission webhooks. Requires a TLS certificate to be mounted in /tmp/k8s-webhook-server/serving-certs.")
+	flag.StringVar(&vaultAddress, "vault-address", "", "Address of the Vault server used as \"vault\" external secret backend. Empty disables the backend.")
+	flag.StringVar(&vaultToken, "vault-token", "", "Token used to authenticate to Vault.")
+	flag.StringVar(&vaultKubernetesRole, "vault-kubernetes-role", "", "Role used to authenticate to Vault with the Kubernetes auth method, when no token is given.")
+	flag.StringVar(&vaultKubernetesAuthPath, "vault-kubernetes-auth-path", "kubernetes", "Mount path of the Kubernetes auth method in Vault.")
+	flag.StringVar(&vaultMount, "vault-kv-mount", "secret", "Mount path of the KV version 2 secrets engine in Vault.")
+	flag.StringVar(&vaultPathPrefix, "vault-path-prefix", secretbackends.NamespacePlaceholder, "Prefix of the paths read from Vault. "+secretbackends.NamespacePlaceholder+" is replaced by the namespace of the object reading the secret.")
+	flag.DurationVar(&vaultCacheTTL, "vault-cache-ttl", time.Minute, "Duration during which secrets read from Vault are cached. Zero disables caching.")
+	flag.StringVar(&secretsFilesRoot, "secrets-files-root", "", "Directory holding the files used as \"file\" external secret backend. Empty disables the backend.")
+	flag.StringVar(&secretsFilesPathPrefix, "secrets-files-path-prefix", secretbackends.NamespacePlaceholder, "Prefix of the paths read from the files backend. "+secretbackends.NamespacePlaceholder+" is replaced by the namespace of the object reading the secret.")
+	flag.DurationVar(&secretsFilesCacheTTL, "secrets-files-cache-ttl", 10*time.Second, "Duration during which secrets read from files are cached. Zero disables caching.")
+	flag.BoolVar(&importPrometheusRules, "import-prometheus-rules", false, "Imports prometheus-operator PrometheusRule objects as Grafana alert rules. Requires the PrometheusRule CRD to be installed.")
+	flag.StringVar(&prometheusRulesSelector, "prometheus-rules-selector", "", "Label selector restricting the imported PrometheusRule objects. Empty selects all of them.")
+	flag.StringVar(&prometheusRulesDatasource, "prometheus-rules-datasource", "", "Prometheus Datasource queried by the imported rules, as namespace/name.")
+	flag.StringVar(&prometheusRulesFolder, "prometheus-rules-folder", "", "Folder holding the imported rules. Defaults to the namespace of each PrometheusRule.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -63,6 +109,16 @@ func main() {
 	must(viper.BindEnv("grafana-host", "GRAFANA_HOST"))
 	must(viper.BindEnv("grafana-token", "GRAFANA_TOKEN"))
 	must(viper.BindEnv("insecure-skip-verify", "INSECURE_SKIP_VERIFY"))
+	must(viper.BindEnv("dashboards-resync-interval", "DASHBOARDS_RESYNC_INTERVAL"))
+	must(viper.BindEnv("dashboards-drift-report-only", "DASHBOARDS_DRIFT_REPORT_ONLY"))
+	must(viper.BindEnv("enable-webhooks", "ENABLE_WEBHOOKS"))
+	must(viper.BindEnv("vault-address", "VAULT_ADDR"))
+	must(viper.BindEnv("vault-token", "VAULT_TOKEN"))
+	must(viper.BindEnv("vault-kubernetes-role", "VAULT_KUBERNETES_ROLE"))
+	must(viper.BindEnv("secrets-files-root", "SECRETS_FILES_ROOT"))
+	must(viper.BindEnv("import-prometheus-rules", "IMPORT_PROMETHEUS_RULES"))
+	must(viper.BindEnv("prometheus-rules-selector", "PROMETHEUS_RULES_SELECTOR"))
+	must(viper.BindEnv("prometheus-rules-datasource", "PROMETHEUS_RULES_DATASOURCE"))
 
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
 	pflag.Parse()
@@ -71,15 +127,16 @@ func main() {
 	logger := zap.New(zap.UseFlagOptions(&opts))
 	ctrl.SetLogger(logger)
 
-	httpClient := makeHTTPClient(&tls.Config{
-		//nolint:gosec
-		InsecureSkipVerify: viper.GetBool("insecure-skip-verify"),
+	// default Grafana client, used by objects not referencing a GrafanaInstance
+	grabanaClient, err := grafana.NewClient(grafana.ClientConfig{
+		Host:          viper.GetString("grafana-host"),
+		APIToken:      viper.GetString("grafana-token"),
+		SkipTLSVerify: viper.GetBool("insecure-skip-verify"),
 	})
-	grabanaClient := grabana.NewClient(
-		httpClient,
-		viper.GetString("grafana-host"),
-		grabana.WithAPIToken(viper.GetString("grafana-token")),
-	)
+	if err != nil {
+		setupLog.Error(err, "unable to create Grafana client")
+		os.Exit(1)
+	}
 
 	// controllers setup
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
@@ -96,24 +153,129 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = controllers.StartGrafanaDashboardReconciler(mgr, grabanaClient); err != nil {
+	// external secret backends
+	externalSecrets := secretbackends.NewReader(logger.WithName("secretbackends"))
+	if address := viper.GetString("vault-address"); address != "" {
+		vault, err := secretbackends.NewVaultBackend(secretbackends.VaultConfig{
+			Address:            address,
+			Mount:              viper.GetString("vault-kv-mount"),
+			Token:              viper.GetString("vault-token"),
+			KubernetesRole:     viper.GetString("vault-kubernetes-role"),
+			KubernetesAuthPath: viper.GetString("vault-kubernetes-auth-path"),
+		})
+		if err != nil {
+			setupLog.Error(err, "unable to create Vault secret backend")
+			os.Exit(1)
+		}
+
+		externalSecrets.Register("vault", vault, viper.GetString("vault-path-prefix"), viper.GetDuration("vault-cache-ttl"))
+	}
+	if root := viper.GetString("secrets-files-root"); root != "" {
+		externalSecrets.Register("file", secretbackends.NewFileBackend(root), viper.GetString("secrets-files-path-prefix"), viper.GetDuration("secrets-files-cache-ttl"))
+	}
+
+	refReader := kubernetes.NewValueRefReader(logger, kubernetes.NewSecrets(logger, mgr.GetClient()), kubernetes.NewConfigMaps(logger, mgr.GetClient()), externalSecrets)
+	instances := grafana.NewInstances(logger, mgr.GetClient(), refReader, grabanaClient)
+
+	if err = controllers.StartGrafanaInstanceReconciler(mgr, instances); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaInstance")
+		os.Exit(1)
+	}
+	if err = controllers.StartGrafanaDashboardReconciler(mgr, instances, controllers.DriftDetection{
+		ResyncInterval: viper.GetDuration("dashboards-resync-interval"),
+		ReportOnly:     viper.GetBool("dashboards-drift-report-only"),
+	}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "GrafanaDashboard")
 		os.Exit(1)
 	}
-	if err = controllers.StartDatasourceReconciler(logger, mgr, grabanaClient); err != nil {
+	if err = controllers.StartGrafanaFolderReconciler(logger, mgr, instances); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaFolder")
+		os.Exit(1)
+	}
+	if err = controllers.StartGrafanaTeamReconciler(logger, mgr, instances); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaTeam")
+		os.Exit(1)
+	}
+	if err = controllers.StartGrafanaUserReconciler(logger, mgr, instances, refReader); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaUser")
+		os.Exit(1)
+	}
+	if err = controllers.StartDatasourceReconciler(logger, mgr, instances, refReader); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Datasource")
 		os.Exit(1)
 	}
-	if err = controllers.StartAPIKeyReconciler(logger, mgr, grabanaClient); err != nil {
+	if err = controllers.StartAPIKeyReconciler(logger, mgr, instances); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "APIKey")
 		os.Exit(1)
 	}
-	if err = controllers.StartAlertManagerReconciler(logger, mgr, grabanaClient); err != nil {
+	if err = controllers.StartAlertManagerReconciler(logger, mgr, instances, refReader); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AlertManager")
 		os.Exit(1)
 	}
+	if err = controllers.StartGrafanaAlertRuleGroupReconciler(logger, mgr, instances); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaAlertRuleGroup")
+		os.Exit(1)
+	}
+	if err = controllers.StartGrafanaSilenceReconciler(logger, mgr, instances); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GrafanaSilence")
+		os.Exit(1)
+	}
+	if viper.GetBool("import-prometheus-rules") {
+		if err = startPrometheusRulesImport(logger, mgr, instances); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "PrometheusRule")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
+	// webhooks setup
+	if viper.GetBool("enable-webhooks") {
+		if err = webhooks.SetupGrafanaDashboardWebhook(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "GrafanaDashboard")
+			os.Exit(1)
+		}
+		if err = webhooks.SetupGrafanaFolderWebhook(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "GrafanaFolder")
+			os.Exit(1)
+		}
+		if err = webhooks.SetupDatasourceWebhook(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Datasource")
+			os.Exit(1)
+		}
+		if err = webhooks.SetupAPIKeyWebhook(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "APIKey")
+			os.Exit(1)
+		}
+		if err = webhooks.SetupAlertManagerWebhook(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "AlertManager")
+			os.Exit(1)
+		}
+		if err = webhooks.SetupGrafanaAlertRuleGroupWebhook(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "GrafanaAlertRuleGroup")
+			os.Exit(1)
+		}
+		if err = webhooks.SetupGrafanaSilenceWebhook(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "GrafanaSilence")
+			os.Exit(1)
+		}
+	}
+
+	// metrics
+	ctrlmetrics.Registry.MustRegister(metrics.NewManagedObjectsCollector(
+		logger.WithName("metrics"),
+		mgr.GetClient(),
+		metrics.ManagedKind{Kind: "GrafanaDashboard", List: func() client.ObjectList { return &k8skevingomezfrv1.GrafanaDashboardList{} }},
+		metrics.ManagedKind{Kind: "GrafanaFolder", List: func() client.ObjectList { return &k8skevingomezfrv1alpha1.GrafanaFolderList{} }},
+		metrics.ManagedKind{Kind: "GrafanaTeam", List: func() client.ObjectList { return &k8skevingomezfrv1alpha1.GrafanaTeamList{} }},
+		metrics.ManagedKind{Kind: "GrafanaUser", List: func() client.ObjectList { return &k8skevingomezfrv1alpha1.GrafanaUserList{} }},
+		metrics.ManagedKind{Kind: "Datasource", List: func() client.ObjectList { return &k8skevingomezfrv1alpha1.DatasourceList{} }},
+		metrics.ManagedKind{Kind: "APIKey", List: func() client.ObjectList { return &k8skevingomezfrv1alpha1.APIKeyList{} }},
+		metrics.ManagedKind{Kind: "AlertManager", List: func() client.ObjectList { return &k8skevingomezfrv1alpha1.AlertManagerList{} }},
+		metrics.ManagedKind{Kind: "GrafanaAlertRuleGroup", List: func() client.ObjectList { return &k8skevingomezfrv1alpha1.GrafanaAlertRuleGroupList{} }},
+		metrics.ManagedKind{Kind: "GrafanaSilence", List: func() client.ObjectList { return &k8skevingomezfrv1alpha1.GrafanaSilenceList{} }},
+		metrics.ManagedKind{Kind: "GrafanaInstance", List: func() client.ObjectList { return &k8skevingomezfrv1alpha1.GrafanaInstanceList{} }},
+	))
+
 	// liveness and readiness probes
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
@@ -132,18 +294,28 @@ func main() {
 	}
 }
 
-func must(err error) {
+// startPrometheusRulesImport starts the controller importing PrometheusRule
+// objects, as configured by the command line.
+func startPrometheusRulesImport(logger logr.Logger, mgr ctrl.Manager, instances *grafana.Instances) error {
+	selector, err := labels.Parse(viper.GetString("prometheus-rules-selector"))
 	if err != nil {
-		setupLog.Error(err, "")
-		os.Exit(1)
+		return fmt.Errorf("invalid prometheus-rules-selector: %w", err)
+	}
+
+	namespace, name, found := strings.Cut(viper.GetString("prometheus-rules-datasource"), "/")
+	if !found || namespace == "" || name == "" {
+		return errInvalidPrometheusRulesDatasource
 	}
+
+	return controllers.StartPrometheusRuleReconciler(logger, mgr, instances, selector, grafana.PrometheusRulesImport{
+		Datasource: types.NamespacedName{Namespace: namespace, Name: name},
+		Folder:     viper.GetString("prometheus-rules-folder"),
+	})
 }
 
-func makeHTTPClient(tlsConfig *tls.Config) *http.Client {
-	return &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: tlsConfig,
-		},
-		Timeout: 10 * time.Second, // Large, but better than no timeout.
+func must(err error) {
+	if err != nil {
+		setupLog.Error(err, "")
+		os.Exit(1)
 	}
 }
@@ -2,19 +2,23 @@ package controllers
 
 import (
 	"context"
+	"errors"
 
 	"github.com/K-Phoen/dark/api/v1alpha1"
 	"github.com/K-Phoen/dark/internal/pkg/grafana"
 	"github.com/K-Phoen/dark/internal/pkg/kubernetes"
-	"github.com/K-Phoen/grabana"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const alertManagerFinalizerName = "alertmanagers.k8s.kevingomez.fr/finalizer"
@@ -26,12 +30,15 @@ type AlertManagerReconciler struct {
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
 
-	alertManager *grafana.AlertManager
+	instances    grafanaInstances
+	alertManager func(grafanaClient *grafana.Client) *grafana.AlertManager
 }
 
 //+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=alertmanagers,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=alertmanagers/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=alertmanagers/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -56,6 +63,21 @@ func (r *AlertManagerReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	grafanaClient, err := r.instances.ClientFor(ctx, alertManagerManifest, alertManagerManifest.Spec.InstanceRef)
+	if err != nil {
+		logger.Error(err, "unable to resolve Grafana instance")
+
+		if isInstanceGone(err) && !alertManagerManifest.ObjectMeta.DeletionTimestamp.IsZero() {
+			return ctrl.Result{}, releaseFinalizer(ctx, r.Client, r.Recorder, alertManagerManifest, alertManagerFinalizerName)
+		}
+
+		statusErr := updateStatus(ctx, r.Client, alertManagerManifest, err)
+		r.Recorder.Event(alertManagerManifest, "Warning", "Error", "could not resolve Grafana instance")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+	alertManager := r.alertManager(grafanaClient)
+
 	// examine DeletionTimestamp to determine if object is under deletion
 	if alertManagerManifest.ObjectMeta.DeletionTimestamp.IsZero() {
 		// The object is not being deleted, so if it does not have our finalizer,
@@ -75,7 +97,7 @@ func (r *AlertManagerReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			logger.Info("finalizer found, deleting AlertManager config from grafana")
 
 			// our finalizer is present, so lets handle any external dependency
-			if err := r.alertManager.Reset(ctx); err != nil {
+			if err := alertManager.Reset(ctx, *alertManagerManifest); err != nil {
 				// if fail to delete the external dependency here, return with error
 				// so that it can be retried
 				return ctrl.Result{}, err
@@ -93,35 +115,44 @@ func (r *AlertManagerReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	// handle actual reconciliation
-	return r.doReconcileManifest(ctx, alertManagerManifest)
+	return r.doReconcileManifest(ctx, alertManager, alertManagerManifest)
 }
 
-func (r *AlertManagerReconciler) doReconcileManifest(ctx context.Context, manifest *v1alpha1.AlertManager) (ctrl.Result, error) {
+func (r *AlertManagerReconciler) doReconcileManifest(ctx context.Context, alertManager *grafana.AlertManager, manifest *v1alpha1.AlertManager) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
-	if err := r.alertManager.Configure(ctx, *manifest); err != nil {
+	if err := alertManager.Configure(ctx, *manifest); err != nil {
 		logger.Info("failed reconciling AlertManager")
 
-		r.updateStatus(ctx, manifest, err)
+		statusErr := updateStatus(ctx, r.Client, manifest, err)
 		r.Recorder.Event(manifest, "Warning", "Error", "could not reconcile AlertManager with Grafana")
 
-		return ctrl.Result{}, err
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+
+	manifestWithStatus := manifest.DeepCopy()
+	manifestWithStatus.Status.MuteTimings = nil
+	for _, muteTiming := range manifest.Spec.MuteTimings {
+		manifestWithStatus.Status.MuteTimings = append(manifestWithStatus.Status.MuteTimings, muteTiming.Name)
 	}
 
-	r.updateStatus(ctx, manifest, nil)
+	if err := updateStatus(ctx, r.Client, manifestWithStatus, nil); err != nil {
+		return ctrl.Result{}, err
+	}
 	r.Recorder.Event(manifest, "Normal", "Synchronized", "AlertManager reconciled")
 
 	return ctrl.Result{}, nil
 }
 
-func StartAlertManagerReconciler(logger logr.Logger, ctrlManager ctrl.Manager, grabanaClient *grabana.Client) error {
-	refReader := kubernetes.NewValueRefReader(logger, kubernetes.NewSecrets(logger, ctrlManager.GetClient()))
-
+func StartAlertManagerReconciler(logger logr.Logger, ctrlManager ctrl.Manager, instances *grafana.Instances, refReader *kubernetes.ValueRefReader) error {
 	reconciler := &AlertManagerReconciler{
-		Client:       ctrlManager.GetClient(),
-		Scheme:       ctrlManager.GetScheme(),
-		Recorder:     ctrlManager.GetEventRecorderFor("alertmanager-controller"),
-		alertManager: grafana.NewAlertManager(logger, grabanaClient, refReader),
+		Client:    ctrlManager.GetClient(),
+		Scheme:    ctrlManager.GetScheme(),
+		Recorder:  ctrlManager.GetEventRecorderFor("alertmanager-controller"),
+		instances: instances,
+		alertManager: func(grafanaClient *grafana.Client) *grafana.AlertManager {
+			return grafana.NewAlertManager(logger, grafanaClient, refReader)
+		},
 	}
 
 	return reconciler.SetupWithManager(ctrlManager)
@@ -129,29 +160,19 @@ func StartAlertManagerReconciler(logger logr.Logger, ctrlManager ctrl.Manager, g
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *AlertManagerReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.AlertManager{}).
-		WithEventFilter(predicate.GenerationChangedPredicate{}).
-		Complete(r)
-}
-
-func (r *AlertManagerReconciler) updateStatus(ctx context.Context, manifest *v1alpha1.AlertManager, err error) {
-	logger := log.FromContext(ctx)
-
-	// NEVER modify objects from the store. It's a read-only, local cache.
-	// You can use DeepCopy() to make a deep manifestCopy of original object and modify this manifestCopy
-	// Or create a manifestCopy manually for better performance
-	manifestCopy := manifest.DeepCopy()
-
-	if err == nil {
-		manifestCopy.Status.Status = "OK"
-		manifestCopy.Status.Message = "Synchronized"
-	} else {
-		manifestCopy.Status.Status = "Error"
-		manifestCopy.Status.Message = err.Error()
+	err := indexValueRefs(mgr, &v1alpha1.AlertManager{}, func(object client.Object) interface{} {
+		return object.(*v1alpha1.AlertManager).Spec
+	})
+	if err != nil {
+		return err
 	}
 
-	if err := r.Status().Update(ctx, manifestCopy); err != nil {
-		logger.Error(err, "unable to update AlertManager status")
-	}
+	newList := func() client.ObjectList { return &v1alpha1.AlertManagerList{} }
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.AlertManager{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// referenced secrets and config maps changing must be reflected in Grafana
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(requestsForReferenced(r.Client, secretRefsIndex, newList))).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(requestsForReferenced(r.Client, configMapRefsIndex, newList))).
+		Complete(r)
 }
@@ -2,15 +2,20 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/K-Phoen/dark/api/v1alpha1"
 	"github.com/K-Phoen/dark/internal/pkg/grafana"
 	"github.com/K-Phoen/dark/internal/pkg/kubernetes"
-	"github.com/K-Phoen/grabana"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -20,9 +25,13 @@ import (
 //nolint:gosec
 const apiKeysFinalizerName = "apikeys.k8s.kevingomez.fr/finalizer" //  (these are not hardcoded credentials -_-)
 
+// minKeyCheckDelay is the shortest delay before checking a key again: checks
+// due now, or already overdue, are retried shortly instead of immediately.
+const minKeyCheckDelay = 5 * time.Second
+
 type apiKeyClient interface {
-	Reconcile(ctx context.Context, key grafana.APIKey) error
-	Delete(ctx context.Context, name string) error
+	Reconcile(ctx context.Context, key grafana.APIKey, state grafana.APIKeyState) (grafana.APIKeyState, error)
+	Delete(ctx context.Context, key grafana.APIKey, state grafana.APIKeyState) error
 }
 
 // APIKeyReconciler reconciles a APIKey object
@@ -32,17 +41,21 @@ type APIKeyReconciler struct {
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
 
-	apiKeyClient apiKeyClient
+	instances    grafanaInstances
+	apiKeyClient func(grafanaClient *grafana.Client) apiKeyClient
 }
 
-func StartAPIKeyReconciler(logger logr.Logger, ctrlManager ctrl.Manager, grabanaClient *grabana.Client) error {
-	apiKeys := grafana.NewAPIKeys(logger, grabanaClient, kubernetes.NewSecrets(logger, ctrlManager.GetClient()))
+func StartAPIKeyReconciler(logger logr.Logger, ctrlManager ctrl.Manager, instances *grafana.Instances) error {
+	secrets := kubernetes.NewSecrets(logger, ctrlManager.GetClient())
 
 	reconciler := &APIKeyReconciler{
-		Client:       ctrlManager.GetClient(),
-		Scheme:       ctrlManager.GetScheme(),
-		Recorder:     ctrlManager.GetEventRecorderFor("api-key-controller"),
-		apiKeyClient: apiKeys,
+		Client:    ctrlManager.GetClient(),
+		Scheme:    ctrlManager.GetScheme(),
+		Recorder:  ctrlManager.GetEventRecorderFor("api-key-controller"),
+		instances: instances,
+		apiKeyClient: func(grafanaClient *grafana.Client) apiKeyClient {
+			return grafana.NewAPIKeys(logger, grafanaClient, secrets)
+		},
 	}
 
 	return reconciler.SetupWithManager(ctrlManager)
@@ -52,7 +65,7 @@ func StartAPIKeyReconciler(logger logr.Logger, ctrlManager ctrl.Manager, grabana
 //+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=apikeys/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=apikeys/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
-//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -77,6 +90,21 @@ func (r *APIKeyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	grafanaClient, err := r.instances.ClientFor(ctx, apiKeyManifest, apiKeyManifest.Spec.InstanceRef)
+	if err != nil {
+		logger.Error(err, "unable to resolve Grafana instance")
+
+		if isInstanceGone(err) && !apiKeyManifest.ObjectMeta.DeletionTimestamp.IsZero() {
+			return ctrl.Result{}, releaseFinalizer(ctx, r.Client, r.Recorder, apiKeyManifest, apiKeysFinalizerName)
+		}
+
+		statusErr := updateStatus(ctx, r.Client, apiKeyManifest, err)
+		r.Recorder.Event(apiKeyManifest, "Warning", "Error", "could not resolve Grafana instance")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+	apiKeys := r.apiKeyClient(grafanaClient)
+
 	// examine DeletionTimestamp to determine if object is under deletion
 	if apiKeyManifest.ObjectMeta.DeletionTimestamp.IsZero() {
 		// The object is not being deleted, so if it does not have our finalizer,
@@ -95,8 +123,11 @@ func (r *APIKeyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		if containsString(apiKeyManifest.GetFinalizers(), apiKeysFinalizerName) {
 			logger.Info("finalizer found, deleting API key from grafana")
 
+			// rotation settings are irrelevant to deletions: invalid ones can be ignored
+			key, _ := keyFromManifest(apiKeyManifest)
+
 			// our finalizer is present, so lets handle any external dependency
-			if err := r.apiKeyClient.Delete(ctx, apiKeyManifest.Name); err != nil {
+			if err := apiKeys.Delete(ctx, key, keyStateFromStatus(apiKeyManifest.Status)); err != nil {
 				// if fail to delete the external dependency here, return with error
 				// so that it can be retried
 				return ctrl.Result{}, err
@@ -114,61 +145,141 @@ func (r *APIKeyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	}
 
 	// handle actual reconciliation
-	return r.doReconcileManifest(ctx, apiKeyManifest)
+	return r.doReconcileManifest(ctx, apiKeys, apiKeyManifest)
 
 }
 
-func (r *APIKeyReconciler) doReconcileManifest(ctx context.Context, manifest *v1alpha1.APIKey) (ctrl.Result, error) {
+func (r *APIKeyReconciler) doReconcileManifest(ctx context.Context, apiKeys apiKeyClient, manifest *v1alpha1.APIKey) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
-	key := grafana.APIKey{
-		Name:            manifest.Name,
-		Role:            manifest.Spec.Role,
-		SecretName:      manifest.Name,
-		SecretNamespace: manifest.Namespace,
-		TokenKey:        "token",
+	key, err := keyFromManifest(manifest)
+	if err != nil {
+		logger.Info("invalid API key")
+
+		statusErr := updateStatus(ctx, r.Client, manifest, err)
+		r.Recorder.Event(manifest, "Warning", "Error", "invalid API key")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
 	}
 
-	if err := r.apiKeyClient.Reconcile(ctx, key); err != nil {
+	previousState := keyStateFromStatus(manifest.Status)
+
+	state, err := apiKeys.Reconcile(ctx, key, previousState)
+	if err != nil {
 		logger.Info("failed reconciling API key")
 
-		r.updateStatus(ctx, manifest, err)
+		statusErr := updateStatus(ctx, r.Client, withKeyState(manifest, key, state), err)
 		r.Recorder.Event(manifest, "Warning", "Error", "could not reconcile API key with Grafana")
 
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+
+	if err := updateStatus(ctx, r.Client, withKeyState(manifest, key, state), nil); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	r.updateStatus(ctx, manifest, nil)
-	r.Recorder.Event(manifest, "Normal", "Synchronized", "API key reconciled")
+	if !state.LastRotationTime.Equal(previousState.LastRotationTime) {
+		r.Recorder.Event(manifest, "Normal", "Rotated", "API key rotated")
+	} else {
+		r.Recorder.Event(manifest, "Normal", "Synchronized", "API key reconciled")
+	}
+
+	nextCheck := key.NextCheck(state)
+	if nextCheck.IsZero() {
+		return ctrl.Result{}, nil
+	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: keyCheckDelay(nextCheck, time.Now())}, nil
 }
 
-// SetupWithManager sets up the controller with the Manager.
-func (r *APIKeyReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.APIKey{}).
-		WithEventFilter(predicate.GenerationChangedPredicate{}).
-		Complete(r)
+// keyCheckDelay returns how long to wait before checking a key again.
+func keyCheckDelay(nextCheck time.Time, now time.Time) time.Duration {
+	delay := nextCheck.Sub(now)
+	if delay < minKeyCheckDelay {
+		return minKeyCheckDelay
+	}
+
+	return delay
 }
 
-func (r *APIKeyReconciler) updateStatus(ctx context.Context, manifest *v1alpha1.APIKey, err error) {
-	logger := log.FromContext(ctx)
+func keyFromManifest(manifest *v1alpha1.APIKey) (grafana.APIKey, error) {
+	mode := manifest.Spec.Mode
+	if mode == "" {
+		mode = grafana.APIKeyMode
+	}
 
-	// NEVER modify objects from the store. It's a read-only, local cache.
-	// You can use DeepCopy() to make a deep manifestCopy of original object and modify this manifestCopy
-	// Or create a manifestCopy manually for better performance
-	manifestCopy := manifest.DeepCopy()
+	key := grafana.APIKey{
+		Name:            manifest.Name,
+		Role:            manifest.Spec.Role,
+		Mode:            mode,
+		SecondsToLive:   manifest.Spec.SecondsToLive,
+		SecretName:      manifest.Name,
+		SecretNamespace: manifest.Namespace,
+		TokenKey:        "token",
+		SecretOwner:     metav1.NewControllerRef(manifest, v1alpha1.GroupVersion.WithKind("APIKey")),
+	}
 
-	if err == nil {
-		manifestCopy.Status.Status = "OK"
-		manifestCopy.Status.Message = "Synchronized"
-	} else {
-		manifestCopy.Status.Status = "Error"
-		manifestCopy.Status.Message = err.Error()
+	if secret := manifest.Spec.Secret; secret != nil {
+		if secret.Name != "" {
+			key.SecretName = secret.Name
+		}
+		if secret.TokenKey != "" {
+			key.TokenKey = secret.TokenKey
+		}
+
+		key.SecretLabels = secret.Labels
+		key.SecretAnnotations = secret.Annotations
+		key.SecretTemplates = secret.Templates
 	}
 
-	if err := r.Status().Update(ctx, manifestCopy); err != nil {
-		logger.Error(err, "unable to update APIKey status")
+	var err error
+
+	if manifest.Spec.RotateEvery != "" {
+		if key.RotateEvery, err = time.ParseDuration(manifest.Spec.RotateEvery); err != nil {
+			return key, fmt.Errorf("invalid rotateEvery: %w", err)
+		}
+	}
+	if manifest.Spec.RotationOverlap != "" {
+		if key.RotationOverlap, err = time.ParseDuration(manifest.Spec.RotationOverlap); err != nil {
+			return key, fmt.Errorf("invalid rotationOverlap: %w", err)
+		}
 	}
+
+	return key, nil
+}
+
+func keyStateFromStatus(status v1alpha1.APIKeyStatus) grafana.APIKeyState {
+	return grafana.APIKeyState{
+		Current:          status.KeyName,
+		CreatedAt:        timeOrZero(status.CreatedAt),
+		ExpiresAt:        timeOrZero(status.ExpiresAt),
+		Previous:         status.PreviousKeyName,
+		RevokePreviousAt: timeOrZero(status.PreviousKeyRevocationTime),
+		LastRotationTime: timeOrZero(status.LastRotationTime),
+		TokenHash:        status.TokenHash,
+	}
+}
+
+func withKeyState(manifest *v1alpha1.APIKey, key grafana.APIKey, state grafana.APIKeyState) *v1alpha1.APIKey {
+	manifestCopy := manifest.DeepCopy()
+
+	manifestCopy.Status.KeyName = state.Current
+	manifestCopy.Status.CreatedAt = timeOrNil(state.CreatedAt)
+	manifestCopy.Status.ExpiresAt = timeOrNil(state.ExpiresAt)
+	manifestCopy.Status.NextRotationTime = timeOrNil(key.NextRotation(state))
+	manifestCopy.Status.LastRotationTime = timeOrNil(state.LastRotationTime)
+	manifestCopy.Status.PreviousKeyName = state.Previous
+	manifestCopy.Status.PreviousKeyRevocationTime = timeOrNil(state.RevokePreviousAt)
+	manifestCopy.Status.TokenHash = state.TokenHash
+
+	return manifestCopy
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *APIKeyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.APIKey{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// secrets deleted or modified by someone else are regenerated right away
+		Owns(&corev1.Secret{}).
+		Complete(r)
 }
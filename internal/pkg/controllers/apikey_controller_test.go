@@ -0,0 +1,19 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyCheckDelay(t *testing.T) {
+	req := require.New(t)
+
+	now := time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	req.Equal(time.Hour, keyCheckDelay(now.Add(time.Hour), now))
+	req.Equal(minKeyCheckDelay, keyCheckDelay(now, now))
+	req.Equal(minKeyCheckDelay, keyCheckDelay(now.Add(-time.Minute), now))
+	req.Equal(minKeyCheckDelay, keyCheckDelay(now.Add(time.Second), now))
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// conversionWarnings is a zap core collecting the warnings emitted while
+// converting a Grafana JSON dashboard: panels, annotations, links, ... that
+// the conversion skipped.
+type conversionWarnings struct {
+	fields   []zapcore.Field
+	messages *[]string
+}
+
+func newConversionWarnings() *conversionWarnings {
+	return &conversionWarnings{messages: &[]string{}}
+}
+
+// Messages returns the warnings collected so far.
+func (warnings *conversionWarnings) Messages() []string {
+	return *warnings.messages
+}
+
+func (warnings *conversionWarnings) Enabled(level zapcore.Level) bool {
+	return level == zapcore.WarnLevel
+}
+
+func (warnings *conversionWarnings) With(fields []zapcore.Field) zapcore.Core {
+	return &conversionWarnings{
+		fields:   append(append([]zapcore.Field{}, warnings.fields...), fields...),
+		messages: warnings.messages,
+	}
+}
+
+func (warnings *conversionWarnings) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !warnings.Enabled(entry.Level) {
+		return checked
+	}
+
+	return checked.AddCore(entry, warnings)
+}
+
+func (warnings *conversionWarnings) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range append(append([]zapcore.Field{}, warnings.fields...), fields...) {
+		field.AddTo(encoder)
+	}
+
+	if len(encoder.Fields) == 0 {
+		*warnings.messages = append(*warnings.messages, entry.Message)
+		return nil
+	}
+
+	details := make([]string, 0, len(encoder.Fields))
+	for key, value := range encoder.Fields {
+		details = append(details, fmt.Sprintf("%s=%v", key, value))
+	}
+	sort.Strings(details)
+
+	*warnings.messages = append(*warnings.messages, fmt.Sprintf("%s (%s)", entry.Message, strings.Join(details, ", ")))
+
+	return nil
+}
+
+func (warnings *conversionWarnings) Sync() error {
+	return nil
+}
@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestConversionWarningsCollectsWarnings(t *testing.T) {
+	req := require.New(t)
+
+	warnings := newConversionWarnings()
+	logger := zap.New(warnings)
+
+	logger.Info("ignored")
+	logger.Error("ignored too")
+	logger.Warn("unhandled panel type: skipped", zap.String("type", "news"), zap.String("title", "Feed"))
+	logger.With(zap.String("dashboard", "test")).Warn("link URL empty: skipped")
+
+	req.Equal([]string{
+		"unhandled panel type: skipped (title=Feed, type=news)",
+		"link URL empty: skipped (dashboard=test)",
+	}, warnings.Messages())
+}
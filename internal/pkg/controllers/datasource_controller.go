@@ -2,29 +2,48 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/K-Phoen/dark/api/v1alpha1"
 	"github.com/K-Phoen/dark/internal/pkg/grafana"
 	"github.com/K-Phoen/dark/internal/pkg/kubernetes"
-	"github.com/K-Phoen/grabana"
-	"github.com/K-Phoen/grabana/datasource"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const datasourcesFinalizerName = "datasources.k8s.kevingomez.fr/finalizer"
 
+// DatasourceUIDsAnnotation marks the datasources handled since DARK identifies
+// them by UID. Manifests holding the finalizer without it were synchronized by
+// earlier versions of DARK, that identified datasources by name.
+const DatasourceUIDsAnnotation = "dark/datasource-uids"
+
+// datasourceIdentityIndex indexes datasources by the UID and the name under
+// which they are stored in their Grafana instance.
+const datasourceIdentityIndex = ".spec.identity"
+
+var ErrDatasourceConflict = fmt.Errorf("datasource conflict")
+
 type datasourcesManager interface {
-	SpecToModel(ctx context.Context, objectRef types.NamespacedName, spec v1alpha1.DatasourceSpec) (datasource.Datasource, error)
-	Upsert(ctx context.Context, model datasource.Datasource) error
-	Delete(ctx context.Context, name string) error
+	SpecToModel(ctx context.Context, objectRef types.NamespacedName, spec v1alpha1.DatasourceSpec) (grafana.Datasource, error)
+	Upsert(ctx context.Context, model grafana.Datasource) error
+	Delete(ctx context.Context, uid string) error
+	DeleteByName(ctx context.Context, name string) error
 }
 
 // DatasourceReconciler reconciles a Datasource object
@@ -34,7 +53,8 @@ type DatasourceReconciler struct {
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
 
-	Datasources datasourcesManager
+	Instances   grafanaInstances
+	Datasources func(grafanaClient *grafana.Client) datasourcesManager
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -55,6 +75,21 @@ func (r *DatasourceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	grafanaClient, err := r.Instances.ClientFor(ctx, datasourceManifest, datasourceManifest.Spec.InstanceRef)
+	if err != nil {
+		logger.Error(err, "unable to resolve Grafana instance")
+
+		if isInstanceGone(err) && !datasourceManifest.ObjectMeta.DeletionTimestamp.IsZero() {
+			return ctrl.Result{}, releaseFinalizer(ctx, r.Client, r.Recorder, datasourceManifest, datasourcesFinalizerName)
+		}
+
+		statusErr := updateStatus(ctx, r.Client, datasourceManifest, err)
+		r.Recorder.Event(datasourceManifest, "Warning", "Error", "could not resolve Grafana instance")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+	datasources := r.Datasources(grafanaClient)
+
 	// examine DeletionTimestamp to determine if object is under deletion
 	if datasourceManifest.ObjectMeta.DeletionTimestamp.IsZero() {
 		// The object is not being deleted, so if it does not have our finalizer,
@@ -62,6 +97,7 @@ func (r *DatasourceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		// registering our finalizer.
 		if !containsString(datasourceManifest.GetFinalizers(), datasourcesFinalizerName) {
 			controllerutil.AddFinalizer(datasourceManifest, datasourcesFinalizerName)
+			metav1.SetMetaDataAnnotation(&datasourceManifest.ObjectMeta, DatasourceUIDsAnnotation, "true")
 			if err := r.Update(ctx, datasourceManifest); err != nil {
 				return ctrl.Result{}, err
 			}
@@ -73,8 +109,17 @@ func (r *DatasourceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		if containsString(datasourceManifest.GetFinalizers(), datasourcesFinalizerName) {
 			logger.Info("finalizer found, deleting datasource from grafana")
 
-			// our finalizer is present, so lets handle any external dependency
-			if err := r.Datasources.Delete(ctx, datasourceManifest.Name); err != nil {
+			// our finalizer is present, so lets handle any external dependency.
+			// Datasources synchronized by earlier versions of DARK are deleted
+			// by name. Other datasources never synchronized under a UID are
+			// left alone: they might belong to another manifest.
+			var err error
+			if uid := datasourceManifest.Status.UID; uid != "" {
+				err = datasources.Delete(ctx, uid)
+			} else if synchronizedWithoutUID(datasourceManifest) {
+				err = datasources.DeleteByName(ctx, datasourceManifest.Name)
+			}
+			if err != nil {
 				// if fail to delete the external dependency here, return with error
 				// so that it can be retried
 				return ctrl.Result{}, err
@@ -91,48 +136,194 @@ func (r *DatasourceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, nil
 	}
 
-	datasourceModel, err := r.Datasources.SpecToModel(ctx, req.NamespacedName, datasourceManifest.Spec)
+	conflicting, err := r.conflictingDatasource(ctx, datasourceManifest)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if conflicting != nil {
+		err := fmt.Errorf("uid or name already used by %s/%s: %w", conflicting.Namespace, conflicting.Name, ErrDatasourceConflict)
+		logger.Error(err, "datasource conflicts with another one")
+
+		statusErr := updateStatus(ctx, r.Client, datasourceManifest, err)
+		r.Recorder.Event(datasourceManifest, "Warning", "Conflict", err.Error())
+
+		// the conflicting datasource changing or going away will trigger a new reconciliation
+		return ctrl.Result{}, statusErr
+	}
+
+	datasourceModel, err := datasources.SpecToModel(ctx, req.NamespacedName, datasourceManifest.Spec)
 	if err != nil {
 		logger.Error(err, "unable to convert Datasource manifest into a Grabana model")
 
-		r.updateStatus(ctx, datasourceManifest, err)
+		statusErr := updateStatus(ctx, r.Client, datasourceManifest, err)
 		r.Recorder.Event(datasourceManifest, "Warning", "Error", "could not synchronize Datasource with Grafana")
 
-		return ctrl.Result{}, err
+		return ctrl.Result{}, errors.Join(err, statusErr)
 	}
 
+	datasourceModel.PreviousUID = datasourceManifest.Status.UID
+	datasourceModel.SynchronizedWithoutUID = synchronizedWithoutUID(datasourceManifest)
+
 	// proceed with create/update reconciliation
-	if err := r.Datasources.Upsert(ctx, datasourceModel); err != nil {
+	err = datasources.Upsert(ctx, datasourceModel)
+	if errors.Is(err, grafana.ErrDatasourceNotManaged) {
+		logger.Error(err, "datasource conflicts with one not managed by DARK")
+
+		statusErr := updateStatus(ctx, r.Client, datasourceManifest, err)
+		r.Recorder.Event(datasourceManifest, "Warning", "Conflict", err.Error())
+
+		// retrying won't help until the datasource is removed from Grafana or renamed
+		return ctrl.Result{}, statusErr
+	}
+	if err != nil {
 		logger.Error(err, "could not upsert Datasource in Grafana")
 
-		r.updateStatus(ctx, datasourceManifest, err)
+		statusErr := updateStatus(ctx, r.Client, datasourceManifest, err)
 		r.Recorder.Event(datasourceManifest, "Warning", "Error", "could not synchronize Datasource with Grafana")
 
-		return ctrl.Result{}, err
+		return ctrl.Result{}, errors.Join(err, statusErr)
 	}
 
 	logger.Info("done!")
 
-	r.updateStatus(ctx, datasourceManifest, nil)
+	synchronized := datasourceManifest.DeepCopy()
+	synchronized.Status.UID = datasourceModel.UID
+
+	if err := updateStatus(ctx, r.Client, synchronized, nil); err != nil {
+		return ctrl.Result{}, err
+	}
 	r.Recorder.Event(datasourceManifest, "Normal", "Synchronized", "Datasource synchronized")
 
 	return ctrl.Result{}, nil
 }
 
+// conflictingDatasource returns the datasource holding the UID or the name
+// of the given one in their Grafana instance, if any.
+func (r *DatasourceReconciler) conflictingDatasource(ctx context.Context, manifest *v1alpha1.Datasource) (*v1alpha1.Datasource, error) {
+	for _, identity := range datasourceIdentities(manifest) {
+		list := &v1alpha1.DatasourceList{}
+		if err := r.List(ctx, list, client.MatchingFields{datasourceIdentityIndex: identity}); err != nil {
+			return nil, err
+		}
+
+		for i := range list.Items {
+			other := &list.Items[i]
+			if other.UID != manifest.UID && claimsIdentityFirst(other, manifest) {
+				return other, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// datasourceIdentities lists the keys identifying a datasource in its
+// Grafana instance: its UID and its name.
+func datasourceIdentities(manifest *v1alpha1.Datasource) []string {
+	objectRef := types.NamespacedName{Namespace: manifest.Namespace, Name: manifest.Name}
+	instance := grafana.InstanceKey(manifest, manifest.Spec.InstanceRef)
+
+	return []string{
+		instance + "/uid/" + grafana.DatasourceUID(objectRef, manifest.Spec),
+		instance + "/name/" + grafana.DatasourceDisplayName(objectRef, manifest.Spec),
+	}
+}
+
+// claimsIdentityFirst tells whether a datasource takes precedence over another
+// one sharing its UID or name: datasources already synchronized under their
+// current UID keep it, otherwise the oldest manifest wins.
+func claimsIdentityFirst(a *v1alpha1.Datasource, b *v1alpha1.Datasource) bool {
+	aSynchronized := isSynchronizedUnderCurrentUID(a)
+	bSynchronized := isSynchronizedUnderCurrentUID(b)
+	if aSynchronized != bSynchronized {
+		return aSynchronized
+	}
+
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+
+	return a.Namespace+"/"+a.Name < b.Namespace+"/"+b.Name
+}
+
+// synchronizedWithoutUID tells whether a datasource was synchronized by a
+// version of DARK that identified datasources by name, and was not
+// synchronized under a UID since.
+func synchronizedWithoutUID(manifest *v1alpha1.Datasource) bool {
+	return manifest.Status.UID == "" &&
+		containsString(manifest.GetFinalizers(), datasourcesFinalizerName) &&
+		!metav1.HasAnnotation(manifest.ObjectMeta, DatasourceUIDsAnnotation)
+}
+
+func isSynchronizedUnderCurrentUID(manifest *v1alpha1.Datasource) bool {
+	objectRef := types.NamespacedName{Namespace: manifest.Namespace, Name: manifest.Name}
+
+	return manifest.Status.UID != "" && manifest.Status.UID == grafana.DatasourceUID(objectRef, manifest.Spec)
+}
+
+// datasourcePrecedenceChanged filters updates changing whether a datasource
+// takes precedence over the ones sharing its UID or name.
+func datasourcePrecedenceChanged() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldManifest, oldOk := e.ObjectOld.(*v1alpha1.Datasource)
+			newManifest, newOk := e.ObjectNew.(*v1alpha1.Datasource)
+			if !oldOk || !newOk {
+				return false
+			}
+
+			return isSynchronizedUnderCurrentUID(oldManifest) != isSynchronizedUnderCurrentUID(newManifest)
+		},
+	}
+}
+
+// requestsForConflictingDatasources enqueues the datasources sharing the UID
+// or the name of the given one, so that conflicts are re-evaluated.
+func requestsForConflictingDatasources(reader client.Reader) handler.MapFunc {
+	return func(object client.Object) []reconcile.Request {
+		manifest, ok := object.(*v1alpha1.Datasource)
+		if !ok {
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, identity := range datasourceIdentities(manifest) {
+			list := &v1alpha1.DatasourceList{}
+			if err := reader.List(context.Background(), list, client.MatchingFields{datasourceIdentityIndex: identity}); err != nil {
+				return nil
+			}
+
+			for _, other := range list.Items {
+				if other.UID == manifest.UID {
+					continue
+				}
+
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Namespace: other.Namespace, Name: other.Name},
+				})
+			}
+		}
+
+		return requests
+	}
+}
+
 //+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=datasources,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=datasources/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=datasources/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 
-func StartDatasourceReconciler(logger logr.Logger, ctrlManager ctrl.Manager, grabanaClient *grabana.Client) error {
-	refReader := kubernetes.NewValueRefReader(logger, kubernetes.NewSecrets(logger, ctrlManager.GetClient()))
-
+func StartDatasourceReconciler(logger logr.Logger, ctrlManager ctrl.Manager, instances *grafana.Instances, refReader *kubernetes.ValueRefReader) error {
 	reconciler := &DatasourceReconciler{
-		Client:      ctrlManager.GetClient(),
-		Scheme:      ctrlManager.GetScheme(),
-		Recorder:    ctrlManager.GetEventRecorderFor("grafanadashboard-controller"),
-		Datasources: grafana.NewDatasources(logger, grabanaClient, refReader),
+		Client:    ctrlManager.GetClient(),
+		Scheme:    ctrlManager.GetScheme(),
+		Recorder:  ctrlManager.GetEventRecorderFor("grafanadashboard-controller"),
+		Instances: instances,
+		Datasources: func(grafanaClient *grafana.Client) datasourcesManager {
+			return grafana.NewDatasources(logger, grafanaClient, refReader, ctrlManager.GetClient())
+		},
 	}
 
 	return reconciler.SetupWithManager(ctrlManager)
@@ -140,29 +331,29 @@ func StartDatasourceReconciler(logger logr.Logger, ctrlManager ctrl.Manager, gra
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *DatasourceReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.Datasource{}).
-		WithEventFilter(predicate.GenerationChangedPredicate{}).
-		Complete(r)
-}
-
-func (r *DatasourceReconciler) updateStatus(ctx context.Context, manifest *v1alpha1.Datasource, err error) {
-	logger := log.FromContext(ctx)
-
-	// NEVER modify objects from the store. It's a read-only, local cache.
-	// You can use DeepCopy() to make a deep copy of original object and modify this copy
-	// Or create a copy manually for better performance
-	manifestCopy := manifest.DeepCopy()
-
-	if err == nil {
-		manifestCopy.Status.Status = "OK"
-		manifestCopy.Status.Message = "Synchronized"
-	} else {
-		manifestCopy.Status.Status = "Error"
-		manifestCopy.Status.Message = err.Error()
+	err := indexValueRefs(mgr, &v1alpha1.Datasource{}, func(object client.Object) interface{} {
+		return object.(*v1alpha1.Datasource).Spec
+	})
+	if err != nil {
+		return err
 	}
 
-	if err := r.Status().Update(ctx, manifestCopy); err != nil {
-		logger.Error(err, "unable to update Datasource status")
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Datasource{}, datasourceIdentityIndex, func(object client.Object) []string {
+		return datasourceIdentities(object.(*v1alpha1.Datasource))
+	})
+	if err != nil {
+		return err
 	}
+
+	newList := func() client.ObjectList { return &v1alpha1.DatasourceList{} }
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.Datasource{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// referenced secrets and config maps changing must be reflected in Grafana
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(requestsForReferenced(r.Client, secretRefsIndex, newList))).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(requestsForReferenced(r.Client, configMapRefsIndex, newList))).
+		// datasources sharing a UID or a name must be re-evaluated when one of them changes or goes away
+		// precedence depends on the status too: a datasource getting synchronized under its UID wins its conflicts
+		Watches(&source.Kind{Type: &v1alpha1.Datasource{}}, handler.EnqueueRequestsFromMapFunc(requestsForConflictingDatasources(r.Client)), builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, datasourcePrecedenceChanged()))).
+		Complete(r)
 }
@@ -0,0 +1,219 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/dark/internal/pkg/grafana"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func datasourceManifest(namespace string, name string, createdAt time.Time, spec v1alpha1.DatasourceSpec) *v1alpha1.Datasource {
+	return &v1alpha1.Datasource{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         namespace,
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(createdAt),
+		},
+		Spec: spec,
+	}
+}
+
+func TestDatasourceIdentitiesAreScopedToTheirInstance(t *testing.T) {
+	req := require.New(t)
+
+	manifest := datasourceManifest("team-a", "prometheus", time.Now(), v1alpha1.DatasourceSpec{
+		UID:         "prom",
+		InstanceRef: &v1alpha1.InstanceRef{Name: "staging"},
+	})
+
+	req.Equal([]string{"team-a/staging/uid/prom", "team-a/staging/name/prometheus"}, datasourceIdentities(manifest))
+}
+
+func TestSynchronizedDatasourcesKeepTheirIdentity(t *testing.T) {
+	req := require.New(t)
+
+	now := time.Now()
+	older := datasourceManifest("team-a", "prometheus", now.Add(-time.Hour), v1alpha1.DatasourceSpec{UID: "prom"})
+	newer := datasourceManifest("team-b", "prometheus", now, v1alpha1.DatasourceSpec{UID: "prom"})
+
+	req.True(claimsIdentityFirst(older, newer))
+	req.False(claimsIdentityFirst(newer, older))
+
+	newer.Status.UID = grafana.DatasourceUID(types.NamespacedName{Namespace: "team-b", Name: "prometheus"}, newer.Spec)
+
+	req.True(claimsIdentityFirst(newer, older))
+	req.False(claimsIdentityFirst(older, newer))
+}
+
+type stubInstances struct{}
+
+func (stubInstances) ClientFor(_ context.Context, _ metav1.Object, _ *v1alpha1.InstanceRef) (*grafana.Client, error) {
+	return nil, nil
+}
+
+type stubDatasourcesManager struct {
+	upserted      []grafana.Datasource
+	deleted       []string
+	deletedByName []string
+}
+
+func (manager *stubDatasourcesManager) SpecToModel(_ context.Context, objectRef types.NamespacedName, spec v1alpha1.DatasourceSpec) (grafana.Datasource, error) {
+	return grafana.Datasource{
+		UID:  grafana.DatasourceUID(objectRef, spec),
+		Name: grafana.DatasourceDisplayName(objectRef, spec),
+	}, nil
+}
+
+func (manager *stubDatasourcesManager) Upsert(_ context.Context, model grafana.Datasource) error {
+	manager.upserted = append(manager.upserted, model)
+	return nil
+}
+
+func (manager *stubDatasourcesManager) Delete(_ context.Context, uid string) error {
+	manager.deleted = append(manager.deleted, uid)
+	return nil
+}
+
+func (manager *stubDatasourcesManager) DeleteByName(_ context.Context, name string) error {
+	manager.deletedByName = append(manager.deletedByName, name)
+	return nil
+}
+
+// datasourceClient serves a single datasource manifest, and records the
+// updates made to it.
+type datasourceClient struct {
+	*statusRecorder
+
+	manifest *v1alpha1.Datasource
+	updated  []*v1alpha1.Datasource
+}
+
+func (c *datasourceClient) Get(_ context.Context, _ client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	c.manifest.DeepCopyInto(obj.(*v1alpha1.Datasource))
+	return nil
+}
+
+func (c *datasourceClient) List(_ context.Context, _ client.ObjectList, _ ...client.ListOption) error {
+	return nil
+}
+
+func (c *datasourceClient) Update(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	c.updated = append(c.updated, obj.(*v1alpha1.Datasource).DeepCopy())
+	return nil
+}
+
+func testDatasourceReconciler(manifest *v1alpha1.Datasource) (*DatasourceReconciler, *datasourceClient, *stubDatasourcesManager) {
+	k8sClient := &datasourceClient{statusRecorder: &statusRecorder{}, manifest: manifest}
+	datasources := &stubDatasourcesManager{}
+
+	return &DatasourceReconciler{
+		Client:    k8sClient,
+		Recorder:  record.NewFakeRecorder(10),
+		Instances: stubInstances{},
+		Datasources: func(_ *grafana.Client) datasourcesManager {
+			return datasources
+		},
+	}, k8sClient, datasources
+}
+
+// baselineDatasource returns a datasource as left by versions of DARK that
+// identified datasources by name: holding the finalizer, with a status
+// carrying neither a UID nor conditions.
+func baselineDatasource() *v1alpha1.Datasource {
+	manifest := datasourceManifest("default", "prometheus", time.Now(), v1alpha1.DatasourceSpec{})
+	manifest.Finalizers = []string{datasourcesFinalizerName}
+
+	return manifest
+}
+
+func TestDatasourcesSynchronizedByEarlierVersionsAreFoundByName(t *testing.T) {
+	req := require.New(t)
+
+	reconciler, k8sClient, datasources := testDatasourceReconciler(baselineDatasource())
+
+	_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "prometheus"}})
+	req.NoError(err)
+
+	req.Len(datasources.upserted, 1)
+	req.True(datasources.upserted[0].SynchronizedWithoutUID)
+	req.Empty(datasources.upserted[0].PreviousUID)
+
+	req.Len(k8sClient.statusRecorder.updated, 1)
+	req.Equal(datasources.upserted[0].UID, k8sClient.statusRecorder.updated[0].(*v1alpha1.Datasource).Status.UID)
+}
+
+func TestNewDatasourcesAreNotFoundByName(t *testing.T) {
+	req := require.New(t)
+
+	reconciler, k8sClient, datasources := testDatasourceReconciler(datasourceManifest("default", "prometheus", time.Now(), v1alpha1.DatasourceSpec{}))
+
+	_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "prometheus"}})
+	req.NoError(err)
+
+	req.Len(k8sClient.updated, 1)
+	req.Contains(k8sClient.updated[0].Finalizers, datasourcesFinalizerName)
+	req.True(metav1.HasAnnotation(k8sClient.updated[0].ObjectMeta, DatasourceUIDsAnnotation))
+
+	req.Len(datasources.upserted, 1)
+	req.False(datasources.upserted[0].SynchronizedWithoutUID)
+}
+
+func TestDatasourcesSynchronizedByEarlierVersionsAreDeletedByName(t *testing.T) {
+	req := require.New(t)
+
+	manifest := baselineDatasource()
+	deletedAt := metav1.Now()
+	manifest.DeletionTimestamp = &deletedAt
+
+	reconciler, k8sClient, datasources := testDatasourceReconciler(manifest)
+
+	_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "prometheus"}})
+	req.NoError(err)
+
+	req.Equal([]string{"prometheus"}, datasources.deletedByName)
+	req.Empty(datasources.deleted)
+	req.Len(k8sClient.updated, 1)
+	req.NotContains(k8sClient.updated[0].Finalizers, datasourcesFinalizerName)
+}
+
+func TestUnsynchronizedDatasourcesAreNotDeleted(t *testing.T) {
+	req := require.New(t)
+
+	manifest := baselineDatasource()
+	manifest.Annotations = map[string]string{DatasourceUIDsAnnotation: "true"}
+	deletedAt := metav1.Now()
+	manifest.DeletionTimestamp = &deletedAt
+
+	reconciler, _, datasources := testDatasourceReconciler(manifest)
+
+	_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "prometheus"}})
+	req.NoError(err)
+
+	req.Empty(datasources.deletedByName)
+	req.Empty(datasources.deleted)
+}
+
+func TestDatasourcePrecedenceChanges(t *testing.T) {
+	req := require.New(t)
+
+	before := datasourceManifest("default", "prometheus", time.Now(), v1alpha1.DatasourceSpec{})
+	after := before.DeepCopy()
+	after.Status.UID = grafana.DatasourceUID(types.NamespacedName{Namespace: "default", Name: "prometheus"}, after.Spec)
+
+	req.True(datasourcePrecedenceChanged().Update(event.UpdateEvent{ObjectOld: before, ObjectNew: after}))
+
+	touched := after.DeepCopy()
+	now := metav1.Now()
+	touched.Status.LastSyncTime = &now
+
+	req.False(datasourcePrecedenceChanged().Update(event.UpdateEvent{ObjectOld: after, ObjectNew: touched}))
+}
@@ -0,0 +1,386 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/dark/internal/pkg/grafana"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const grafanaAlertRuleGroupsFinalizerName = "grafanaalertrulegroups.k8s.kevingomez.fr/finalizer"
+
+// alertRuleGroupFolderIndex indexes rule groups by the name of the
+// GrafanaFolder holding them.
+const alertRuleGroupFolderIndex = ".spec.folderRef.name"
+
+// alertRuleGroupIdentityIndex indexes rule groups by the instance, folder and
+// name under which they are stored in Grafana.
+const alertRuleGroupIdentityIndex = ".spec.identity"
+
+var ErrAlertRuleGroupConflict = fmt.Errorf("alert rule group conflict")
+
+type alertRuleGroupsManager interface {
+	Upsert(ctx context.Context, group grafana.AlertRuleGroup) (string, error)
+	Delete(ctx context.Context, folderTitle string, name string) error
+}
+
+// GrafanaAlertRuleGroupReconciler reconciles a GrafanaAlertRuleGroup object
+type GrafanaAlertRuleGroupReconciler struct {
+	client.Client
+
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	Instances       grafanaInstances
+	AlertRuleGroups func(grafanaClient *grafana.Client) alertRuleGroupsManager
+}
+
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanaalertrulegroups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanaalertrulegroups/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanaalertrulegroups/finalizers,verbs=update
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanafolders,verbs=get;list;watch
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=datasources,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
+func (r *GrafanaAlertRuleGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	logger.Info("reconciling")
+
+	group := &v1alpha1.GrafanaAlertRuleGroup{}
+	if err := r.Get(ctx, req.NamespacedName, group); err != nil {
+		logger.Error(err, "unable to fetch GrafanaAlertRuleGroup")
+		// we'll ignore not-found errors, since they can't be fixed by an immediate
+		// requeue (we'll need to wait for a new notification), and we can get them
+		// on deleted requests.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	grafanaClient, err := r.Instances.ClientFor(ctx, group, group.Spec.InstanceRef)
+	if err != nil {
+		logger.Error(err, "unable to resolve Grafana instance")
+
+		if isInstanceGone(err) && !group.ObjectMeta.DeletionTimestamp.IsZero() {
+			return ctrl.Result{}, releaseFinalizer(ctx, r.Client, r.Recorder, group, grafanaAlertRuleGroupsFinalizerName)
+		}
+
+		statusErr := updateStatus(ctx, r.Client, group, err)
+		r.Recorder.Event(group, "Warning", "Error", "could not resolve Grafana instance")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+	alertRuleGroups := r.AlertRuleGroups(grafanaClient)
+
+	// examine DeletionTimestamp to determine if object is under deletion
+	if group.ObjectMeta.DeletionTimestamp.IsZero() {
+		// The object is not being deleted, so if it does not have our finalizer,
+		// then lets add the finalizer and update the object. This is equivalent
+		// registering our finalizer.
+		if !containsString(group.GetFinalizers(), grafanaAlertRuleGroupsFinalizerName) {
+			controllerutil.AddFinalizer(group, grafanaAlertRuleGroupsFinalizerName)
+			if err := r.Update(ctx, group); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	} else {
+		logger.Info("deleting GrafanaAlertRuleGroup")
+
+		// The object is being deleted
+		if containsString(group.GetFinalizers(), grafanaAlertRuleGroupsFinalizerName) {
+			logger.Info("finalizer found, deleting alert rule group from grafana")
+
+			conflicting, err := r.conflictingRuleGroup(ctx, group)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+
+			// our finalizer is present, so lets handle any external dependency.
+			// Groups that lost a conflict are left alone: the group in Grafana
+			// belongs to another manifest.
+			if group.Status.Group != "" && conflicting == nil {
+				if err := alertRuleGroups.Delete(ctx, group.Status.Folder, group.Status.Group); err != nil {
+					// if fail to delete the external dependency here, return with error
+					// so that it can be retried
+					return ctrl.Result{}, err
+				}
+			}
+
+			// remove our finalizer from the list and update it.
+			controllerutil.RemoveFinalizer(group, grafanaAlertRuleGroupsFinalizerName)
+			if err := r.Update(ctx, group); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		// Stop reconciliation as the item is being deleted
+		return ctrl.Result{}, nil
+	}
+
+	conflicting, err := r.conflictingRuleGroup(ctx, group)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if conflicting != nil {
+		err := fmt.Errorf("folder and name already used by %s/%s: %w", conflicting.Namespace, conflicting.Name, ErrAlertRuleGroupConflict)
+		logger.Error(err, "alert rule group conflicts with another one")
+
+		statusErr := updateStatus(ctx, r.Client, group, err)
+		r.Recorder.Event(group, "Warning", "Conflict", err.Error())
+
+		// the conflicting group changing or going away will trigger a new reconciliation
+		return ctrl.Result{}, statusErr
+	}
+
+	folder, err := r.ruleGroupFolder(ctx, group)
+	if err != nil {
+		logger.Error(err, "could not resolve GrafanaAlertRuleGroup folder")
+
+		statusErr := updateStatus(ctx, r.Client, group, err)
+		r.Recorder.Event(group, "Warning", "Error", "could not resolve GrafanaAlertRuleGroup folder")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+
+	// proceed with create/update reconciliation
+	folderTitle, err := alertRuleGroups.Upsert(ctx, grafana.AlertRuleGroup{
+		Name:      group.GroupName(),
+		Namespace: group.Namespace,
+		Folder:    folder,
+		Spec:      group.Spec,
+	})
+	if err != nil {
+		logger.Error(err, "could not upsert GrafanaAlertRuleGroup in Grafana")
+
+		statusErr := updateStatus(ctx, r.Client, group, err)
+		r.Recorder.Event(group, "Warning", "Error", "could not synchronize GrafanaAlertRuleGroup with Grafana")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+
+	// the group was renamed or moved to another folder: its previous version
+	// must go
+	if isRelocatedRuleGroup(group, folderTitle) {
+		logger.Info("deleting previous alert rule group", "folder", group.Status.Folder, "group", group.Status.Group)
+
+		if err := alertRuleGroups.Delete(ctx, group.Status.Folder, group.Status.Group); err != nil {
+			logger.Error(err, "could not delete previous alert rule group from Grafana")
+
+			statusErr := updateStatus(ctx, r.Client, group, err)
+			r.Recorder.Event(group, "Warning", "Error", "could not delete previous alert rule group from Grafana")
+
+			return ctrl.Result{}, errors.Join(err, statusErr)
+		}
+	}
+
+	logger.Info("done!")
+
+	groupWithStatus := group.DeepCopy()
+	groupWithStatus.Status.Folder = folderTitle
+	groupWithStatus.Status.Group = group.GroupName()
+
+	if err := updateStatus(ctx, r.Client, groupWithStatus, nil); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.Recorder.Event(group, "Normal", "Synchronized", "GrafanaAlertRuleGroup synchronized")
+
+	return ctrl.Result{}, nil
+}
+
+// ruleGroupFolder returns the folder in which the rule group should live.
+func (r *GrafanaAlertRuleGroupReconciler) ruleGroupFolder(ctx context.Context, group *v1alpha1.GrafanaAlertRuleGroup) (grafana.DashboardFolder, error) {
+	if group.Spec.FolderRef == nil {
+		return grafana.DashboardFolder{Title: group.Spec.Folder}, nil
+	}
+
+	folder := &v1alpha1.GrafanaFolder{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: group.Namespace, Name: group.Spec.FolderRef.Name}, folder); err != nil {
+		return grafana.DashboardFolder{}, fmt.Errorf("could not fetch GrafanaFolder %s: %w", group.Spec.FolderRef.Name, err)
+	}
+
+	if !isSynchronized(folder) || folder.Status.UID == "" {
+		return grafana.DashboardFolder{}, fmt.Errorf("GrafanaFolder %s: %w", group.Spec.FolderRef.Name, ErrFolderNotReady)
+	}
+
+	return grafana.DashboardFolder{UID: folder.Status.UID}, nil
+}
+
+// conflictingRuleGroup returns the rule group stored under the same instance,
+// folder and name as the given one in Grafana, if any.
+func (r *GrafanaAlertRuleGroupReconciler) conflictingRuleGroup(ctx context.Context, group *v1alpha1.GrafanaAlertRuleGroup) (*v1alpha1.GrafanaAlertRuleGroup, error) {
+	list := &v1alpha1.GrafanaAlertRuleGroupList{}
+	if err := r.List(ctx, list, client.MatchingFields{alertRuleGroupIdentityIndex: ruleGroupIdentity(group)}); err != nil {
+		return nil, err
+	}
+
+	for i := range list.Items {
+		other := &list.Items[i]
+		if other.UID != group.UID && ruleGroupClaimsIdentityFirst(other, group) {
+			return other, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ruleGroupIdentity identifies a rule group in its Grafana instance: by its
+// folder and its name.
+func ruleGroupIdentity(group *v1alpha1.GrafanaAlertRuleGroup) string {
+	instance := grafana.InstanceKey(group, group.Spec.InstanceRef)
+
+	// Grafana matches folder titles regardless of their case
+	folder := "title/" + strings.ToLower(group.Spec.Folder)
+	if group.Spec.FolderRef != nil {
+		folder = "ref/" + group.Namespace + "/" + group.Spec.FolderRef.Name
+	}
+
+	return instance + "/" + folder + "/" + group.GroupName()
+}
+
+// ruleGroupClaimsIdentityFirst tells whether a rule group takes precedence
+// over another one sharing its folder and name: groups already synchronized
+// under their current folder and name keep them, otherwise the oldest
+// manifest wins.
+func ruleGroupClaimsIdentityFirst(a *v1alpha1.GrafanaAlertRuleGroup, b *v1alpha1.GrafanaAlertRuleGroup) bool {
+	aSynchronized := isSynchronizedUnderCurrentIdentity(a)
+	bSynchronized := isSynchronizedUnderCurrentIdentity(b)
+	if aSynchronized != bSynchronized {
+		return aSynchronized
+	}
+
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+
+	return a.Namespace+"/"+a.Name < b.Namespace+"/"+b.Name
+}
+
+func isSynchronizedUnderCurrentIdentity(group *v1alpha1.GrafanaAlertRuleGroup) bool {
+	if group.Status.Group != group.GroupName() || !isSynchronized(group) {
+		return false
+	}
+
+	return group.Spec.FolderRef != nil || strings.EqualFold(group.Status.Folder, group.Spec.Folder)
+}
+
+// requestsForConflictingRuleGroups enqueues the rule groups sharing the
+// folder and name of the given one, so that conflicts are re-evaluated.
+func requestsForConflictingRuleGroups(reader client.Reader) handler.MapFunc {
+	return func(object client.Object) []reconcile.Request {
+		group, ok := object.(*v1alpha1.GrafanaAlertRuleGroup)
+		if !ok {
+			return nil
+		}
+
+		list := &v1alpha1.GrafanaAlertRuleGroupList{}
+		if err := reader.List(context.Background(), list, client.MatchingFields{alertRuleGroupIdentityIndex: ruleGroupIdentity(group)}); err != nil {
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, other := range list.Items {
+			if other.UID == group.UID {
+				continue
+			}
+
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: other.Namespace, Name: other.Name},
+			})
+		}
+
+		return requests
+	}
+}
+
+// isRelocatedRuleGroup tells whether the rule group was previously
+// synchronized under another name or in another folder.
+func isRelocatedRuleGroup(group *v1alpha1.GrafanaAlertRuleGroup, folderTitle string) bool {
+	if group.Status.Group == "" {
+		return false
+	}
+
+	return group.Status.Group != group.GroupName() || group.Status.Folder != folderTitle
+}
+
+func StartGrafanaAlertRuleGroupReconciler(logger logr.Logger, ctrlManager ctrl.Manager, instances *grafana.Instances) error {
+	reconciler := &GrafanaAlertRuleGroupReconciler{
+		Client:    ctrlManager.GetClient(),
+		Scheme:    ctrlManager.GetScheme(),
+		Recorder:  ctrlManager.GetEventRecorderFor("grafanaalertrulegroup-controller"),
+		Instances: instances,
+		AlertRuleGroups: func(grafanaClient *grafana.Client) alertRuleGroupsManager {
+			return grafana.NewAlertRuleGroups(logger, grafanaClient, ctrlManager.GetClient())
+		},
+	}
+
+	return reconciler.SetupWithManager(ctrlManager)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaAlertRuleGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.GrafanaAlertRuleGroup{}, alertRuleGroupFolderIndex, func(object client.Object) []string {
+		folderRef := object.(*v1alpha1.GrafanaAlertRuleGroup).Spec.FolderRef
+		if folderRef == nil {
+			return nil
+		}
+
+		return []string{folderRef.Name}
+	})
+	if err != nil {
+		return err
+	}
+
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.GrafanaAlertRuleGroup{}, alertRuleGroupIdentityIndex, func(object client.Object) []string {
+		return []string{ruleGroupIdentity(object.(*v1alpha1.GrafanaAlertRuleGroup))}
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.GrafanaAlertRuleGroup{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Kind{Type: &v1alpha1.GrafanaFolder{}}, handler.EnqueueRequestsFromMapFunc(r.ruleGroupsForFolder)).
+		// rule groups sharing a folder and a name must be re-evaluated when one of them changes or goes away
+		Watches(&source.Kind{Type: &v1alpha1.GrafanaAlertRuleGroup{}}, handler.EnqueueRequestsFromMapFunc(requestsForConflictingRuleGroups(r.Client)), builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+// ruleGroupsForFolder lists the rule groups held by the given GrafanaFolder.
+func (r *GrafanaAlertRuleGroupReconciler) ruleGroupsForFolder(folder client.Object) []reconcile.Request {
+	groups := &v1alpha1.GrafanaAlertRuleGroupList{}
+	err := r.List(context.Background(), groups,
+		client.InNamespace(folder.GetNamespace()),
+		client.MatchingFields{alertRuleGroupFolderIndex: folder.GetName()},
+	)
+	if err != nil {
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(groups.Items))
+	for _, group := range groups.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(&group),
+		})
+	}
+
+	return requests
+}
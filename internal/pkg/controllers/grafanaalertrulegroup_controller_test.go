@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func alertRuleGroupManifest(specName string, status v1alpha1.GrafanaAlertRuleGroupStatus) *v1alpha1.GrafanaAlertRuleGroup {
+	return &v1alpha1.GrafanaAlertRuleGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "api"},
+		Spec:       v1alpha1.GrafanaAlertRuleGroupSpec{Name: specName},
+		Status:     status,
+	}
+}
+
+func TestNewRuleGroupsAreNotRelocated(t *testing.T) {
+	req := require.New(t)
+
+	req.False(isRelocatedRuleGroup(alertRuleGroupManifest("", v1alpha1.GrafanaAlertRuleGroupStatus{}), "Platform"))
+}
+
+func TestRuleGroupsSynchronizedInPlaceAreNotRelocated(t *testing.T) {
+	req := require.New(t)
+
+	group := alertRuleGroupManifest("", v1alpha1.GrafanaAlertRuleGroupStatus{Folder: "Platform", Group: "api"})
+
+	req.False(isRelocatedRuleGroup(group, "Platform"))
+}
+
+func TestRenamedRuleGroupsAreRelocated(t *testing.T) {
+	req := require.New(t)
+
+	group := alertRuleGroupManifest("api-errors", v1alpha1.GrafanaAlertRuleGroupStatus{Folder: "Platform", Group: "api"})
+
+	req.True(isRelocatedRuleGroup(group, "Platform"))
+}
+
+func TestRuleGroupsMovedToAnotherFolderAreRelocated(t *testing.T) {
+	req := require.New(t)
+
+	group := alertRuleGroupManifest("", v1alpha1.GrafanaAlertRuleGroupStatus{Folder: "Platform", Group: "api"})
+
+	req.True(isRelocatedRuleGroup(group, "Backend"))
+}
+
+func TestRuleGroupIdentities(t *testing.T) {
+	req := require.New(t)
+
+	group := alertRuleGroupManifest("", v1alpha1.GrafanaAlertRuleGroupStatus{})
+	group.Spec.Folder = "Platform"
+
+	req.Equal("/title/platform/api", ruleGroupIdentity(group))
+
+	group.Spec.FolderRef = &v1alpha1.FolderRef{Name: "platform"}
+	group.Spec.InstanceRef = &v1alpha1.InstanceRef{Name: "staging"}
+
+	req.Equal("default/staging/ref/default/platform/api", ruleGroupIdentity(group))
+}
+
+func TestSynchronizedRuleGroupsKeepTheirIdentity(t *testing.T) {
+	req := require.New(t)
+
+	now := time.Now()
+	older := alertRuleGroupManifest("", v1alpha1.GrafanaAlertRuleGroupStatus{})
+	older.Namespace = "team-a"
+	older.Spec.Folder = "Platform"
+	older.CreationTimestamp = metav1.NewTime(now.Add(-time.Hour))
+	newer := alertRuleGroupManifest("", v1alpha1.GrafanaAlertRuleGroupStatus{})
+	newer.Namespace = "team-b"
+	newer.Spec.Folder = "platform"
+	newer.CreationTimestamp = metav1.NewTime(now)
+
+	req.Equal(ruleGroupIdentity(older), ruleGroupIdentity(newer))
+	req.True(ruleGroupClaimsIdentityFirst(older, newer))
+	req.False(ruleGroupClaimsIdentityFirst(newer, older))
+
+	newer.Status = v1alpha1.GrafanaAlertRuleGroupStatus{Folder: "Platform", Group: "api"}
+	setSyncStatus(&newer.Status.SyncStatus, newer.Generation, nil)
+
+	req.True(ruleGroupClaimsIdentityFirst(newer, older))
+	req.False(ruleGroupClaimsIdentityFirst(older, newer))
+}
@@ -2,15 +2,34 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
 
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	k8skevingomezfrv1 "github.com/K-Phoen/dark/api/v1"
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/dark/internal/pkg/converter"
 	"github.com/K-Phoen/dark/internal/pkg/grafana"
-	"github.com/K-Phoen/grabana"
+	"github.com/K-Phoen/dark/internal/pkg/kubernetes"
+	"github.com/K-Phoen/dark/internal/pkg/metrics"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -18,11 +37,43 @@ import (
 )
 
 const grafanaDashboardFinalizerName = "grafanadashboards.k8s.kevingomez.fr/finalizer"
-const DashboardFolderAnnotation = "dark/folder"
+const DashboardDriftedCondition = "Drifted"
+
+var ErrFolderNotReady = fmt.Errorf("folder not ready")
+
+// dashboardConfigMapIndex indexes dashboards by the name of the ConfigMap
+// they are read from.
+const dashboardConfigMapIndex = ".spec.fromConfigMap.name"
+
+// dashboardFolderIndex indexes dashboards by the name of the GrafanaFolder
+// holding them.
+const dashboardFolderIndex = ".folderRef.name"
 
 type dashboardManager interface {
-	FromRawSpec(ctx context.Context, folderName string, uid string, rawJSON []byte) error
+	FromRawSpec(ctx context.Context, folder grafana.DashboardFolder, uid string, rawJSON []byte) (grafana.DeployedDashboard, error)
+	Drifted(ctx context.Context, folder grafana.DashboardFolder, uid string, rawJSON []byte) (bool, error)
+	SetPermissions(ctx context.Context, uid string, permissions []v1alpha1.Permission) error
 	Delete(ctx context.Context, uid string) error
+	DeleteFolderIfEmpty(ctx context.Context, uid string) error
+}
+
+type configMapReader interface {
+	Read(ctx context.Context, namespace string, ref corev1.ConfigMapKeySelector) (string, error)
+}
+
+type dashboardConverter interface {
+	ToSpec(input io.Reader) ([]byte, error)
+}
+
+// DriftDetection configures the periodic comparison of the dashboards held by
+// Grafana with their manifests.
+type DriftDetection struct {
+	// ResyncInterval is the interval at which dashboards are compared with
+	// Grafana. Zero disables drift detection.
+	ResyncInterval time.Duration
+
+	// ReportOnly flags drifted dashboards without overwriting them.
+	ReportOnly bool
 }
 
 // GrafanaDashboardReconciler reconciles a GrafanaDashboard object
@@ -32,15 +83,29 @@ type GrafanaDashboardReconciler struct {
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
 
-	Dashboards dashboardManager
+	Instances      grafanaInstances
+	Dashboards     func(grafanaClient *grafana.Client) dashboardManager
+	ConfigMaps     configMapReader
+	Converter      func(logger *zap.Logger) dashboardConverter
+	DriftDetection DriftDetection
 }
 
-func StartGrafanaDashboardReconciler(ctrlManager ctrl.Manager, grabanaClient *grabana.Client) error {
+func StartGrafanaDashboardReconciler(ctrlManager ctrl.Manager, instances *grafana.Instances, driftDetection DriftDetection) error {
 	reconciler := &GrafanaDashboardReconciler{
-		Client:     ctrlManager.GetClient(),
-		Scheme:     ctrlManager.GetScheme(),
-		Recorder:   ctrlManager.GetEventRecorderFor("grafanadashboard-controller"),
-		Dashboards: grafana.NewCreator(grabanaClient),
+		Client:    ctrlManager.GetClient(),
+		Scheme:    ctrlManager.GetScheme(),
+		Recorder:  ctrlManager.GetEventRecorderFor("grafanadashboard-controller"),
+		Instances: instances,
+		Dashboards: func(grafanaClient *grafana.Client) dashboardManager {
+			return grafana.NewCreator(grafanaClient)
+		},
+		ConfigMaps: kubernetes.NewConfigMaps(ctrlManager.GetLogger(), ctrlManager.GetClient()),
+		// conversion errors are reported in the dashboards' status, and
+		// conversion warnings as events
+		Converter: func(logger *zap.Logger) dashboardConverter {
+			return converter.NewJSON(logger)
+		},
+		DriftDetection: driftDetection,
 	}
 
 	return reconciler.SetupWithManager(ctrlManager)
@@ -49,6 +114,9 @@ func StartGrafanaDashboardReconciler(ctrlManager ctrl.Manager, grabanaClient *gr
 //+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanadashboards,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanadashboards/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanadashboards/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanafolders,verbs=get;list;watch
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanateams,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -68,6 +136,21 @@ func (r *GrafanaDashboardReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	grafanaClient, err := r.Instances.ClientFor(ctx, dashboard, dashboard.InstanceRef())
+	if err != nil {
+		logger.Error(err, "unable to resolve Grafana instance")
+
+		if isInstanceGone(err) && !dashboard.ObjectMeta.DeletionTimestamp.IsZero() {
+			return ctrl.Result{}, releaseFinalizer(ctx, r.Client, r.Recorder, dashboard, grafanaDashboardFinalizerName)
+		}
+
+		statusErr := updateStatus(ctx, r.Client, dashboard, err)
+		r.Recorder.Event(dashboard, "Warning", "Error", "could not resolve Grafana instance")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+	dashboards := r.Dashboards(grafanaClient)
+
 	// examine DeletionTimestamp to determine if object is under deletion
 	if dashboard.ObjectMeta.DeletionTimestamp.IsZero() {
 		// The object is not being deleted, so if it does not have our finalizer,
@@ -87,12 +170,16 @@ func (r *GrafanaDashboardReconciler) Reconcile(ctx context.Context, req ctrl.Req
 			logger.Info("finalizer found, deleting dashboard from grafana")
 
 			// our finalizer is present, so lets handle any external dependency
-			if err := r.Dashboards.Delete(ctx, dashboard.Name); err != nil {
+			if err := dashboards.Delete(ctx, dashboard.Name); err != nil {
 				// if fail to delete the external dependency here, return with error
 				// so that it can be retried
 				return ctrl.Result{}, err
 			}
 
+			if err := r.cleanupFolder(ctx, dashboards, dashboard); err != nil {
+				return ctrl.Result{}, err
+			}
+
 			// remove our finalizer from the list and update it.
 			controllerutil.RemoveFinalizer(dashboard, grafanaDashboardFinalizerName)
 			if err := r.Update(ctx, dashboard); err != nil {
@@ -104,57 +191,335 @@ func (r *GrafanaDashboardReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, nil
 	}
 
-	folder := dashboard.Annotations[DashboardFolderAnnotation]
-	if dashboard.Folder != "" {
-		folder = dashboard.Folder
+	folder, err := r.dashboardFolder(ctx, dashboard)
+	if err != nil {
+		logger.Error(err, "could not resolve GrafanaDashboard folder")
+
+		statusErr := updateStatus(ctx, r.Client, dashboard, err)
+		r.Recorder.Event(dashboard, "Warning", "Error", "could not resolve GrafanaDashboard folder")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+
+	spec, err := r.resolveSpec(ctx, dashboard)
+	if err != nil {
+		logger.Error(err, "could not resolve GrafanaDashboard spec")
+
+		statusErr := updateStatus(ctx, r.Client, dashboard, err)
+		r.Recorder.Event(dashboard, "Warning", "Error", "could not resolve GrafanaDashboard spec")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+	specHash := hashSpec(spec)
+
+	// the manifest didn't change since it was last applied: look for drifts
+	if r.DriftDetection.ResyncInterval != 0 && isSynchronized(dashboard) && dashboard.Status.SpecHash == specHash {
+		return r.correctDrift(ctx, dashboards, dashboard, folder, spec)
 	}
 
 	// proceed with create/update reconciliation
-	if err := r.Dashboards.FromRawSpec(ctx, folder, dashboard.ObjectMeta.Name, dashboard.Spec.Raw); err != nil {
+	deployed, err := dashboards.FromRawSpec(ctx, folder, dashboard.ObjectMeta.Name, spec)
+	if err != nil {
 		logger.Error(err, "could not apply GrafanaDashboard in Grafana")
 
-		r.updateStatus(ctx, dashboard, err)
+		statusErr := updateStatus(ctx, r.Client, dashboard, err)
 		r.Recorder.Event(dashboard, "Warning", "Error", "could not apply GrafanaDashboard in Grafana")
 
-		return ctrl.Result{}, err
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+
+	permissionsApplied, err := r.applyPermissions(ctx, dashboards, dashboard, deployed.UID)
+	if err != nil {
+		logger.Error(err, "could not apply GrafanaDashboard permissions in Grafana")
+
+		statusErr := updateStatus(ctx, r.Client, dashboard, err)
+		r.Recorder.Event(dashboard, "Warning", "Error", "could not apply GrafanaDashboard permissions in Grafana")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
 	}
 
 	logger.Info("done!")
 
-	r.updateStatus(ctx, dashboard, nil)
+	dashboardWithStatus := withDeployedDashboard(dashboard, deployed, specHash)
+	dashboardWithStatus.Status.PermissionsApplied = permissionsApplied
+
+	if err := updateStatus(ctx, r.Client, dashboardWithStatus, nil); err != nil {
+		return ctrl.Result{}, err
+	}
 	r.Recorder.Event(dashboard, "Normal", "Synchronized", "GrafanaDashboard synchronized")
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: r.DriftDetection.ResyncInterval}, nil
+}
+
+// dashboardFolder returns the folder in which the dashboard should live.
+func (r *GrafanaDashboardReconciler) dashboardFolder(ctx context.Context, dashboard *k8skevingomezfrv1.GrafanaDashboard) (grafana.DashboardFolder, error) {
+	if dashboard.FolderRef == nil {
+		title := dashboard.Annotations[k8skevingomezfrv1.DashboardFolderAnnotation]
+		if dashboard.Folder != "" {
+			title = dashboard.Folder
+		}
+
+		return grafana.DashboardFolder{Title: title}, nil
+	}
+
+	folder := &v1alpha1.GrafanaFolder{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: dashboard.Namespace, Name: dashboard.FolderRef.Name}, folder); err != nil {
+		return grafana.DashboardFolder{}, fmt.Errorf("could not fetch GrafanaFolder %s: %w", dashboard.FolderRef.Name, err)
+	}
+
+	if !isSynchronized(folder) || folder.Status.UID == "" {
+		return grafana.DashboardFolder{}, fmt.Errorf("GrafanaFolder %s: %w", dashboard.FolderRef.Name, ErrFolderNotReady)
+	}
+
+	return grafana.DashboardFolder{UID: folder.Status.UID}, nil
+}
+
+// applyPermissions replaces the permissions of the deployed dashboard, when
+// the manifest defines some. Permissions previously applied are removed once
+// the manifest doesn't define any: the dashboard then inherits the ones of its
+// folder. It returns whether permissions were applied.
+func (r *GrafanaDashboardReconciler) applyPermissions(ctx context.Context, dashboards dashboardManager, dashboard *k8skevingomezfrv1.GrafanaDashboard, uid string) (bool, error) {
+	if len(dashboard.Permissions) == 0 {
+		if !dashboard.Status.PermissionsApplied {
+			return false, nil
+		}
+
+		return false, dashboards.SetPermissions(ctx, uid, nil)
+	}
+
+	permissions, err := resolvePermissions(ctx, r.Client, dashboard.Namespace, dashboard.Permissions)
+	if err != nil {
+		return false, err
+	}
+
+	return true, dashboards.SetPermissions(ctx, uid, permissions)
+}
+
+// cleanupFolder deletes the folder that held a deleted dashboard if DARK
+// created it and it is now empty. Folders managed by a GrafanaFolder are left
+// untouched. When the folder still holds dashboards, one of them inherits the
+// responsibility of deleting it.
+func (r *GrafanaDashboardReconciler) cleanupFolder(ctx context.Context, dashboards dashboardManager, dashboard *k8skevingomezfrv1.GrafanaDashboard) error {
+	folderUID := dashboard.Status.FolderUID
+	if dashboard.FolderRef != nil || folderUID == "" || !dashboard.Status.FolderCreated {
+		return nil
+	}
+
+	folders := &v1alpha1.GrafanaFolderList{}
+	if err := r.List(ctx, folders); err != nil {
+		return err
+	}
+
+	for _, folder := range folders.Items {
+		if folder.Status.UID == folderUID {
+			return nil
+		}
+	}
+
+	siblings := &k8skevingomezfrv1.GrafanaDashboardList{}
+	if err := r.List(ctx, siblings); err != nil {
+		return err
+	}
+
+	instance := grafana.InstanceKey(dashboard, dashboard.InstanceRef())
+	for i := range siblings.Items {
+		sibling := &siblings.Items[i]
+		if sibling.UID == dashboard.UID || !sibling.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if sibling.Status.FolderUID != folderUID || grafana.InstanceKey(sibling, sibling.InstanceRef()) != instance {
+			continue
+		}
+
+		siblingCopy := sibling.DeepCopy()
+		siblingCopy.Status.FolderCreated = true
+
+		return r.Status().Update(ctx, siblingCopy)
+	}
+
+	return dashboards.DeleteFolderIfEmpty(ctx, folderUID)
+}
+
+// resolveSpec returns the JSON spec of the dashboard, converting it from a
+// Grafana JSON dashboard if needed.
+func (r *GrafanaDashboardReconciler) resolveSpec(ctx context.Context, dashboard *k8skevingomezfrv1.GrafanaDashboard) ([]byte, error) {
+	source, err := dashboard.Source()
+	if err != nil {
+		return nil, err
+	}
+
+	if !source.IsSet() {
+		return dashboard.DashboardSpec()
+	}
+
+	rawJSON := source.FromJSON
+	if source.FromConfigMap != nil {
+		rawJSON, err = r.ConfigMaps.Read(ctx, dashboard.Namespace, *source.FromConfigMap)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	warnings := newConversionWarnings()
+	spec, err := r.Converter(zap.New(warnings)).ToSpec(strings.NewReader(rawJSON))
+	if err != nil {
+		return nil, err
+	}
+
+	if messages := warnings.Messages(); len(messages) != 0 {
+		log.FromContext(ctx).Info("parts of the GrafanaDashboard JSON source could not be converted", "warnings", messages)
+		r.Recorder.Event(dashboard, "Warning", "ConversionWarnings", "parts of the dashboard could not be converted: "+strings.Join(messages, "; "))
+	}
+
+	return spec, nil
+}
+
+func (r *GrafanaDashboardReconciler) correctDrift(ctx context.Context, dashboards dashboardManager, dashboard *k8skevingomezfrv1.GrafanaDashboard, folder grafana.DashboardFolder, spec []byte) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	drifted, err := dashboards.Drifted(ctx, folder, dashboard.ObjectMeta.Name, spec)
+	if err != nil {
+		logger.Error(err, "could not check GrafanaDashboard for drifts")
+		return ctrl.Result{}, err
+	}
+
+	if !drifted {
+		statusErr := updateCondition(ctx, r.Client, dashboard, metav1.Condition{
+			Type:    DashboardDriftedCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "InSync",
+			Message: "Dashboard in Grafana matches its manifest",
+		})
+		return ctrl.Result{RequeueAfter: r.DriftDetection.ResyncInterval}, statusErr
+	}
+
+	logger.Info("drift detected")
+	metrics.RecordReconcile(dashboard, metrics.ResultDrift)
+	r.Recorder.Event(dashboard, "Warning", "Drifted", "GrafanaDashboard in Grafana drifted from its manifest")
+
+	if r.DriftDetection.ReportOnly {
+		statusErr := updateCondition(ctx, r.Client, dashboard, metav1.Condition{
+			Type:    DashboardDriftedCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  "DriftDetected",
+			Message: "Dashboard in Grafana differs from its manifest",
+		})
+		return ctrl.Result{RequeueAfter: r.DriftDetection.ResyncInterval}, statusErr
+	}
+
+	deployed, err := dashboards.FromRawSpec(ctx, folder, dashboard.ObjectMeta.Name, spec)
+	if err != nil {
+		logger.Error(err, "could not correct GrafanaDashboard drift in Grafana")
+
+		statusErr := updateCondition(ctx, r.Client, dashboard, metav1.Condition{
+			Type:    DashboardDriftedCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  "CorrectionFailed",
+			Message: err.Error(),
+		})
+		r.Recorder.Event(dashboard, "Warning", "Error", "could not correct GrafanaDashboard drift in Grafana")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+
+	dashboardWithStatus := withDeployedDashboard(dashboard, deployed, hashSpec(spec))
+	meta.SetStatusCondition(&dashboardWithStatus.Status.Conditions, metav1.Condition{
+		Type:               DashboardDriftedCondition,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: dashboard.Generation,
+		Reason:             "DriftCorrected",
+		Message:            "Dashboard in Grafana was re-synchronized with its manifest",
+	})
+	if err := updateStatus(ctx, r.Client, dashboardWithStatus, nil); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.Recorder.Event(dashboard, "Normal", "Synchronized", "GrafanaDashboard drift corrected")
+
+	return ctrl.Result{RequeueAfter: r.DriftDetection.ResyncInterval}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *GrafanaDashboardReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &k8skevingomezfrv1.GrafanaDashboard{}, dashboardConfigMapIndex, func(object client.Object) []string {
+		source, err := object.(*k8skevingomezfrv1.GrafanaDashboard).Source()
+		if err != nil || source.FromConfigMap == nil {
+			return nil
+		}
+
+		return []string{source.FromConfigMap.Name}
+	})
+	if err != nil {
+		return err
+	}
+
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &k8skevingomezfrv1.GrafanaDashboard{}, dashboardFolderIndex, func(object client.Object) []string {
+		folderRef := object.(*k8skevingomezfrv1.GrafanaDashboard).FolderRef
+		if folderRef == nil {
+			return nil
+		}
+
+		return []string{folderRef.Name}
+	})
+	if err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&k8skevingomezfrv1.GrafanaDashboard{}).
-		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		For(&k8skevingomezfrv1.GrafanaDashboard{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.dashboardsForConfigMap)).
+		Watches(&source.Kind{Type: &v1alpha1.GrafanaFolder{}}, handler.EnqueueRequestsFromMapFunc(r.dashboardsForFolder)).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: 5,
 		}).
 		Complete(r)
 }
 
-func (r *GrafanaDashboardReconciler) updateStatus(ctx context.Context, dashboard *k8skevingomezfrv1.GrafanaDashboard, err error) {
-	logger := log.FromContext(ctx)
+// dashboardsForConfigMap lists the dashboards read from the given ConfigMap.
+func (r *GrafanaDashboardReconciler) dashboardsForConfigMap(configMap client.Object) []reconcile.Request {
+	return r.dashboardsMatching(configMap.GetNamespace(), dashboardConfigMapIndex, configMap.GetName())
+}
 
-	// NEVER modify objects from the store. It's a read-only, local cache.
-	// You can use DeepCopy() to make a deep copy of original object and modify this copy
-	// Or create a copy manually for better performance
-	dashboardCopy := dashboard.DeepCopy()
+// dashboardsForFolder lists the dashboards held by the given GrafanaFolder.
+func (r *GrafanaDashboardReconciler) dashboardsForFolder(folder client.Object) []reconcile.Request {
+	return r.dashboardsMatching(folder.GetNamespace(), dashboardFolderIndex, folder.GetName())
+}
 
-	if err == nil {
-		dashboardCopy.Status.Status = "OK"
-		dashboardCopy.Status.Message = "Synchronized"
-	} else {
-		dashboardCopy.Status.Status = "Error"
-		dashboardCopy.Status.Message = err.Error()
+func (r *GrafanaDashboardReconciler) dashboardsMatching(namespace string, index string, value string) []reconcile.Request {
+	dashboards := &k8skevingomezfrv1.GrafanaDashboardList{}
+	err := r.List(context.Background(), dashboards,
+		client.InNamespace(namespace),
+		client.MatchingFields{index: value},
+	)
+	if err != nil {
+		return nil
 	}
 
-	if err := r.Status().Update(ctx, dashboardCopy); err != nil {
-		logger.Error(err, "unable to update GrafanaDashboard status")
+	requests := make([]reconcile.Request, 0, len(dashboards.Items))
+	for _, dashboard := range dashboards.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(&dashboard),
+		})
 	}
+
+	return requests
+}
+
+func hashSpec(spec []byte) string {
+	hash := sha256.Sum256(spec)
+
+	return hex.EncodeToString(hash[:])
+}
+
+func withDeployedDashboard(dashboard *k8skevingomezfrv1.GrafanaDashboard, deployed grafana.DeployedDashboard, specHash string) *k8skevingomezfrv1.GrafanaDashboard {
+	dashboardCopy := dashboard.DeepCopy()
+
+	dashboardCopy.Status.UID = deployed.UID
+	dashboardCopy.Status.URL = deployed.URL
+	dashboardCopy.Status.Version = int64(deployed.Version)
+	dashboardCopy.Status.FolderCreated = deployed.FolderCreated ||
+		(dashboard.Status.FolderCreated && dashboard.Status.FolderUID == deployed.FolderUID)
+	dashboardCopy.Status.FolderUID = deployed.FolderUID
+	dashboardCopy.Status.SpecHash = specHash
+
+	return dashboardCopy
 }
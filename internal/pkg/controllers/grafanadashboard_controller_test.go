@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	k8skevingomezfrv1 "github.com/K-Phoen/dark/api/v1"
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/dark/internal/pkg/grafana"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type stubDashboardManager struct {
+	drifted     bool
+	deployed    []string
+	permissions [][]v1alpha1.Permission
+}
+
+func (manager *stubDashboardManager) FromRawSpec(_ context.Context, folder grafana.DashboardFolder, uid string, _ []byte) (grafana.DeployedDashboard, error) {
+	manager.deployed = append(manager.deployed, uid)
+
+	return grafana.DeployedDashboard{UID: uid, URL: "/d/" + uid, Version: 5, FolderUID: folder.UID}, nil
+}
+
+func (manager *stubDashboardManager) Drifted(_ context.Context, _ grafana.DashboardFolder, _ string, _ []byte) (bool, error) {
+	return manager.drifted, nil
+}
+
+func (manager *stubDashboardManager) SetPermissions(_ context.Context, _ string, permissions []v1alpha1.Permission) error {
+	manager.permissions = append(manager.permissions, permissions)
+
+	return nil
+}
+
+func (manager *stubDashboardManager) Delete(_ context.Context, _ string) error {
+	return nil
+}
+
+func (manager *stubDashboardManager) DeleteFolderIfEmpty(_ context.Context, _ string) error {
+	return nil
+}
+
+// statusRecorder records the objects whose status was updated.
+type statusRecorder struct {
+	client.Client
+
+	updated []client.Object
+	err     error
+}
+
+func (recorder *statusRecorder) Status() client.SubResourceWriter {
+	return statusWriter{recorder: recorder}
+}
+
+type statusWriter struct {
+	client.SubResourceWriter
+
+	recorder *statusRecorder
+}
+
+func (writer statusWriter) Update(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+	if writer.recorder.err != nil {
+		return writer.recorder.err
+	}
+
+	writer.recorder.updated = append(writer.recorder.updated, obj)
+	return nil
+}
+
+func testDriftReconciler(reportOnly bool) (*GrafanaDashboardReconciler, *statusRecorder, *record.FakeRecorder) {
+	statusClient := &statusRecorder{}
+	events := record.NewFakeRecorder(10)
+
+	return &GrafanaDashboardReconciler{
+		Client:   statusClient,
+		Recorder: events,
+		DriftDetection: DriftDetection{
+			ResyncInterval: time.Minute,
+			ReportOnly:     reportOnly,
+		},
+	}, statusClient, events
+}
+
+func syncedDashboard() *k8skevingomezfrv1.GrafanaDashboard {
+	return &k8skevingomezfrv1.GrafanaDashboard{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "platform", Generation: 2},
+		Status: k8skevingomezfrv1.GrafanaDashboardStatus{
+			UID:       "api",
+			Version:   4,
+			FolderUID: "platform-uid",
+		},
+	}
+}
+
+func updatedDashboard(t *testing.T, statusClient *statusRecorder) *k8skevingomezfrv1.GrafanaDashboard {
+	t.Helper()
+
+	require.Len(t, statusClient.updated, 1)
+
+	return statusClient.updated[0].(*k8skevingomezfrv1.GrafanaDashboard)
+}
+
+func TestDashboardsInSyncAreLeftUntouched(t *testing.T) {
+	req := require.New(t)
+
+	reconciler, statusClient, events := testDriftReconciler(false)
+	dashboards := &stubDashboardManager{drifted: false}
+
+	result, err := reconciler.correctDrift(context.Background(), dashboards, syncedDashboard(), grafana.DashboardFolder{UID: "platform-uid"}, []byte("{}"))
+	req.NoError(err)
+
+	req.Equal(time.Minute, result.RequeueAfter)
+	req.Empty(dashboards.deployed)
+	req.Empty(events.Events)
+
+	condition := meta.FindStatusCondition(updatedDashboard(t, statusClient).Status.Conditions, DashboardDriftedCondition)
+	req.NotNil(condition)
+	req.Equal(metav1.ConditionFalse, condition.Status)
+	req.Equal("InSync", condition.Reason)
+}
+
+func TestDriftsAreOnlyReportedInReportOnlyMode(t *testing.T) {
+	req := require.New(t)
+
+	reconciler, statusClient, events := testDriftReconciler(true)
+	dashboards := &stubDashboardManager{drifted: true}
+
+	result, err := reconciler.correctDrift(context.Background(), dashboards, syncedDashboard(), grafana.DashboardFolder{UID: "platform-uid"}, []byte("{}"))
+	req.NoError(err)
+
+	req.Equal(time.Minute, result.RequeueAfter)
+	req.Empty(dashboards.deployed)
+	req.Len(events.Events, 1)
+	req.Contains(<-events.Events, "Drifted")
+
+	condition := meta.FindStatusCondition(updatedDashboard(t, statusClient).Status.Conditions, DashboardDriftedCondition)
+	req.NotNil(condition)
+	req.Equal(metav1.ConditionTrue, condition.Status)
+	req.Equal("DriftDetected", condition.Reason)
+}
+
+func TestDriftsAreCorrected(t *testing.T) {
+	req := require.New(t)
+
+	reconciler, statusClient, events := testDriftReconciler(false)
+	dashboards := &stubDashboardManager{drifted: true}
+
+	result, err := reconciler.correctDrift(context.Background(), dashboards, syncedDashboard(), grafana.DashboardFolder{UID: "platform-uid"}, []byte("{}"))
+	req.NoError(err)
+
+	req.Equal(time.Minute, result.RequeueAfter)
+	req.Equal([]string{"api"}, dashboards.deployed)
+	req.Len(events.Events, 2)
+	req.Contains(<-events.Events, "Drifted")
+	req.Contains(<-events.Events, "drift corrected")
+
+	updated := updatedDashboard(t, statusClient)
+	req.Equal(int64(5), updated.Status.Version)
+
+	condition := meta.FindStatusCondition(updated.Status.Conditions, DashboardDriftedCondition)
+	req.NotNil(condition)
+	req.Equal(metav1.ConditionFalse, condition.Status)
+	req.Equal("DriftCorrected", condition.Reason)
+}
+
+func TestPermissionsAreResetOnceRemovedFromTheManifest(t *testing.T) {
+	req := require.New(t)
+
+	reconciler, _, _ := testDriftReconciler(false)
+	dashboards := &stubDashboardManager{}
+	dashboard := syncedDashboard()
+	dashboard.Status.PermissionsApplied = true
+
+	applied, err := reconciler.applyPermissions(context.Background(), dashboards, dashboard, "api")
+	req.NoError(err)
+
+	req.False(applied)
+	req.Len(dashboards.permissions, 1)
+	req.Empty(dashboards.permissions[0])
+}
+
+func TestPermissionsNotAppliedAreLeftUntouched(t *testing.T) {
+	req := require.New(t)
+
+	reconciler, _, _ := testDriftReconciler(false)
+	dashboards := &stubDashboardManager{}
+
+	applied, err := reconciler.applyPermissions(context.Background(), dashboards, syncedDashboard(), "api")
+	req.NoError(err)
+
+	req.False(applied)
+	req.Empty(dashboards.permissions)
+}
+
+func TestPermissionsAreApplied(t *testing.T) {
+	req := require.New(t)
+
+	reconciler, _, _ := testDriftReconciler(false)
+	dashboards := &stubDashboardManager{}
+	dashboard := syncedDashboard()
+	dashboard.Permissions = []v1alpha1.Permission{{Role: "Viewer", Permission: "view"}}
+
+	applied, err := reconciler.applyPermissions(context.Background(), dashboards, dashboard, "api")
+	req.NoError(err)
+
+	req.True(applied)
+	req.Equal([][]v1alpha1.Permission{{{Role: "Viewer", Permission: "view"}}}, dashboards.permissions)
+}
+
+func TestInstanceRefIsReadFromTheSpecAndLeftOutOfTheDashboard(t *testing.T) {
+	req := require.New(t)
+
+	reconciler, _, _ := testDriftReconciler(false)
+	dashboard := syncedDashboard()
+	dashboard.Spec.Raw = []byte(`{"instanceRef": {"name": "staging", "namespace": "monitoring"}, "title": "API"}`)
+
+	req.Equal(&v1alpha1.InstanceRef{Name: "staging", Namespace: "monitoring"}, dashboard.InstanceRef())
+	req.Equal("monitoring/staging", grafana.InstanceKey(dashboard, dashboard.InstanceRef()))
+
+	spec, err := reconciler.resolveSpec(context.Background(), dashboard)
+	req.NoError(err)
+	req.JSONEq(`{"title": "API"}`, string(spec))
+}
+
+func TestSpecsWithoutInstanceRefAreLeftUntouched(t *testing.T) {
+	req := require.New(t)
+
+	reconciler, _, _ := testDriftReconciler(false)
+	dashboard := syncedDashboard()
+	dashboard.Spec.Raw = []byte(`{"title": "API"}`)
+
+	req.Nil(dashboard.InstanceRef())
+
+	spec, err := reconciler.resolveSpec(context.Background(), dashboard)
+	req.NoError(err)
+	req.Equal(dashboard.Spec.Raw, spec)
+}
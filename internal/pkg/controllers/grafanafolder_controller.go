@@ -0,0 +1,278 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	k8skevingomezfrv1 "github.com/K-Phoen/dark/api/v1"
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/dark/internal/pkg/grafana"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const grafanaFoldersFinalizerName = "grafanafolders.k8s.kevingomez.fr/finalizer"
+
+// folderDeletionRetryInterval is the delay after which the deletion of a
+// folder still holding dashboards is retried.
+const folderDeletionRetryInterval = 30 * time.Second
+
+type foldersManager interface {
+	Upsert(ctx context.Context, folder grafana.Folder) error
+	Delete(ctx context.Context, uid string) (bool, error)
+}
+
+// GrafanaFolderReconciler reconciles a GrafanaFolder object
+type GrafanaFolderReconciler struct {
+	client.Client
+
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	Instances grafanaInstances
+	Folders   func(grafanaClient *grafana.Client) foldersManager
+}
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
+func (r *GrafanaFolderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	logger.Info("reconciling")
+
+	folder := &v1alpha1.GrafanaFolder{}
+	if err := r.Get(ctx, req.NamespacedName, folder); err != nil {
+		logger.Error(err, "unable to fetch GrafanaFolder")
+		// we'll ignore not-found errors, since they can't be fixed by an immediate
+		// requeue (we'll need to wait for a new notification), and we can get them
+		// on deleted requests.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	grafanaClient, err := r.Instances.ClientFor(ctx, folder, folder.Spec.InstanceRef)
+	if err != nil {
+		logger.Error(err, "unable to resolve Grafana instance")
+
+		if isInstanceGone(err) && !folder.ObjectMeta.DeletionTimestamp.IsZero() {
+			return ctrl.Result{}, releaseFinalizer(ctx, r.Client, r.Recorder, folder, grafanaFoldersFinalizerName)
+		}
+
+		statusErr := updateStatus(ctx, r.Client, folder, err)
+		r.Recorder.Event(folder, "Warning", "Error", "could not resolve Grafana instance")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+	folders := r.Folders(grafanaClient)
+
+	// examine DeletionTimestamp to determine if object is under deletion
+	if folder.ObjectMeta.DeletionTimestamp.IsZero() {
+		// The object is not being deleted, so if it does not have our finalizer,
+		// then lets add the finalizer and update the object. This is equivalent
+		// registering our finalizer.
+		if !containsString(folder.GetFinalizers(), grafanaFoldersFinalizerName) {
+			controllerutil.AddFinalizer(folder, grafanaFoldersFinalizerName)
+			if err := r.Update(ctx, folder); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	} else {
+		logger.Info("deleting GrafanaFolder")
+
+		// The object is being deleted
+		if containsString(folder.GetFinalizers(), grafanaFoldersFinalizerName) {
+			return r.finalize(ctx, folders, folder)
+		}
+
+		// Stop reconciliation as the item is being deleted
+		return ctrl.Result{}, nil
+	}
+
+	parentUID, err := r.parentUID(ctx, folder)
+	if err != nil {
+		logger.Error(err, "could not resolve parent folder")
+
+		statusErr := updateStatus(ctx, r.Client, folder, err)
+		r.Recorder.Event(folder, "Warning", "Error", "could not resolve parent folder")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+
+	permissions, err := resolvePermissions(ctx, r.Client, folder.Namespace, folder.Spec.Permissions)
+	if err != nil {
+		logger.Error(err, "could not resolve folder permissions")
+
+		statusErr := updateStatus(ctx, r.Client, folder, err)
+		r.Recorder.Event(folder, "Warning", "Error", "could not resolve folder permissions")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+
+	// proceed with create/update reconciliation
+	err = folders.Upsert(ctx, grafana.Folder{
+		UID:         folder.FolderUID(),
+		Title:       folder.Spec.Title,
+		ParentUID:   parentUID,
+		Permissions: permissions,
+
+		ResetPermissions: folder.Status.PermissionsApplied,
+	})
+	if err != nil {
+		logger.Error(err, "could not upsert GrafanaFolder in Grafana")
+
+		statusErr := updateStatus(ctx, r.Client, folder, err)
+		r.Recorder.Event(folder, "Warning", "Error", "could not synchronize GrafanaFolder with Grafana")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+
+	logger.Info("done!")
+
+	folderWithStatus := folder.DeepCopy()
+	folderWithStatus.Status.UID = folder.FolderUID()
+	folderWithStatus.Status.PermissionsApplied = len(permissions) != 0
+
+	if err := updateStatus(ctx, r.Client, folderWithStatus, nil); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.Recorder.Event(folder, "Normal", "Synchronized", "GrafanaFolder synchronized")
+
+	return ctrl.Result{}, nil
+}
+
+// finalize deletes the folder from Grafana, once no GrafanaDashboard or
+// GrafanaAlertRuleGroup references it anymore. Folders still holding dashboards not managed by the
+// operator are left in Grafana.
+func (r *GrafanaFolderReconciler) finalize(ctx context.Context, folders foldersManager, folder *v1alpha1.GrafanaFolder) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	referenced, err := r.isReferenced(ctx, folder)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if referenced {
+		logger.Info("GrafanaFolder still referenced, postponing its deletion")
+		r.Recorder.Event(folder, "Warning", "FolderInUse", "GrafanaFolder is still referenced by GrafanaDashboards, GrafanaAlertRuleGroups or GrafanaFolders")
+
+		return ctrl.Result{RequeueAfter: folderDeletionRetryInterval}, nil
+	}
+
+	if folder.Status.UID != "" {
+		logger.Info("finalizer found, deleting folder from grafana")
+
+		// our finalizer is present, so lets handle any external dependency
+		deleted, err := folders.Delete(ctx, folder.Status.UID)
+		if err != nil {
+			// if fail to delete the external dependency here, return with error
+			// so that it can be retried
+			return ctrl.Result{}, err
+		}
+		if !deleted {
+			r.Recorder.Event(folder, "Warning", "FolderNotEmpty", "folder not deleted from Grafana as it is not empty")
+		}
+	}
+
+	// remove our finalizer from the list and update it.
+	controllerutil.RemoveFinalizer(folder, grafanaFoldersFinalizerName)
+	if err := r.Update(ctx, folder); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// isReferenced tells whether a GrafanaDashboard, a GrafanaAlertRuleGroup or
+// another GrafanaFolder references the given folder.
+func (r *GrafanaFolderReconciler) isReferenced(ctx context.Context, folder *v1alpha1.GrafanaFolder) (bool, error) {
+	dashboards := &k8skevingomezfrv1.GrafanaDashboardList{}
+	if err := r.List(ctx, dashboards, client.InNamespace(folder.Namespace)); err != nil {
+		return false, err
+	}
+
+	for _, dashboard := range dashboards.Items {
+		if dashboard.FolderRef != nil && dashboard.FolderRef.Name == folder.Name {
+			return true, nil
+		}
+	}
+
+	ruleGroups := &v1alpha1.GrafanaAlertRuleGroupList{}
+	if err := r.List(ctx, ruleGroups, client.InNamespace(folder.Namespace)); err != nil {
+		return false, err
+	}
+
+	for _, ruleGroup := range ruleGroups.Items {
+		if ruleGroup.Spec.FolderRef != nil && ruleGroup.Spec.FolderRef.Name == folder.Name {
+			return true, nil
+		}
+	}
+
+	subFolders := &v1alpha1.GrafanaFolderList{}
+	if err := r.List(ctx, subFolders, client.InNamespace(folder.Namespace)); err != nil {
+		return false, err
+	}
+
+	for _, subFolder := range subFolders.Items {
+		if subFolder.Spec.ParentRef != nil && subFolder.Spec.ParentRef.Name == folder.Name {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r *GrafanaFolderReconciler) parentUID(ctx context.Context, folder *v1alpha1.GrafanaFolder) (string, error) {
+	if folder.Spec.ParentRef == nil {
+		return "", nil
+	}
+
+	parent := &v1alpha1.GrafanaFolder{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: folder.Namespace, Name: folder.Spec.ParentRef.Name}, parent); err != nil {
+		return "", fmt.Errorf("could not fetch parent GrafanaFolder %s: %w", folder.Spec.ParentRef.Name, err)
+	}
+
+	if !isSynchronized(parent) || parent.Status.UID == "" {
+		return "", fmt.Errorf("parent GrafanaFolder %s: %w", folder.Spec.ParentRef.Name, ErrFolderNotReady)
+	}
+
+	return parent.Status.UID, nil
+}
+
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanafolders,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanafolders/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanafolders/finalizers,verbs=update
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanadashboards,verbs=get;list;watch
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanaalertrulegroups,verbs=get;list;watch
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanateams,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+func StartGrafanaFolderReconciler(logger logr.Logger, ctrlManager ctrl.Manager, instances *grafana.Instances) error {
+	reconciler := &GrafanaFolderReconciler{
+		Client:    ctrlManager.GetClient(),
+		Scheme:    ctrlManager.GetScheme(),
+		Recorder:  ctrlManager.GetEventRecorderFor("grafanafolder-controller"),
+		Instances: instances,
+		Folders: func(grafanaClient *grafana.Client) foldersManager {
+			return grafana.NewFolders(logger, grafanaClient)
+		},
+	}
+
+	return reconciler.SetupWithManager(ctrlManager)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaFolderReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.GrafanaFolder{}).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(r)
+}
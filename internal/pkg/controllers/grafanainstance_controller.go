@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/dark/internal/pkg/grafana"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// GrafanaInstanceReconciler reconciles a GrafanaInstance object
+type GrafanaInstanceReconciler struct {
+	client.Client
+
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	Instances *grafana.Instances
+}
+
+func StartGrafanaInstanceReconciler(ctrlManager ctrl.Manager, instances *grafana.Instances) error {
+	reconciler := &GrafanaInstanceReconciler{
+		Client:    ctrlManager.GetClient(),
+		Scheme:    ctrlManager.GetScheme(),
+		Recorder:  ctrlManager.GetEventRecorderFor("grafanainstance-controller"),
+		Instances: instances,
+	}
+
+	return reconciler.SetupWithManager(ctrlManager)
+}
+
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanainstances,verbs=get;list;watch
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanainstances/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
+func (r *GrafanaInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	logger.Info("reconciling")
+
+	instance := &v1alpha1.GrafanaInstance{}
+	if err := r.Get(ctx, req.NamespacedName, instance); err != nil {
+		logger.Error(err, "unable to fetch GrafanaInstance")
+
+		// the instance is gone: so should its client
+		r.Instances.Forget(req.NamespacedName)
+
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// drop any previously cached client and make sure a new one can be built
+	r.Instances.Forget(req.NamespacedName)
+
+	grafanaClient, err := r.Instances.ClientForInstance(ctx, instance)
+	if err != nil {
+		logger.Error(err, "could not create client for GrafanaInstance")
+
+		statusErr := updateStatus(ctx, r.Client, instance, err)
+		r.Recorder.Event(instance, "Warning", "Error", "could not create client for GrafanaInstance")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+
+	if err := grafanaClient.Health(ctx); err != nil {
+		logger.Error(err, "GrafanaInstance is not healthy")
+
+		statusErr := updateStatus(ctx, r.Client, instance, err)
+		r.Recorder.Event(instance, "Warning", "Error", "GrafanaInstance is not healthy")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+
+	if err := updateStatus(ctx, r.Client, instance, nil); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.Recorder.Event(instance, "Normal", "Synchronized", "GrafanaInstance ready")
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaInstanceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.GrafanaInstance{}).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(r)
+}
@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/dark/internal/pkg/grafana"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const grafanaSilencesFinalizerName = "grafanasilences.k8s.kevingomez.fr/finalizer"
+
+type silencesManager interface {
+	Upsert(ctx context.Context, id string, spec v1alpha1.GrafanaSilenceSpec) (string, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// GrafanaSilenceReconciler reconciles a GrafanaSilence object
+type GrafanaSilenceReconciler struct {
+	client.Client
+
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	Instances grafanaInstances
+	Silences  func(grafanaClient *grafana.Client) silencesManager
+}
+
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanasilences,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanasilences/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanasilences/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
+func (r *GrafanaSilenceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	logger.Info("reconciling")
+
+	silence := &v1alpha1.GrafanaSilence{}
+	if err := r.Get(ctx, req.NamespacedName, silence); err != nil {
+		logger.Error(err, "unable to fetch GrafanaSilence")
+		// we'll ignore not-found errors, since they can't be fixed by an immediate
+		// requeue (we'll need to wait for a new notification), and we can get them
+		// on deleted requests.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	grafanaClient, err := r.Instances.ClientFor(ctx, silence, silence.Spec.InstanceRef)
+	if err != nil {
+		logger.Error(err, "unable to resolve Grafana instance")
+
+		if isInstanceGone(err) && !silence.ObjectMeta.DeletionTimestamp.IsZero() {
+			return ctrl.Result{}, releaseFinalizer(ctx, r.Client, r.Recorder, silence, grafanaSilencesFinalizerName)
+		}
+
+		statusErr := updateStatus(ctx, r.Client, silence, err)
+		r.Recorder.Event(silence, "Warning", "Error", "could not resolve Grafana instance")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+	silences := r.Silences(grafanaClient)
+
+	// examine DeletionTimestamp to determine if object is under deletion
+	if silence.ObjectMeta.DeletionTimestamp.IsZero() {
+		// The object is not being deleted, so if it does not have our finalizer,
+		// then lets add the finalizer and update the object. This is equivalent
+		// registering our finalizer.
+		if !containsString(silence.GetFinalizers(), grafanaSilencesFinalizerName) {
+			controllerutil.AddFinalizer(silence, grafanaSilencesFinalizerName)
+			if err := r.Update(ctx, silence); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	} else {
+		logger.Info("deleting GrafanaSilence")
+
+		// The object is being deleted
+		if containsString(silence.GetFinalizers(), grafanaSilencesFinalizerName) {
+			logger.Info("finalizer found, expiring silence in grafana")
+
+			// our finalizer is present, so lets handle any external dependency
+			if silence.Status.ID != "" {
+				if err := silences.Delete(ctx, silence.Status.ID); err != nil {
+					// if fail to delete the external dependency here, return with error
+					// so that it can be retried
+					return ctrl.Result{}, err
+				}
+			}
+
+			// remove our finalizer from the list and update it.
+			controllerutil.RemoveFinalizer(silence, grafanaSilencesFinalizerName)
+			if err := r.Update(ctx, silence); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		// Stop reconciliation as the item is being deleted
+		return ctrl.Result{}, nil
+	}
+
+	// proceed with create/update reconciliation
+	id, err := silences.Upsert(ctx, silence.Status.ID, silence.Spec)
+	if err != nil {
+		logger.Error(err, "could not upsert GrafanaSilence in Grafana")
+
+		statusErr := updateStatus(ctx, r.Client, silence, err)
+		r.Recorder.Event(silence, "Warning", "Error", "could not synchronize GrafanaSilence with Grafana")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+
+	logger.Info("done!")
+
+	silenceWithStatus := silence.DeepCopy()
+	silenceWithStatus.Status.ID = id
+
+	if err := updateStatus(ctx, r.Client, silenceWithStatus, nil); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.Recorder.Event(silence, "Normal", "Synchronized", "GrafanaSilence synchronized")
+
+	return ctrl.Result{}, nil
+}
+
+func StartGrafanaSilenceReconciler(logger logr.Logger, ctrlManager ctrl.Manager, instances *grafana.Instances) error {
+	reconciler := &GrafanaSilenceReconciler{
+		Client:    ctrlManager.GetClient(),
+		Scheme:    ctrlManager.GetScheme(),
+		Recorder:  ctrlManager.GetEventRecorderFor("grafanasilence-controller"),
+		Instances: instances,
+		Silences: func(grafanaClient *grafana.Client) silencesManager {
+			return grafana.NewSilences(logger, grafanaClient)
+		},
+	}
+
+	return reconciler.SetupWithManager(ctrlManager)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaSilenceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.GrafanaSilence{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/dark/internal/pkg/grafana"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const grafanaTeamsFinalizerName = "grafanateams.k8s.kevingomez.fr/finalizer"
+
+type teamsManager interface {
+	Upsert(ctx context.Context, team grafana.Team) (int64, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// GrafanaTeamReconciler reconciles a GrafanaTeam object
+type GrafanaTeamReconciler struct {
+	client.Client
+
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	Instances grafanaInstances
+	Teams     func(grafanaClient *grafana.Client) teamsManager
+}
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
+func (r *GrafanaTeamReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	logger.Info("reconciling")
+
+	team := &v1alpha1.GrafanaTeam{}
+	if err := r.Get(ctx, req.NamespacedName, team); err != nil {
+		logger.Error(err, "unable to fetch GrafanaTeam")
+		// we'll ignore not-found errors, since they can't be fixed by an immediate
+		// requeue (we'll need to wait for a new notification), and we can get them
+		// on deleted requests.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	grafanaClient, err := r.Instances.ClientFor(ctx, team, team.Spec.InstanceRef)
+	if err != nil {
+		logger.Error(err, "unable to resolve Grafana instance")
+
+		if isInstanceGone(err) && !team.ObjectMeta.DeletionTimestamp.IsZero() {
+			return ctrl.Result{}, releaseFinalizer(ctx, r.Client, r.Recorder, team, grafanaTeamsFinalizerName)
+		}
+
+		statusErr := updateStatus(ctx, r.Client, team, err)
+		r.Recorder.Event(team, "Warning", "Error", "could not resolve Grafana instance")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+	teams := r.Teams(grafanaClient)
+
+	// examine DeletionTimestamp to determine if object is under deletion
+	if team.ObjectMeta.DeletionTimestamp.IsZero() {
+		// The object is not being deleted, so if it does not have our finalizer,
+		// then lets add the finalizer and update the object. This is equivalent
+		// registering our finalizer.
+		if !containsString(team.GetFinalizers(), grafanaTeamsFinalizerName) {
+			controllerutil.AddFinalizer(team, grafanaTeamsFinalizerName)
+			if err := r.Update(ctx, team); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	} else {
+		logger.Info("deleting GrafanaTeam")
+
+		// The object is being deleted
+		if containsString(team.GetFinalizers(), grafanaTeamsFinalizerName) {
+			logger.Info("finalizer found, deleting team from grafana")
+
+			// our finalizer is present, so lets handle any external dependency
+			if team.Status.ID != 0 {
+				if err := teams.Delete(ctx, team.Status.ID); err != nil {
+					// if fail to delete the external dependency here, return with error
+					// so that it can be retried
+					return ctrl.Result{}, err
+				}
+			}
+
+			// remove our finalizer from the list and update it.
+			controllerutil.RemoveFinalizer(team, grafanaTeamsFinalizerName)
+			if err := r.Update(ctx, team); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		// Stop reconciliation as the item is being deleted
+		return ctrl.Result{}, nil
+	}
+
+	// proceed with create/update reconciliation
+	teamID, err := teams.Upsert(ctx, grafana.Team{
+		ID:          team.Status.ID,
+		Name:        team.TeamName(),
+		Email:       team.Spec.Email,
+		Members:     team.Spec.Members,
+		Preferences: team.Spec.Preferences,
+	})
+
+	// the ID is only known once Upsert went far enough: an early failure must
+	// not make us forget about the team
+	teamWithStatus := team.DeepCopy()
+	if teamID != 0 {
+		teamWithStatus.Status.ID = teamID
+	}
+
+	if err != nil {
+		logger.Error(err, "could not upsert GrafanaTeam in Grafana")
+
+		statusErr := updateStatus(ctx, r.Client, teamWithStatus, err)
+		r.Recorder.Event(team, "Warning", "Error", "could not synchronize GrafanaTeam with Grafana")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+
+	logger.Info("done!")
+
+	if err := updateStatus(ctx, r.Client, teamWithStatus, nil); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.Recorder.Event(team, "Normal", "Synchronized", "GrafanaTeam synchronized")
+
+	return ctrl.Result{}, nil
+}
+
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanateams,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanateams/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanateams/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+func StartGrafanaTeamReconciler(logger logr.Logger, ctrlManager ctrl.Manager, instances *grafana.Instances) error {
+	reconciler := &GrafanaTeamReconciler{
+		Client:    ctrlManager.GetClient(),
+		Scheme:    ctrlManager.GetScheme(),
+		Recorder:  ctrlManager.GetEventRecorderFor("grafanateam-controller"),
+		Instances: instances,
+		Teams: func(grafanaClient *grafana.Client) teamsManager {
+			return grafana.NewTeams(logger, grafanaClient)
+		},
+	}
+
+	return reconciler.SetupWithManager(ctrlManager)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaTeamReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.GrafanaTeam{}).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(r)
+}
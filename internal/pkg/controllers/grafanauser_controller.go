@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/dark/internal/pkg/grafana"
+	"github.com/K-Phoen/dark/internal/pkg/kubernetes"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const grafanaUsersFinalizerName = "grafanausers.k8s.kevingomez.fr/finalizer"
+
+type usersManager interface {
+	Upsert(ctx context.Context, namespace string, user grafana.User) (int64, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// GrafanaUserReconciler reconciles a GrafanaUser object
+type GrafanaUserReconciler struct {
+	client.Client
+
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	Instances grafanaInstances
+	Users     func(grafanaClient *grafana.Client) usersManager
+}
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
+func (r *GrafanaUserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	logger.Info("reconciling")
+
+	user := &v1alpha1.GrafanaUser{}
+	if err := r.Get(ctx, req.NamespacedName, user); err != nil {
+		logger.Error(err, "unable to fetch GrafanaUser")
+		// we'll ignore not-found errors, since they can't be fixed by an immediate
+		// requeue (we'll need to wait for a new notification), and we can get them
+		// on deleted requests.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	grafanaClient, err := r.Instances.ClientFor(ctx, user, user.Spec.InstanceRef)
+	if err != nil {
+		logger.Error(err, "unable to resolve Grafana instance")
+
+		if isInstanceGone(err) && !user.ObjectMeta.DeletionTimestamp.IsZero() {
+			return ctrl.Result{}, releaseFinalizer(ctx, r.Client, r.Recorder, user, grafanaUsersFinalizerName)
+		}
+
+		statusErr := updateStatus(ctx, r.Client, user, err)
+		r.Recorder.Event(user, "Warning", "Error", "could not resolve Grafana instance")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+	users := r.Users(grafanaClient)
+
+	// examine DeletionTimestamp to determine if object is under deletion
+	if user.ObjectMeta.DeletionTimestamp.IsZero() {
+		// The object is not being deleted, so if it does not have our finalizer,
+		// then lets add the finalizer and update the object. This is equivalent
+		// registering our finalizer.
+		if !containsString(user.GetFinalizers(), grafanaUsersFinalizerName) {
+			controllerutil.AddFinalizer(user, grafanaUsersFinalizerName)
+			if err := r.Update(ctx, user); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	} else {
+		logger.Info("deleting GrafanaUser")
+
+		// The object is being deleted
+		if containsString(user.GetFinalizers(), grafanaUsersFinalizerName) {
+			logger.Info("finalizer found, deleting user from grafana")
+
+			// our finalizer is present, so lets handle any external dependency
+			if user.Status.ID != 0 {
+				if err := users.Delete(ctx, user.Status.ID); err != nil {
+					// if fail to delete the external dependency here, return with error
+					// so that it can be retried
+					return ctrl.Result{}, err
+				}
+			}
+
+			// remove our finalizer from the list and update it.
+			controllerutil.RemoveFinalizer(user, grafanaUsersFinalizerName)
+			if err := r.Update(ctx, user); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		// Stop reconciliation as the item is being deleted
+		return ctrl.Result{}, nil
+	}
+
+	// proceed with create/update reconciliation
+	userID, err := users.Upsert(ctx, user.Namespace, grafana.User{
+		ID:       user.Status.ID,
+		Login:    user.UserLogin(),
+		Email:    user.Spec.Email,
+		Name:     user.Spec.Name,
+		Password: user.Spec.Password,
+		OrgRole:  user.Spec.OrgRole,
+	})
+
+	// the ID is only known once Upsert went far enough: an early failure must
+	// not make us forget about the user
+	userWithStatus := user.DeepCopy()
+	if userID != 0 {
+		userWithStatus.Status.ID = userID
+	}
+
+	if err != nil {
+		logger.Error(err, "could not upsert GrafanaUser in Grafana")
+
+		statusErr := updateStatus(ctx, r.Client, userWithStatus, err)
+		r.Recorder.Event(user, "Warning", "Error", "could not synchronize GrafanaUser with Grafana")
+
+		return ctrl.Result{}, errors.Join(err, statusErr)
+	}
+
+	logger.Info("done!")
+
+	if err := updateStatus(ctx, r.Client, userWithStatus, nil); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.Recorder.Event(user, "Normal", "Synchronized", "GrafanaUser synchronized")
+
+	return ctrl.Result{}, nil
+}
+
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanausers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanausers/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=grafanausers/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+func StartGrafanaUserReconciler(logger logr.Logger, ctrlManager ctrl.Manager, instances *grafana.Instances, refReader *kubernetes.ValueRefReader) error {
+	reconciler := &GrafanaUserReconciler{
+		Client:    ctrlManager.GetClient(),
+		Scheme:    ctrlManager.GetScheme(),
+		Recorder:  ctrlManager.GetEventRecorderFor("grafanauser-controller"),
+		Instances: instances,
+		Users: func(grafanaClient *grafana.Client) usersManager {
+			return grafana.NewUsers(logger, grafanaClient, refReader)
+		},
+	}
+
+	return reconciler.SetupWithManager(ctrlManager)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GrafanaUserReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.GrafanaUser{}).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(r)
+}
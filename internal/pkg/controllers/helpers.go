@@ -1,5 +1,47 @@
 package controllers
 
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/dark/internal/pkg/grafana"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+type grafanaInstances interface {
+	ClientFor(ctx context.Context, object metav1.Object, ref *v1alpha1.InstanceRef) (*grafana.Client, error)
+}
+
+// isInstanceGone tells whether the given error, returned while resolving a
+// Grafana client, means that the instance or its credentials don't exist, or
+// that the instance can't be used by the object anymore.
+func isInstanceGone(err error) bool {
+	return errors.Is(err, grafana.ErrInstanceNotFound) ||
+		errors.Is(err, grafana.ErrInstanceNotAllowed) ||
+		isSecretsError(err)
+}
+
+// releaseFinalizer removes the finalizer of an object being deleted whose
+// Grafana instance is gone: what it created in Grafana can't be cleaned up
+// anymore, and waiting for the instance would keep the object terminating
+// forever.
+func releaseFinalizer(ctx context.Context, k8sClient client.Client, recorder record.EventRecorder, object client.Object, finalizer string) error {
+	if !controllerutil.ContainsFinalizer(object, finalizer) {
+		return nil
+	}
+
+	recorder.Event(object, "Warning", "InstanceNotFound", "Grafana instance not found: resources left in Grafana were not cleaned up")
+
+	controllerutil.RemoveFinalizer(object, finalizer)
+
+	return k8sClient.Update(ctx, object)
+}
+
 func containsString(slice []string, s string) bool {
 	for _, item := range slice {
 		if item == s {
@@ -8,3 +50,19 @@ func containsString(slice []string, s string) bool {
 	}
 	return false
 }
+
+func timeOrZero(t *metav1.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+
+	return t.Time
+}
+
+func timeOrNil(t time.Time) *metav1.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &metav1.Time{Time: t}
+}
@@ -0,0 +1,18 @@
+package controllers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/K-Phoen/dark/internal/pkg/grafana"
+	"github.com/K-Phoen/dark/internal/pkg/kubernetes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsInstanceGone(t *testing.T) {
+	req := require.New(t)
+
+	req.True(isInstanceGone(fmt.Errorf("default/grafana: %w", grafana.ErrInstanceNotFound)))
+	req.True(isInstanceGone(fmt.Errorf("could not extract API key: %w", kubernetes.ErrSecretNotFound)))
+	req.False(isInstanceGone(fmt.Errorf("some other error")))
+}
@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var ErrTeamNotReady = fmt.Errorf("team not ready")
+
+// resolvePermissions replaces the team references of the given permissions
+// by the name of the referenced GrafanaTeam.
+func resolvePermissions(ctx context.Context, reader client.Reader, namespace string, permissions []v1alpha1.Permission) ([]v1alpha1.Permission, error) {
+	resolved := make([]v1alpha1.Permission, 0, len(permissions))
+
+	for _, permission := range permissions {
+		if permission.TeamRef == nil {
+			resolved = append(resolved, permission)
+			continue
+		}
+
+		team := &v1alpha1.GrafanaTeam{}
+		if err := reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: permission.TeamRef.Name}, team); err != nil {
+			return nil, fmt.Errorf("could not fetch GrafanaTeam %s: %w", permission.TeamRef.Name, err)
+		}
+
+		if !isSynchronized(team) {
+			return nil, fmt.Errorf("GrafanaTeam %s: %w", permission.TeamRef.Name, ErrTeamNotReady)
+		}
+
+		resolved = append(resolved, v1alpha1.Permission{
+			Team:       team.TeamName(),
+			Permission: permission.Permission,
+		})
+	}
+
+	return resolved, nil
+}
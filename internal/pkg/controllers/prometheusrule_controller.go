@@ -0,0 +1,238 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/K-Phoen/dark/internal/pkg/grafana"
+	"github.com/K-Phoen/dark/internal/pkg/metrics"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const prometheusRulesFinalizerName = "prometheusrules.k8s.kevingomez.fr/finalizer"
+
+// ImportedRuleGroupsAnnotation lists the Grafana rule groups imported from a
+// PrometheusRule, so that they can be deleted once they're gone from it.
+const ImportedRuleGroupsAnnotation = "dark/imported-rule-groups"
+
+// importedRuleGroup designates a rule group imported in Grafana.
+type importedRuleGroup struct {
+	Folder string `json:"folder"`
+	Group  string `json:"group"`
+}
+
+// PrometheusRuleReconciler imports prometheus-operator PrometheusRule objects
+// as Grafana alert rule groups.
+type PrometheusRuleReconciler struct {
+	client.Client
+
+	Recorder record.EventRecorder
+
+	Instances       grafanaInstances
+	AlertRuleGroups func(grafanaClient *grafana.Client) alertRuleGroupsManager
+
+	// Selector restricts the imported PrometheusRule objects.
+	Selector labels.Selector
+	Import   grafana.PrometheusRulesImport
+}
+
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules/finalizers,verbs=update
+//+kubebuilder:rbac:groups=k8s.kevingomez.fr,resources=datasources,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
+func (r *PrometheusRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	logger.Info("reconciling")
+
+	rule := &unstructured.Unstructured{}
+	rule.SetGroupVersionKind(grafana.PrometheusRuleGVK)
+	if err := r.Get(ctx, req.NamespacedName, rule); err != nil {
+		logger.Error(err, "unable to fetch PrometheusRule")
+		// we'll ignore not-found errors, since they can't be fixed by an immediate
+		// requeue (we'll need to wait for a new notification), and we can get them
+		// on deleted requests.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	grafanaClient, err := r.Instances.ClientFor(ctx, rule, nil)
+	if err != nil {
+		logger.Error(err, "unable to resolve Grafana instance")
+
+		if isInstanceGone(err) && !rule.GetDeletionTimestamp().IsZero() {
+			return ctrl.Result{}, releaseFinalizer(ctx, r.Client, r.Recorder, rule, prometheusRulesFinalizerName)
+		}
+
+		metrics.RecordReconcile(rule, metrics.ResultError)
+		r.Recorder.Event(rule, "Warning", "Error", "could not resolve Grafana instance")
+
+		return ctrl.Result{}, err
+	}
+	alertRuleGroups := r.AlertRuleGroups(grafanaClient)
+
+	// rules being deleted or no longer selected: their groups must go
+	if !rule.GetDeletionTimestamp().IsZero() || !r.Selector.Matches(labels.Set(rule.GetLabels())) {
+		if !containsString(rule.GetFinalizers(), prometheusRulesFinalizerName) {
+			return ctrl.Result{}, nil
+		}
+
+		logger.Info("finalizer found, deleting imported rule groups from grafana")
+
+		for _, group := range importedRuleGroups(rule) {
+			if err := alertRuleGroups.Delete(ctx, group.Folder, group.Group); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		controllerutil.RemoveFinalizer(rule, prometheusRulesFinalizerName)
+		setImportedRuleGroups(rule, nil)
+		if err := r.Update(ctx, rule); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	if !containsString(rule.GetFinalizers(), prometheusRulesFinalizerName) {
+		controllerutil.AddFinalizer(rule, prometheusRulesFinalizerName)
+		if err := r.Update(ctx, rule); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	groups, err := grafana.PrometheusRuleToAlertRuleGroups(rule, r.Import)
+	if err != nil {
+		logger.Error(err, "could not convert PrometheusRule")
+
+		metrics.RecordReconcile(rule, metrics.ResultError)
+		r.Recorder.Event(rule, "Warning", "Error", "could not convert PrometheusRule to Grafana alert rules: "+err.Error())
+
+		return ctrl.Result{}, err
+	}
+
+	var imported []importedRuleGroup
+	for _, group := range groups {
+		folderTitle, err := alertRuleGroups.Upsert(ctx, group)
+		if err != nil {
+			logger.Error(err, "could not import rule group in Grafana", "group", group.Name)
+
+			metrics.RecordReconcile(rule, metrics.ResultError)
+			r.Recorder.Event(rule, "Warning", "Error", "could not import rule group "+group.Name+" in Grafana")
+
+			return ctrl.Result{}, err
+		}
+
+		imported = append(imported, importedRuleGroup{Folder: folderTitle, Group: group.Name})
+	}
+
+	// groups removed from the PrometheusRule since its last import
+	for _, group := range staleRuleGroups(importedRuleGroups(rule), imported) {
+		if err := alertRuleGroups.Delete(ctx, group.Folder, group.Group); err != nil {
+			metrics.RecordReconcile(rule, metrics.ResultError)
+			return ctrl.Result{}, err
+		}
+	}
+
+	previousAnnotation := rule.GetAnnotations()[ImportedRuleGroupsAnnotation]
+	setImportedRuleGroups(rule, imported)
+	if rule.GetAnnotations()[ImportedRuleGroupsAnnotation] != previousAnnotation {
+		if err := r.Update(ctx, rule); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	logger.Info("done!")
+
+	metrics.RecordReconcile(rule, metrics.ResultSuccess)
+	r.Recorder.Event(rule, "Normal", "Synchronized", "PrometheusRule imported in Grafana")
+
+	return ctrl.Result{}, nil
+}
+
+func importedRuleGroups(rule *unstructured.Unstructured) []importedRuleGroup {
+	var groups []importedRuleGroup
+
+	// a corrupted annotation only means that stale groups might be left behind
+	_ = json.Unmarshal([]byte(rule.GetAnnotations()[ImportedRuleGroupsAnnotation]), &groups)
+
+	return groups
+}
+
+func setImportedRuleGroups(rule *unstructured.Unstructured, groups []importedRuleGroup) {
+	annotations := rule.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	if len(groups) == 0 {
+		delete(annotations, ImportedRuleGroupsAnnotation)
+	} else {
+		// marshalling plain structs can't fail
+		encoded, _ := json.Marshal(groups)
+		annotations[ImportedRuleGroupsAnnotation] = string(encoded)
+	}
+
+	rule.SetAnnotations(annotations)
+}
+
+// staleRuleGroups lists the previously imported groups that weren't imported
+// again.
+func staleRuleGroups(previous []importedRuleGroup, current []importedRuleGroup) []importedRuleGroup {
+	kept := make(map[importedRuleGroup]bool, len(current))
+	for _, group := range current {
+		kept[group] = true
+	}
+
+	var stale []importedRuleGroup
+	for _, group := range previous {
+		if !kept[group] {
+			stale = append(stale, group)
+		}
+	}
+
+	return stale
+}
+
+func StartPrometheusRuleReconciler(logger logr.Logger, ctrlManager ctrl.Manager, instances *grafana.Instances, selector labels.Selector, options grafana.PrometheusRulesImport) error {
+	reconciler := &PrometheusRuleReconciler{
+		Client:    ctrlManager.GetClient(),
+		Recorder:  ctrlManager.GetEventRecorderFor("prometheusrule-controller"),
+		Instances: instances,
+		AlertRuleGroups: func(grafanaClient *grafana.Client) alertRuleGroupsManager {
+			return grafana.NewAlertRuleGroups(logger, grafanaClient, ctrlManager.GetClient())
+		},
+		Selector: selector,
+		Import:   options,
+	}
+
+	return reconciler.SetupWithManager(ctrlManager)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PrometheusRuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	rule := &unstructured.Unstructured{}
+	rule.SetGroupVersionKind(grafana.PrometheusRuleGVK)
+
+	// label changes must be seen for rules to be imported, or deleted from
+	// Grafana, when they start or stop matching the selector
+	changed := predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("prometheusrule").
+		For(rule, builder.WithPredicates(changed)).
+		Complete(r)
+}
@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestImportedRuleGroupsAreRememberedInAnnotations(t *testing.T) {
+	req := require.New(t)
+
+	rule := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	groups := []importedRuleGroup{{Folder: "api", Group: "api-rules - api.rules"}}
+
+	setImportedRuleGroups(rule, groups)
+	req.Equal(groups, importedRuleGroups(rule))
+
+	setImportedRuleGroups(rule, nil)
+	req.NotContains(rule.GetAnnotations(), ImportedRuleGroupsAnnotation)
+	req.Empty(importedRuleGroups(rule))
+}
+
+func TestStaleRuleGroups(t *testing.T) {
+	req := require.New(t)
+
+	previous := []importedRuleGroup{
+		{Folder: "api", Group: "api-rules - api.rules"},
+		{Folder: "api", Group: "api-rules - slo.rules"},
+	}
+	current := []importedRuleGroup{
+		{Folder: "api", Group: "api-rules - api.rules"},
+	}
+
+	req.Equal([]importedRuleGroup{{Folder: "api", Group: "api-rules - slo.rules"}}, staleRuleGroups(previous, current))
+	req.Empty(staleRuleGroups(current, previous))
+}
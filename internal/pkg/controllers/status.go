@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/dark/internal/pkg/grafana"
+	"github.com/K-Phoen/dark/internal/pkg/kubernetes"
+	"github.com/K-Phoen/dark/internal/pkg/metrics"
+	"github.com/K-Phoen/dark/internal/pkg/secretbackends"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// syncedObject is implemented by all the resources synchronized with Grafana.
+type syncedObject interface {
+	client.Object
+
+	GetSyncStatus() *v1alpha1.SyncStatus
+}
+
+// updateStatus records the outcome of a synchronization in the status of the
+// given object: its observed generation, last sync time and conditions.
+// The outcome is also exposed as a metric.
+// Failing to write the status is reported: it holds what identifies the
+// object in Grafana, and must not be lost.
+func updateStatus(ctx context.Context, statusClient client.StatusClient, object syncedObject, err error) error {
+	if err != nil {
+		metrics.RecordReconcile(object, metrics.ResultError)
+	} else {
+		metrics.RecordReconcile(object, metrics.ResultSuccess)
+	}
+
+	// NEVER modify objects from the store. It's a read-only, local cache.
+	// You can use DeepCopy() to make a deep copy of original object and modify this copy
+	// Or create a copy manually for better performance
+	objectCopy := object.DeepCopyObject().(syncedObject)
+
+	setSyncStatus(objectCopy.GetSyncStatus(), object.GetGeneration(), err)
+
+	if err := statusClient.Status().Update(ctx, objectCopy); err != nil {
+		return fmt.Errorf("unable to update status: %w", err)
+	}
+
+	return nil
+}
+
+// updateCondition sets a single condition in the status of the given object.
+func updateCondition(ctx context.Context, statusClient client.StatusClient, object syncedObject, condition metav1.Condition) error {
+	objectCopy := object.DeepCopyObject().(syncedObject)
+
+	condition.ObservedGeneration = object.GetGeneration()
+	meta.SetStatusCondition(&objectCopy.GetSyncStatus().Conditions, condition)
+
+	if err := statusClient.Status().Update(ctx, objectCopy); err != nil {
+		return fmt.Errorf("unable to update status: %w", err)
+	}
+
+	return nil
+}
+
+func setSyncStatus(status *v1alpha1.SyncStatus, generation int64, err error) {
+	status.ObservedGeneration = generation
+
+	setCondition := func(conditionType string, conditionStatus metav1.ConditionStatus, reason string, message string) {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               conditionType,
+			Status:             conditionStatus,
+			ObservedGeneration: generation,
+			Reason:             reason,
+			Message:            message,
+		})
+	}
+
+	switch {
+	case err == nil:
+		now := metav1.Now()
+		status.LastSyncTime = &now
+
+		setCondition(v1alpha1.SecretsResolvedCondition, metav1.ConditionTrue, "SecretsResolved", "All referenced values were resolved")
+		setCondition(v1alpha1.GrafanaReachableCondition, metav1.ConditionTrue, "GrafanaReachable", "Grafana was reached")
+		setCondition(v1alpha1.SynchronizedCondition, metav1.ConditionTrue, "Synchronized", "Synchronized with Grafana")
+		setCondition(v1alpha1.ReadyCondition, metav1.ConditionTrue, "Synchronized", "Synchronized with Grafana")
+
+		return
+	case isSecretsError(err):
+		setCondition(v1alpha1.SecretsResolvedCondition, metav1.ConditionFalse, "SecretsNotResolved", err.Error())
+		setCondition(v1alpha1.SynchronizedCondition, metav1.ConditionFalse, "SecretsNotResolved", err.Error())
+		setCondition(v1alpha1.ReadyCondition, metav1.ConditionFalse, "SecretsNotResolved", err.Error())
+	case isUnreachableError(err):
+		setCondition(v1alpha1.GrafanaReachableCondition, metav1.ConditionFalse, "GrafanaUnreachable", err.Error())
+		setCondition(v1alpha1.SynchronizedCondition, metav1.ConditionFalse, "GrafanaUnreachable", err.Error())
+		setCondition(v1alpha1.ReadyCondition, metav1.ConditionFalse, "GrafanaUnreachable", err.Error())
+	default:
+		setCondition(v1alpha1.SynchronizedCondition, metav1.ConditionFalse, "SynchronizationFailed", err.Error())
+		setCondition(v1alpha1.ReadyCondition, metav1.ConditionFalse, "SynchronizationFailed", err.Error())
+	}
+}
+
+// isSynchronized tells whether the given object was successfully synchronized
+// with Grafana in its current generation.
+func isSynchronized(object syncedObject) bool {
+	status := object.GetSyncStatus()
+
+	return status.ObservedGeneration == object.GetGeneration() &&
+		meta.IsStatusConditionTrue(status.Conditions, v1alpha1.SynchronizedCondition)
+}
+
+func isSecretsError(err error) bool {
+	return errors.Is(err, kubernetes.ErrSecretNotFound) ||
+		errors.Is(err, kubernetes.ErrKeyNotFoundInSecret) ||
+		errors.Is(err, kubernetes.ErrInvalidValueRef) ||
+		errors.Is(err, kubernetes.ErrConfigMapNotFound) ||
+		errors.Is(err, kubernetes.ErrKeyNotFoundInConfigMap) ||
+		errors.Is(err, secretbackends.ErrUnknownBackend) ||
+		errors.Is(err, secretbackends.ErrSecretNotFound) ||
+		errors.Is(err, secretbackends.ErrKeyNotFoundInSecret) ||
+		errors.Is(err, secretbackends.ErrInvalidPath)
+}
+
+func isUnreachableError(err error) bool {
+	var urlErr *url.Error
+
+	return errors.As(err, &urlErr) || errors.Is(err, grafana.ErrInstanceNotFound)
+}
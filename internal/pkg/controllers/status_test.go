@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/dark/internal/pkg/kubernetes"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestUpdateStatusReportsFailedWrites(t *testing.T) {
+	req := require.New(t)
+
+	conflict := k8serrors.NewConflict(schema.GroupResource{Resource: "grafanateams"}, "team", fmt.Errorf("object modified"))
+	statusClient := &statusRecorder{err: conflict}
+	team := &v1alpha1.GrafanaTeam{ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "default"}}
+
+	req.ErrorIs(updateStatus(context.Background(), statusClient, team, nil), conflict)
+	req.ErrorIs(updateCondition(context.Background(), statusClient, team, metav1.Condition{Type: "Test", Status: metav1.ConditionTrue, Reason: "Test"}), conflict)
+}
+
+func TestUpdateStatusWritesTheStatus(t *testing.T) {
+	req := require.New(t)
+
+	statusClient := &statusRecorder{}
+	team := &v1alpha1.GrafanaTeam{ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "default", Generation: 2}}
+
+	req.NoError(updateStatus(context.Background(), statusClient, team, nil))
+	req.Len(statusClient.updated, 1)
+	req.Equal(int64(2), statusClient.updated[0].(*v1alpha1.GrafanaTeam).Status.ObservedGeneration)
+}
+
+func TestSetSyncStatusOnSuccess(t *testing.T) {
+	req := require.New(t)
+
+	status := &v1alpha1.SyncStatus{}
+
+	setSyncStatus(status, 3, nil)
+
+	req.Equal(int64(3), status.ObservedGeneration)
+	req.NotNil(status.LastSyncTime)
+	req.Len(status.Conditions, 4)
+	for _, condition := range status.Conditions {
+		req.Equal(metav1.ConditionTrue, condition.Status)
+		req.Equal(int64(3), condition.ObservedGeneration)
+	}
+}
+
+func TestSetSyncStatusWithUnresolvedSecret(t *testing.T) {
+	req := require.New(t)
+
+	status := &v1alpha1.SyncStatus{}
+
+	setSyncStatus(status, 1, fmt.Errorf("could not extract CA certificate: %w", kubernetes.ErrSecretNotFound))
+
+	req.Nil(status.LastSyncTime)
+	req.True(meta.IsStatusConditionFalse(status.Conditions, v1alpha1.SecretsResolvedCondition))
+	req.True(meta.IsStatusConditionFalse(status.Conditions, v1alpha1.ReadyCondition))
+	req.Nil(meta.FindStatusCondition(status.Conditions, v1alpha1.GrafanaReachableCondition))
+}
+
+func TestSetSyncStatusWithUnreachableGrafana(t *testing.T) {
+	req := require.New(t)
+
+	status := &v1alpha1.SyncStatus{}
+
+	setSyncStatus(status, 1, fmt.Errorf("could not create dashboard: %w", &url.Error{Op: "Get", URL: "http://grafana", Err: fmt.Errorf("connection refused")}))
+
+	req.True(meta.IsStatusConditionFalse(status.Conditions, v1alpha1.GrafanaReachableCondition))
+	req.True(meta.IsStatusConditionFalse(status.Conditions, v1alpha1.SynchronizedCondition))
+	req.True(meta.IsStatusConditionFalse(status.Conditions, v1alpha1.ReadyCondition))
+}
+
+func TestSetSyncStatusKeepsLastSyncTimeOnError(t *testing.T) {
+	req := require.New(t)
+
+	status := &v1alpha1.SyncStatus{}
+
+	setSyncStatus(status, 1, nil)
+	lastSync := status.LastSyncTime
+
+	setSyncStatus(status, 2, fmt.Errorf("invalid dashboard"))
+
+	req.Equal(lastSync, status.LastSyncTime)
+	req.Equal(int64(2), status.ObservedGeneration)
+	req.True(meta.IsStatusConditionTrue(status.Conditions, v1alpha1.SecretsResolvedCondition))
+	req.Equal("SynchronizationFailed", meta.FindStatusCondition(status.Conditions, v1alpha1.ReadyCondition).Reason)
+}
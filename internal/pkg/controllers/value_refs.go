@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// secretRefsIndex and configMapRefsIndex index objects by the name of the
+// secrets and config maps they reference through ValueOrRef fields.
+const (
+	secretRefsIndex    = ".spec.secretRefs"
+	configMapRefsIndex = ".spec.configMapRefs"
+)
+
+var valueOrRefType = reflect.TypeOf(v1alpha1.ValueOrRef{})
+
+// indexValueRefs registers a secretRefsIndex and a configMapRefsIndex for the
+// given kind of object. spec extracts the part of the object in which
+// ValueOrRef fields are looked for.
+func indexValueRefs(mgr ctrl.Manager, object client.Object, spec func(object client.Object) interface{}) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), object, secretRefsIndex, func(object client.Object) []string {
+		return referencedSecrets(spec(object))
+	})
+	if err != nil {
+		return err
+	}
+
+	return mgr.GetFieldIndexer().IndexField(context.Background(), object, configMapRefsIndex, func(object client.Object) []string {
+		return referencedConfigMaps(spec(object))
+	})
+}
+
+// requestsForReferenced builds a map function enqueuing the objects
+// referencing a secret or a config map, as listed by the given index.
+func requestsForReferenced(reader client.Reader, index string, newList func() client.ObjectList) handler.MapFunc {
+	return func(referenced client.Object) []reconcile.Request {
+		list := newList()
+		err := reader.List(context.Background(), list,
+			client.InNamespace(referenced.GetNamespace()),
+			client.MatchingFields{index: referenced.GetName()},
+		)
+		if err != nil {
+			return nil
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(items))
+		for _, item := range items {
+			object, ok := item.(client.Object)
+			if !ok {
+				continue
+			}
+
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(object),
+			})
+		}
+
+		return requests
+	}
+}
+
+// referencedSecrets walks the given value and returns the sorted names of the
+// secrets referenced by the ValueOrRef it holds.
+func referencedSecrets(value interface{}) []string {
+	return referencedNames(value, func(ref *v1alpha1.ValueRef) string {
+		if ref.SecretKeyRef == nil {
+			return ""
+		}
+
+		return ref.SecretKeyRef.Name
+	})
+}
+
+// referencedConfigMaps walks the given value and returns the sorted names of
+// the config maps referenced by the ValueOrRef it holds.
+func referencedConfigMaps(value interface{}) []string {
+	return referencedNames(value, func(ref *v1alpha1.ValueRef) string {
+		if ref.ConfigMapKeyRef == nil {
+			return ""
+		}
+
+		return ref.ConfigMapKeyRef.Name
+	})
+}
+
+func referencedNames(value interface{}, nameOf func(ref *v1alpha1.ValueRef) string) []string {
+	names := map[string]struct{}{}
+	collectRefs(reflect.ValueOf(value), func(ref *v1alpha1.ValueRef) {
+		if name := nameOf(ref); name != "" {
+			names[name] = struct{}{}
+		}
+	})
+
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+func collectRefs(value reflect.Value, collect func(ref *v1alpha1.ValueRef)) {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !value.IsNil() {
+			collectRefs(value.Elem(), collect)
+		}
+	case reflect.Struct:
+		if value.Type() == valueOrRefType {
+			if ref := value.Interface().(v1alpha1.ValueOrRef); ref.ValueRef != nil {
+				collect(ref.ValueRef)
+			}
+
+			return
+		}
+
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).IsExported() {
+				collectRefs(value.Field(i), collect)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			collectRefs(value.Index(i), collect)
+		}
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			collectRefs(iter.Value(), collect)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+)
+
+func secretRef(name string) v1alpha1.ValueOrRef {
+	return v1alpha1.ValueOrRef{
+		ValueRef: &v1alpha1.ValueRef{
+			SecretKeyRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: name},
+				Key:                  "value",
+			},
+		},
+	}
+}
+
+func TestReferencedSecretsInDatasources(t *testing.T) {
+	req := require.New(t)
+
+	password := secretRef("prometheus-auth")
+	caCertificate := secretRef("prometheus-ca")
+
+	spec := v1alpha1.DatasourceSpec{
+		Prometheus: &v1alpha1.PrometheusDatasource{
+			URL: v1alpha1.NewStringOrRef("http://prometheus:9090"),
+			BasicAuth: &v1alpha1.BasicAuth{
+				Username: v1alpha1.ValueOrRef{Value: "admin"},
+				Password: password,
+			},
+			CACertificate: &caCertificate,
+		},
+	}
+
+	req.Equal([]string{"prometheus-auth", "prometheus-ca"}, referencedSecrets(spec))
+}
+
+func TestReferencedSecretsInAlertManagers(t *testing.T) {
+	req := require.New(t)
+
+	spec := v1alpha1.AlertManagerSpec{
+		ContactPoints: []v1alpha1.ContactPoint{
+			{
+				Name: "team-a",
+				Contacts: []v1alpha1.ContactPointType{
+					{Slack: &v1alpha1.SlackContactType{Webhook: secretRef("slack")}},
+					{Opsgenie: &v1alpha1.OpsgenieContactType{APIKey: secretRef("opsgenie")}},
+				},
+			},
+			{
+				Name: "team-b",
+				Contacts: []v1alpha1.ContactPointType{
+					{Slack: &v1alpha1.SlackContactType{Webhook: secretRef("slack")}},
+				},
+			},
+		},
+	}
+
+	req.Equal([]string{"opsgenie", "slack"}, referencedSecrets(spec))
+}
+
+func TestReferencedSecretsWithoutReferences(t *testing.T) {
+	req := require.New(t)
+
+	req.Empty(referencedSecrets(v1alpha1.DatasourceSpec{}))
+}
+
+func TestReferencedConfigMaps(t *testing.T) {
+	req := require.New(t)
+
+	spec := v1alpha1.DatasourceSpec{
+		Loki: &v1alpha1.LokiDatasource{
+			URL: v1alpha1.StringOrRef{
+				ValueOrRef: v1alpha1.ValueOrRef{
+					ValueRef: &v1alpha1.ValueRef{
+						ConfigMapKeyRef: &v1.ConfigMapKeySelector{
+							LocalObjectReference: v1.LocalObjectReference{Name: "endpoints"},
+							Key:                  "loki",
+						},
+					},
+				},
+			},
+			BasicAuth: &v1alpha1.BasicAuth{
+				Username: v1alpha1.ValueOrRef{Value: "admin"},
+				Password: secretRef("loki-auth"),
+			},
+		},
+	}
+
+	req.Equal([]string{"endpoints"}, referencedConfigMaps(spec))
+	req.Equal([]string{"loki-auth"}, referencedSecrets(spec))
+}
+
+func TestURLsCanBePlainStringsOrReferences(t *testing.T) {
+	req := require.New(t)
+
+	spec := v1alpha1.DatasourceSpec{}
+	err := json.Unmarshal([]byte(`{
+		"prometheus": {"url": "http://prometheus:9090"},
+		"loki": {"url": {"valueFrom": {"configMapKeyRef": {"name": "endpoints", "key": "loki"}}}}
+	}`), &spec)
+	req.NoError(err)
+
+	req.Equal("http://prometheus:9090", spec.Prometheus.URL.Value)
+	req.Equal([]string{"endpoints"}, referencedConfigMaps(spec))
+
+	// plain values are written back as plain strings
+	rendered, err := json.Marshal(spec.Prometheus)
+	req.NoError(err)
+	req.JSONEq(`{"url": "http://prometheus:9090"}`, string(rendered))
+}
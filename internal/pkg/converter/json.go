@@ -1,12 +1,12 @@
 package converter
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 
 	v1 "github.com/K-Phoen/dark/api/v1"
-	"github.com/K-Phoen/dark/internal/pkg/controllers"
 	grabana "github.com/K-Phoen/grabana/decoder"
 	"github.com/K-Phoen/sdk"
 	"go.uber.org/zap"
@@ -66,6 +66,23 @@ func (converter *JSON) ToYAML(input io.Reader, output io.Writer) error {
 	return err
 }
 
+// ToSpec converts a Grafana JSON dashboard into the JSON spec of a
+// GrafanaDashboard manifest.
+func (converter *JSON) ToSpec(input io.Reader) ([]byte, error) {
+	yamlSpec := &bytes.Buffer{}
+	if err := converter.ToYAML(input, yamlSpec); err != nil {
+		return nil, err
+	}
+
+	spec := make(map[string]interface{})
+	if err := yaml.Unmarshal(yamlSpec.Bytes(), &spec); err != nil {
+		converter.logger.Error("could not unmarshall yaml dashboard", zap.Error(err))
+		return nil, err
+	}
+
+	return json.Marshal(spec)
+}
+
 func (converter *JSON) ToK8SManifest(input io.Reader, output io.Writer, options K8SManifestOptions) error {
 	if err := options.validate(); err != nil {
 		return err
@@ -90,7 +107,7 @@ func (converter *JSON) ToK8SManifest(input io.Reader, output io.Writer, options
 
 	if options.Folder != "" {
 		manifest.Metadata["annotations"] = map[string]string{
-			controllers.DashboardFolderAnnotation: options.Folder,
+			v1.DashboardFolderAnnotation: options.Folder,
 		}
 	}
 
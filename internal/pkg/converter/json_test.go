@@ -28,6 +28,25 @@ func TestConvertValidJSONToYaml(t *testing.T) {
 	req.NoError(err)
 }
 
+func TestConvertInvalidJSONToSpec(t *testing.T) {
+	req := require.New(t)
+
+	converter := NewJSON(zap.NewNop())
+	_, err := converter.ToSpec(bytes.NewBufferString(""))
+
+	req.Error(err)
+}
+
+func TestConvertValidJSONToSpec(t *testing.T) {
+	req := require.New(t)
+
+	converter := NewJSON(zap.NewNop())
+	spec, err := converter.ToSpec(bytes.NewBufferString(`{"title": "Awesome dashboard"}`))
+
+	req.NoError(err)
+	req.Contains(string(spec), `"title":"Awesome dashboard"`)
+}
+
 func TestConvertInvalidJSONToK8SManifest(t *testing.T) {
 	req := require.New(t)
 
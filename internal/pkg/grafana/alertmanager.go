@@ -2,15 +2,19 @@ package grafana
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"sort"
 
 	"github.com/K-Phoen/dark/api/v1alpha1"
-	"github.com/K-Phoen/grabana"
 	"github.com/K-Phoen/grabana/alertmanager"
 	"github.com/K-Phoen/grabana/alertmanager/discord"
 	"github.com/K-Phoen/grabana/alertmanager/email"
 	"github.com/K-Phoen/grabana/alertmanager/opsgenie"
 	"github.com/K-Phoen/grabana/alertmanager/slack"
+	"github.com/K-Phoen/grabana/alertmanager/webhook"
+	"github.com/K-Phoen/sdk"
 	"github.com/go-logr/logr"
 )
 
@@ -19,66 +23,133 @@ var ErrInvalidRoutingRule = fmt.Errorf("invalid routing rule")
 
 type AlertManager struct {
 	logger        logr.Logger
-	grabanaClient *grabana.Client
+	grafanaClient *Client
 	refReader     refReader
 }
 
-func NewAlertManager(logger logr.Logger, grabanaClient *grabana.Client, refReader refReader) *AlertManager {
+func NewAlertManager(logger logr.Logger, grafanaClient *Client, refReader refReader) *AlertManager {
 	return &AlertManager{
 		logger:        logger,
-		grabanaClient: grabanaClient,
+		grafanaClient: grafanaClient,
 		refReader:     refReader,
 	}
 }
 
-func (manager *AlertManager) Reset(ctx context.Context) error {
-	config := alertmanager.New(
-		alertmanager.ContactPoints(
-			alertmanager.ContactPoint("grafana-default-email", email.To([]string{"<example@email.com>"})),
-		),
-		alertmanager.DefaultContactPoint("grafana-default-email"),
-	)
+// Reset restores the default configuration of the alert manager. Mute timings
+// not created by DARK are kept.
+func (manager *AlertManager) Reset(ctx context.Context, manifest v1alpha1.AlertManager) error {
+	defaultContactPoint := alertmanager.ContactPoint("grafana-default-email", email.To([]string{"<example@email.com>"}))
 
-	return manager.grabanaClient.ConfigureAlertManager(ctx, config)
+	config := alertManagerConfig{}
+	config.Config.Receivers = []sdk.ContactPoint{*defaultContactPoint.Builder}
+	config.Config.Route.Receiver = defaultContactPoint.Builder.Name
+
+	muteTimings, err := manager.unmanagedMuteTimings(ctx, manifest.Status.MuteTimings)
+	if err != nil {
+		return err
+	}
+	config.Config.MuteTimeIntervals = muteTimings
+
+	return manager.grafanaClient.configureAlertManager(ctx, config)
 }
 
 func (manager *AlertManager) Configure(ctx context.Context, manifest v1alpha1.AlertManager) error {
-	var managerOpts []alertmanager.Option
-
-	// message templates
-	if len(manifest.Spec.MessageTemplates) != 0 {
-		managerOpts = append(managerOpts, alertmanager.Templates(manifest.Spec.MessageTemplates))
+	config := alertManagerConfig{
+		TemplateFiles: manifest.Spec.MessageTemplates,
 	}
 
 	// contact points
-	contactPointsOpts, err := manager.contactPointsOpts(ctx, manifest)
+	contactPoints, err := manager.contactPointsOpts(ctx, manifest)
 	if err != nil {
 		return err
 	}
-	if len(contactPointsOpts) != 0 {
-		managerOpts = append(managerOpts, alertmanager.ContactPoints(contactPointsOpts...))
+	for _, contactPoint := range contactPoints {
+		config.Config.Receivers = append(config.Config.Receivers, *contactPoint.Builder)
 	}
 
-	// routing rules
-	routingOpts, err := manager.routingOpts(manifest)
+	// default contact point: the first one, unless specified
+	if manifest.Spec.DefaultContactPoint != "" {
+		config.Config.Route.Receiver = manifest.Spec.DefaultContactPoint
+	} else if len(contactPoints) != 0 {
+		config.Config.Route.Receiver = contactPoints[0].Builder.Name
+	}
+
+	// default grouping labels
+	config.Config.Route.GroupBy = manifest.Spec.DefaultGroupBy
+
+	// routing policies
+	routes, err := manager.routingOpts(manifest.Spec.Routing)
 	if err != nil {
 		return err
 	}
-	if len(routingOpts) != 0 {
-		managerOpts = append(managerOpts, alertmanager.Routing(routingOpts...))
+	config.Config.Route.Routes = routes
+
+	// mute timings
+	muteTimings, err := manager.muteTimings(ctx, manifest)
+	if err != nil {
+		return err
 	}
+	config.Config.MuteTimeIntervals = muteTimings
 
-	// default contact point
-	if manifest.Spec.DefaultContactPoint != "" {
-		managerOpts = append(managerOpts, alertmanager.DefaultContactPoint(manifest.Spec.DefaultContactPoint))
+	return manager.grafanaClient.configureAlertManager(ctx, config)
+}
+
+// muteTimings returns the mute timings declared by the manifest, along with
+// the ones found in Grafana that DARK doesn't manage: mute timings created
+// outside of DARK are kept, while the ones removed from the manifest since its
+// last synchronization are dropped.
+func (manager *AlertManager) muteTimings(ctx context.Context, manifest v1alpha1.AlertManager) ([]json.RawMessage, error) {
+	managed := make([]string, 0, len(manifest.Status.MuteTimings)+len(manifest.Spec.MuteTimings))
+	managed = append(managed, manifest.Status.MuteTimings...)
+
+	muteTimings := make([]json.RawMessage, 0, len(manifest.Spec.MuteTimings))
+	for _, muteTiming := range manifest.Spec.MuteTimings {
+		managed = append(managed, muteTiming.Name)
+
+		raw, err := json.Marshal(muteTiming)
+		if err != nil {
+			return nil, err
+		}
+
+		muteTimings = append(muteTimings, raw)
 	}
 
-	// default grouping labels
-	if len(manifest.Spec.DefaultGroupBy) != 0 {
-		managerOpts = append(managerOpts, alertmanager.DefaultGroupBys(manifest.Spec.DefaultGroupBy...))
+	unmanaged, err := manager.unmanagedMuteTimings(ctx, managed)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(muteTimings, unmanaged...), nil
+}
+
+// unmanagedMuteTimings returns the mute timings found in Grafana, except the
+// ones with the given names.
+func (manager *AlertManager) unmanagedMuteTimings(ctx context.Context, managedNames []string) ([]json.RawMessage, error) {
+	managed := make(map[string]bool, len(managedNames))
+	for _, name := range managedNames {
+		managed[name] = true
+	}
+
+	existing, err := manager.grafanaClient.muteTimeIntervals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var muteTimings []json.RawMessage
+	for _, raw := range existing {
+		muteTiming := struct {
+			Name string `json:"name"`
+		}{}
+		if err := json.Unmarshal(raw, &muteTiming); err != nil {
+			return nil, err
+		}
+
+		if !managed[muteTiming.Name] {
+			muteTimings = append(muteTimings, raw)
+		}
 	}
 
-	return manager.grabanaClient.ConfigureAlertManager(ctx, alertmanager.New(managerOpts...))
+	return muteTimings, nil
 }
 
 func (manager *AlertManager) contactPointsOpts(ctx context.Context, manifest v1alpha1.AlertManager) ([]alertmanager.Contact, error) {
@@ -124,6 +195,21 @@ func (manager *AlertManager) contactPointTypeOpt(ctx context.Context, namespace
 	if contactPointType.Discord != nil {
 		return manager.contactPointTypeDiscord(ctx, namespace, *contactPointType.Discord)
 	}
+	if contactPointType.PagerDuty != nil {
+		return manager.contactPointTypePagerDuty(ctx, namespace, *contactPointType.PagerDuty)
+	}
+	if contactPointType.Teams != nil {
+		return manager.contactPointTypeTeams(ctx, namespace, *contactPointType.Teams)
+	}
+	if contactPointType.Webhook != nil {
+		return manager.contactPointTypeWebhook(ctx, namespace, *contactPointType.Webhook)
+	}
+	if contactPointType.Telegram != nil {
+		return manager.contactPointTypeTelegram(ctx, namespace, *contactPointType.Telegram)
+	}
+	if contactPointType.GoogleChat != nil {
+		return manager.contactPointTypeGoogleChat(ctx, namespace, *contactPointType.GoogleChat)
+	}
 
 	return nil, ErrInvalidContactPointType
 }
@@ -174,7 +260,15 @@ func (manager *AlertManager) contactPointTypeOpsgenie(ctx context.Context, names
 		opts = append(opts, opsgenie.AutoClose())
 	}
 
-	return opsgenie.With(contactPointType.APIURL, apiKey, opts...), nil
+	apiURL := ""
+	if contactPointType.APIURL != nil {
+		apiURL, err = manager.refReader.RefToValue(ctx, namespace, contactPointType.APIURL.ValueOrRef)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return opsgenie.With(apiURL, apiKey, opts...), nil
 }
 
 func (manager *AlertManager) contactPointTypeDiscord(ctx context.Context, namespace string, contactPointType v1alpha1.DiscordContactType) (alertmanager.ContactPointOption, error) {
@@ -192,61 +286,266 @@ func (manager *AlertManager) contactPointTypeDiscord(ctx context.Context, namesp
 	return discord.With(webhook, opts...), nil
 }
 
-func (manager *AlertManager) routingOpts(manifest v1alpha1.AlertManager) ([]alertmanager.RoutingPolicy, error) {
-	opts := []alertmanager.RoutingPolicy{}
+func (manager *AlertManager) contactPointTypePagerDuty(ctx context.Context, namespace string, contactPointType v1alpha1.PagerDutyContactType) (alertmanager.ContactPointOption, error) {
+	pagerDuty := newSDKContactPointType("pagerduty")
 
-	for _, routingPolicy := range manifest.Spec.Routing {
-		opt, err := manager.routingPolicyOpt(routingPolicy)
+	integrationKey, err := manager.refReader.RefToValue(ctx, namespace, contactPointType.IntegrationKey)
+	if err != nil {
+		return nil, err
+	}
+
+	pagerDuty.builder.SecureSettings["integrationKey"] = integrationKey
+	pagerDuty.setIfNotEmpty("severity", contactPointType.Severity)
+	pagerDuty.setIfNotEmpty("class", contactPointType.Class)
+	pagerDuty.setIfNotEmpty("component", contactPointType.Component)
+	pagerDuty.setIfNotEmpty("group", contactPointType.Group)
+	pagerDuty.setIfNotEmpty("summary", contactPointType.Summary)
+
+	return pagerDuty.option(), nil
+}
+
+func (manager *AlertManager) contactPointTypeTeams(ctx context.Context, namespace string, contactPointType v1alpha1.TeamsContactType) (alertmanager.ContactPointOption, error) {
+	teams := newSDKContactPointType("teams")
+
+	webhookURL, err := manager.refReader.RefToValue(ctx, namespace, contactPointType.Webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	teams.builder.Settings["url"] = webhookURL
+	teams.setIfNotEmpty("title", contactPointType.Title)
+	teams.setIfNotEmpty("sectiontitle", contactPointType.SectionTitle)
+	teams.setIfNotEmpty("message", contactPointType.Message)
+
+	return teams.option(), nil
+}
+
+func (manager *AlertManager) contactPointTypeWebhook(ctx context.Context, namespace string, contactPointType v1alpha1.WebhookContactType) (alertmanager.ContactPointOption, error) {
+	var opts []webhook.Option
+
+	// settings not supported by grabana
+	extension := newSDKContactPointType("webhook")
+
+	url, err := manager.refReader.RefToValue(ctx, namespace, contactPointType.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	if contactPointType.HTTPMethod != "" {
+		opts = append(opts, webhook.Method(contactPointType.HTTPMethod))
+	}
+	if contactPointType.Password != nil {
+		password, err := manager.refReader.RefToValue(ctx, namespace, *contactPointType.Password)
 		if err != nil {
 			return nil, err
 		}
 
-		opts = append(opts, opt)
+		opts = append(opts, webhook.Credentials(contactPointType.Username, password))
+	} else {
+		extension.setIfNotEmpty("username", contactPointType.Username)
+	}
+	if contactPointType.MaxAlerts != 0 {
+		opts = append(opts, webhook.MaxAlerts(contactPointType.MaxAlerts))
 	}
 
-	return opts, nil
+	extension.setIfNotEmpty("authorization_scheme", contactPointType.AuthorizationScheme)
+	if contactPointType.AuthorizationCredentials != nil {
+		credentials, err := manager.refReader.RefToValue(ctx, namespace, *contactPointType.AuthorizationCredentials)
+		if err != nil {
+			return nil, err
+		}
+
+		extension.builder.SecureSettings["authorization_credentials"] = credentials
+	}
+
+	return extension.extend(webhook.Call(url, opts...)), nil
+}
+
+func (manager *AlertManager) contactPointTypeTelegram(ctx context.Context, namespace string, contactPointType v1alpha1.TelegramContactType) (alertmanager.ContactPointOption, error) {
+	telegram := newSDKContactPointType("telegram")
+
+	botToken, err := manager.refReader.RefToValue(ctx, namespace, contactPointType.BotToken)
+	if err != nil {
+		return nil, err
+	}
+
+	telegram.builder.SecureSettings["bottoken"] = botToken
+	telegram.builder.Settings["chatid"] = contactPointType.ChatID
+	telegram.setIfNotEmpty("message", contactPointType.Message)
+
+	if contactPointType.DisableNotifications {
+		telegram.builder.Settings["disable_notifications"] = true
+	}
+
+	return telegram.option(), nil
 }
 
-func (manager *AlertManager) routingPolicyOpt(policySpec v1alpha1.RoutingPolicy) (alertmanager.RoutingPolicy, error) {
-	opts := []alertmanager.RoutingPolicyOption{}
+func (manager *AlertManager) contactPointTypeGoogleChat(ctx context.Context, namespace string, contactPointType v1alpha1.GoogleChatContactType) (alertmanager.ContactPointOption, error) {
+	googleChat := newSDKContactPointType("googlechat")
+
+	webhookURL, err := manager.refReader.RefToValue(ctx, namespace, contactPointType.Webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	googleChat.builder.Settings["url"] = webhookURL
+	googleChat.setIfNotEmpty("title", contactPointType.Title)
+	googleChat.setIfNotEmpty("message", contactPointType.Message)
+
+	return googleChat.option(), nil
+}
+
+func (manager *AlertManager) routingOpts(policies []v1alpha1.RoutingPolicy) ([]notificationPolicy, error) {
+	routes := make([]notificationPolicy, 0, len(policies))
+
+	for _, routingPolicy := range policies {
+		route, err := manager.routingPolicyOpt(routingPolicy)
+		if err != nil {
+			return nil, err
+		}
+
+		routes = append(routes, route)
+	}
+
+	return routes, nil
+}
+
+func (manager *AlertManager) routingPolicyOpt(policySpec v1alpha1.RoutingPolicy) (notificationPolicy, error) {
+	policy := notificationPolicy{
+		Receiver:          policySpec.ContactPoint,
+		Continue:          policySpec.Continue,
+		GroupBy:           policySpec.GroupBy,
+		GroupWait:         policySpec.GroupWait,
+		GroupInterval:     policySpec.GroupInterval,
+		RepeatInterval:    policySpec.RepeatInterval,
+		MuteTimeIntervals: policySpec.MuteTimings,
+	}
 
 	for _, rule := range policySpec.Rules {
-		labelOpts, err := manager.routingLabelRules(rule)
+		matchers, err := labelsMatchers(rule)
 		if err != nil {
-			return alertmanager.RoutingPolicy{}, err
+			return notificationPolicy{}, err
 		}
 
-		opts = append(opts, labelOpts...)
+		policy.ObjectMatchers = append(policy.ObjectMatchers, matchers...)
 	}
 
-	return alertmanager.Policy(policySpec.ContactPoint, opts...), nil
+	if len(policySpec.Routing) != 0 {
+		routes, err := manager.routingOpts(policySpec.Routing)
+		if err != nil {
+			return notificationPolicy{}, err
+		}
+
+		policy.Routes = routes
+	}
+
+	return policy, nil
 }
 
-func (manager *AlertManager) routingLabelRules(rule v1alpha1.LabelsMatchingRule) ([]alertmanager.RoutingPolicyOption, error) {
-	var operatorFunc func(tag string, value string) alertmanager.RoutingPolicyOption
+// labelsMatchers converts a labels matching rule into matchers understood by
+// Grafana's alert manager.
+func labelsMatchers(rule v1alpha1.LabelsMatchingRule) ([]sdk.AlertObjectMatcher, error) {
+	var operator string
 	var labels map[string]string
-	opts := []alertmanager.RoutingPolicyOption{}
 
 	//nolint:gocritic
 	if rule.Eq != nil {
-		operatorFunc = alertmanager.TagEq
+		operator = "="
 		labels = rule.Eq
 	} else if rule.Neq != nil {
-		operatorFunc = alertmanager.TagNeq
+		operator = "!="
 		labels = rule.Neq
 	} else if rule.Matches != nil {
-		operatorFunc = alertmanager.TagMatches
+		operator = "=~"
 		labels = rule.Matches
 	} else if rule.NotMatches != nil {
-		operatorFunc = alertmanager.TagNotMatches
+		operator = "!~"
 		labels = rule.NotMatches
 	} else {
 		return nil, ErrInvalidRoutingRule
 	}
 
-	for key, value := range labels {
-		opts = append(opts, operatorFunc(key, value))
+	// sorted, to keep the generated configuration stable
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
 
-	return opts, nil
+	matchers := make([]sdk.AlertObjectMatcher, 0, len(keys))
+	for _, key := range keys {
+		matchers = append(matchers, sdk.AlertObjectMatcher{key, operator, labels[key]})
+	}
+
+	return matchers, nil
+}
+
+// alertManagerConfig is the configuration of Grafana's alert manager, as
+// represented by its API. Unlike grabana's, it describes nested routing
+// policies.
+type alertManagerConfig struct {
+	Config struct {
+		Receivers         []sdk.ContactPoint `json:"receivers"`
+		Route             notificationPolicy `json:"route"`
+		Templates         []string           `json:"templates"`
+		MuteTimeIntervals []json.RawMessage  `json:"mute_time_intervals,omitempty"`
+	} `json:"alertmanager_config"`
+	TemplateFiles map[string]string `json:"template_files"`
+}
+
+type notificationPolicy struct {
+	Receiver          string                   `json:"receiver,omitempty"`
+	Continue          bool                     `json:"continue,omitempty"`
+	GroupBy           []string                 `json:"group_by,omitempty"`
+	GroupWait         string                   `json:"group_wait,omitempty"`
+	GroupInterval     string                   `json:"group_interval,omitempty"`
+	RepeatInterval    string                   `json:"repeat_interval,omitempty"`
+	MuteTimeIntervals []string                 `json:"mute_time_intervals,omitempty"`
+	ObjectMatchers    []sdk.AlertObjectMatcher `json:"object_matchers,omitempty"`
+	Routes            []notificationPolicy     `json:"routes,omitempty"`
+}
+
+const alertManagerConfigPath = "/api/alertmanager/grafana/config/api/v1/alerts"
+
+// muteTimeIntervals returns the mute timings currently configured in Grafana.
+func (client *Client) muteTimeIntervals(ctx context.Context) ([]json.RawMessage, error) {
+	resp, err := client.get(ctx, alertManagerConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, client.httpError(resp)
+	}
+
+	config := struct {
+		Config struct {
+			MuteTimeIntervals []json.RawMessage `json:"mute_time_intervals"`
+		} `json:"alertmanager_config"`
+	}{}
+	if err := decodeJSON(resp.Body, &config); err != nil {
+		return nil, err
+	}
+
+	return config.Config.MuteTimeIntervals, nil
+}
+
+func (client *Client) configureAlertManager(ctx context.Context, config alertManagerConfig) error {
+	resp, err := client.sendJSON(ctx, http.MethodPost, alertManagerConfigPath, config)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return client.httpError(resp)
+	}
+
+	return nil
 }
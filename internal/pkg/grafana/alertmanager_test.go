@@ -0,0 +1,273 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/sdk"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func contactPointTypeToModel(t *testing.T, contactType v1alpha1.ContactPointType) sdk.ContactPointType {
+	t.Helper()
+	req := require.New(t)
+
+	manager := NewAlertManager(logr.Discard(), nil, valuesOnlyRefReader{})
+
+	contact, err := manager.contactPointOpt(context.Background(), "default", v1alpha1.ContactPoint{
+		Name:     "on-call",
+		Contacts: []v1alpha1.ContactPointType{contactType},
+	})
+	req.NoError(err)
+	req.Len(contact.Builder.GrafanaManagedReceivers, 1)
+
+	return contact.Builder.GrafanaManagedReceivers[0]
+}
+
+func TestPagerDutyContactPointType(t *testing.T) {
+	req := require.New(t)
+
+	model := contactPointTypeToModel(t, v1alpha1.ContactPointType{
+		PagerDuty: &v1alpha1.PagerDutyContactType{
+			IntegrationKey: v1alpha1.ValueOrRef{Value: "integration-key"},
+			Severity:       "critical",
+			Summary:        "{{ .CommonLabels.alertname }}",
+		},
+	})
+
+	req.Equal("pagerduty", model.Type)
+	req.Equal("integration-key", model.SecureSettings["integrationKey"])
+	req.Equal("critical", model.Settings["severity"])
+	req.Equal("{{ .CommonLabels.alertname }}", model.Settings["summary"])
+	req.NotContains(model.Settings, "class")
+}
+
+func TestWebhookContactPointType(t *testing.T) {
+	req := require.New(t)
+
+	model := contactPointTypeToModel(t, v1alpha1.ContactPointType{
+		Webhook: &v1alpha1.WebhookContactType{
+			URL:                      v1alpha1.ValueOrRef{Value: "https://on-call.example.com/hook"},
+			HTTPMethod:               "PUT",
+			AuthorizationScheme:      "Bearer",
+			AuthorizationCredentials: &v1alpha1.ValueOrRef{Value: "token"},
+			MaxAlerts:                10,
+		},
+	})
+
+	req.Equal("webhook", model.Type)
+	req.Equal("https://on-call.example.com/hook", model.Settings["url"])
+	req.Equal("PUT", model.Settings["httpMethod"])
+	req.Equal("Bearer", model.Settings["authorization_scheme"])
+	req.Equal("10", model.Settings["maxAlerts"])
+	req.Equal("token", model.SecureSettings["authorization_credentials"])
+	req.NotContains(model.SecureSettings, "password")
+}
+
+func TestWebhookContactPointTypeWithBasicAuth(t *testing.T) {
+	req := require.New(t)
+
+	model := contactPointTypeToModel(t, v1alpha1.ContactPointType{
+		Webhook: &v1alpha1.WebhookContactType{
+			URL:      v1alpha1.ValueOrRef{Value: "https://on-call.example.com/hook"},
+			Username: "joe",
+			Password: &v1alpha1.ValueOrRef{Value: "lafrite"},
+		},
+	})
+
+	req.Equal("webhook", model.Type)
+	req.Equal("joe", model.Settings["username"])
+	req.Equal("lafrite", model.SecureSettings["password"])
+	req.NotContains(model.Settings, "authorization_scheme")
+}
+
+func TestTelegramContactPointType(t *testing.T) {
+	req := require.New(t)
+
+	model := contactPointTypeToModel(t, v1alpha1.ContactPointType{
+		Telegram: &v1alpha1.TelegramContactType{
+			BotToken:             v1alpha1.ValueOrRef{Value: "bot-token"},
+			ChatID:               "-1234",
+			DisableNotifications: true,
+		},
+	})
+
+	req.Equal("telegram", model.Type)
+	req.Equal("bot-token", model.SecureSettings["bottoken"])
+	req.Equal("-1234", model.Settings["chatid"])
+	req.Equal(true, model.Settings["disable_notifications"])
+}
+
+func TestWebhookBasedContactPointTypes(t *testing.T) {
+	req := require.New(t)
+
+	teams := contactPointTypeToModel(t, v1alpha1.ContactPointType{
+		Teams: &v1alpha1.TeamsContactType{Webhook: v1alpha1.ValueOrRef{Value: "https://teams.example.com"}, Title: "Alert!"},
+	})
+	googleChat := contactPointTypeToModel(t, v1alpha1.ContactPointType{
+		GoogleChat: &v1alpha1.GoogleChatContactType{Webhook: v1alpha1.ValueOrRef{Value: "https://chat.example.com"}, Message: "Oops"},
+	})
+
+	req.Equal("teams", teams.Type)
+	req.Equal("https://teams.example.com", teams.Settings["url"])
+	req.Equal("Alert!", teams.Settings["title"])
+
+	req.Equal("googlechat", googleChat.Type)
+	req.Equal("https://chat.example.com", googleChat.Settings["url"])
+	req.Equal("Oops", googleChat.Settings["message"])
+}
+
+func TestRoutingPoliciesAreNested(t *testing.T) {
+	req := require.New(t)
+
+	manager := NewAlertManager(logr.Discard(), nil, valuesOnlyRefReader{})
+
+	routes, err := manager.routingOpts([]v1alpha1.RoutingPolicy{
+		{
+			ContactPoint: "team-a",
+			Rules:        []v1alpha1.LabelsMatchingRule{{Eq: map[string]string{"team": "a", "env": "prod"}}},
+			GroupBy:      []string{"alertname"},
+			Continue:     true,
+			Routing: []v1alpha1.RoutingPolicy{
+				{
+					ContactPoint:   "team-a-pager",
+					Rules:          []v1alpha1.LabelsMatchingRule{{Matches: map[string]string{"severity": "critical|error"}}},
+					GroupWait:      "10s",
+					RepeatInterval: "1h",
+					MuteTimings:    []string{"weekends"},
+				},
+			},
+		},
+	})
+	req.NoError(err)
+
+	req.Len(routes, 1)
+	req.Equal("team-a", routes[0].Receiver)
+	req.True(routes[0].Continue)
+	req.Equal([]string{"alertname"}, routes[0].GroupBy)
+	req.Equal([]sdk.AlertObjectMatcher{{"env", "=", "prod"}, {"team", "=", "a"}}, routes[0].ObjectMatchers)
+
+	req.Len(routes[0].Routes, 1)
+	child := routes[0].Routes[0]
+	req.Equal("team-a-pager", child.Receiver)
+	req.Equal("10s", child.GroupWait)
+	req.Equal("1h", child.RepeatInterval)
+	req.Equal([]string{"weekends"}, child.MuteTimeIntervals)
+	req.Equal([]sdk.AlertObjectMatcher{{"severity", "=~", "critical|error"}}, child.ObjectMatchers)
+}
+
+func TestConfigureSendsRoutingTreeAndMuteTimings(t *testing.T) {
+	req := require.New(t)
+
+	var configured map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET " + alertManagerConfigPath:
+			writeJSON(t, w, map[string]interface{}{
+				"alertmanager_config": map[string]interface{}{
+					"mute_time_intervals": []interface{}{
+						// outdated version of a declared mute timing
+						map[string]interface{}{"name": "weekends"},
+						// previously declared, removed from the manifest since
+						map[string]interface{}{"name": "nights"},
+						// created outside of DARK
+						map[string]interface{}{"name": "holidays"},
+					},
+				},
+			})
+		case "POST " + alertManagerConfigPath:
+			req.NoError(json.NewDecoder(r.Body).Decode(&configured))
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{Host: server.URL})
+	req.NoError(err)
+
+	manager := NewAlertManager(logr.Discard(), client, valuesOnlyRefReader{})
+	err = manager.Configure(context.Background(), v1alpha1.AlertManager{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "alertmanager"},
+		Spec: v1alpha1.AlertManagerSpec{
+			ContactPoints: []v1alpha1.ContactPoint{
+				{Name: "team-a", Contacts: []v1alpha1.ContactPointType{{Email: &v1alpha1.EmailContactType{To: []string{"team-a@example.com"}}}}},
+			},
+			Routing: []v1alpha1.RoutingPolicy{
+				{ContactPoint: "team-a", MuteTimings: []string{"weekends"}},
+			},
+			MuteTimings: []v1alpha1.MuteTiming{
+				{Name: "weekends", TimeIntervals: []v1alpha1.TimeInterval{{Weekdays: []string{"saturday", "sunday"}}}},
+			},
+		},
+		Status: v1alpha1.AlertManagerStatus{MuteTimings: []string{"weekends", "nights"}},
+	})
+	req.NoError(err)
+
+	config := configured["alertmanager_config"].(map[string]interface{})
+	route := config["route"].(map[string]interface{})
+
+	req.Equal("team-a", route["receiver"])
+	req.Len(route["routes"], 1)
+	req.Equal([]interface{}{
+		map[string]interface{}{
+			"name": "weekends",
+			"time_intervals": []interface{}{
+				map[string]interface{}{"weekdays": []interface{}{"saturday", "sunday"}},
+			},
+		},
+		map[string]interface{}{"name": "holidays"},
+	}, config["mute_time_intervals"])
+}
+
+func TestResetKeepsUnmanagedMuteTimings(t *testing.T) {
+	req := require.New(t)
+
+	var configured map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET " + alertManagerConfigPath:
+			writeJSON(t, w, map[string]interface{}{
+				"alertmanager_config": map[string]interface{}{
+					"mute_time_intervals": []interface{}{
+						map[string]interface{}{"name": "weekends"},
+						// created outside of DARK
+						map[string]interface{}{"name": "holidays"},
+					},
+				},
+			})
+		case "POST " + alertManagerConfigPath:
+			req.NoError(json.NewDecoder(r.Body).Decode(&configured))
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{Host: server.URL})
+	req.NoError(err)
+
+	manager := NewAlertManager(logr.Discard(), client, valuesOnlyRefReader{})
+	err = manager.Reset(context.Background(), v1alpha1.AlertManager{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "alertmanager"},
+		Status:     v1alpha1.AlertManagerStatus{MuteTimings: []string{"weekends"}},
+	})
+	req.NoError(err)
+
+	config := configured["alertmanager_config"].(map[string]interface{})
+	route := config["route"].(map[string]interface{})
+
+	req.Equal("grafana-default-email", route["receiver"])
+	req.Len(config["receivers"], 1)
+	req.Equal([]interface{}{
+		map[string]interface{}{"name": "holidays"},
+	}, config["mute_time_intervals"])
+}
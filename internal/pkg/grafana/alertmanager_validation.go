@@ -0,0 +1,298 @@
+package grafana
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidateAlertManagerSpec statically checks an alert manager spec: referenced
+// values (secrets, ...) are not resolved.
+func ValidateAlertManagerSpec(spec v1alpha1.AlertManagerSpec) field.ErrorList {
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	contactPoints := sets.NewString()
+	for i, contactPoint := range spec.ContactPoints {
+		contactPointPath := specPath.Child("contact_points").Index(i)
+
+		if contactPoint.Name == "" {
+			errs = append(errs, field.Required(contactPointPath.Child("name"), ""))
+		} else if contactPoints.Has(contactPoint.Name) {
+			errs = append(errs, field.Duplicate(contactPointPath.Child("name"), contactPoint.Name))
+		}
+		contactPoints.Insert(contactPoint.Name)
+
+		for j, contact := range contactPoint.Contacts {
+			errs = append(errs, validateContactPointType(contactPointPath.Child("contacts").Index(j), contact)...)
+		}
+	}
+
+	if spec.DefaultContactPoint != "" && !contactPoints.Has(spec.DefaultContactPoint) {
+		errs = append(errs, field.NotFound(specPath.Child("default_contact_point"), spec.DefaultContactPoint))
+	}
+
+	muteTimings := sets.NewString()
+	for i, muteTiming := range spec.MuteTimings {
+		muteTimingPath := specPath.Child("mute_timings").Index(i)
+
+		if muteTiming.Name == "" {
+			errs = append(errs, field.Required(muteTimingPath.Child("name"), ""))
+		} else if muteTimings.Has(muteTiming.Name) {
+			errs = append(errs, field.Duplicate(muteTimingPath.Child("name"), muteTiming.Name))
+		}
+		muteTimings.Insert(muteTiming.Name)
+
+		if len(muteTiming.TimeIntervals) == 0 {
+			errs = append(errs, field.Required(muteTimingPath.Child("time_intervals"), ""))
+		}
+		for j, interval := range muteTiming.TimeIntervals {
+			errs = append(errs, validateTimeInterval(muteTimingPath.Child("time_intervals").Index(j), interval)...)
+		}
+	}
+
+	errs = append(errs, validateRoutingPolicies(specPath.Child("routing"), spec.Routing, contactPoints, muteTimings)...)
+
+	return errs
+}
+
+// validateRoutingPolicies checks a tree of routing policies against the known
+// contact points and mute timings.
+func validateRoutingPolicies(path *field.Path, policies []v1alpha1.RoutingPolicy, contactPoints sets.String, muteTimings sets.String) field.ErrorList {
+	var errs field.ErrorList
+
+	for i, policy := range policies {
+		policyPath := path.Index(i)
+
+		if policy.ContactPoint != "" && !contactPoints.Has(policy.ContactPoint) {
+			errs = append(errs, field.NotFound(policyPath.Child("to"), policy.ContactPoint))
+		}
+
+		for j, rule := range policy.Rules {
+			errs = append(errs, validateLabelsMatchingRule(policyPath.Child("if_labels").Index(j), rule)...)
+		}
+
+		errs = append(errs, validateDuration(policyPath.Child("group_wait"), policy.GroupWait)...)
+		errs = append(errs, validateDuration(policyPath.Child("group_interval"), policy.GroupInterval)...)
+		errs = append(errs, validateDuration(policyPath.Child("repeat_interval"), policy.RepeatInterval)...)
+
+		for j, muteTiming := range policy.MuteTimings {
+			if !muteTimings.Has(muteTiming) {
+				errs = append(errs, field.NotFound(policyPath.Child("mute_timings").Index(j), muteTiming))
+			}
+		}
+
+		errs = append(errs, validateRoutingPolicies(policyPath.Child("routing"), policy.Routing, contactPoints, muteTimings)...)
+	}
+
+	return errs
+}
+
+func validateContactPointType(path *field.Path, contactType v1alpha1.ContactPointType) field.ErrorList {
+	var errs field.ErrorList
+
+	configured := 0
+	configure := func(typeName string, set bool, validate func(path *field.Path) field.ErrorList) {
+		if !set {
+			return
+		}
+
+		configured++
+		if configured > 1 {
+			errs = append(errs, field.Forbidden(path.Child(typeName), "only one contact point type may be specified"))
+			return
+		}
+
+		errs = append(errs, validate(path.Child(typeName))...)
+	}
+
+	configure("email", contactType.Email != nil, func(path *field.Path) field.ErrorList {
+		if len(contactType.Email.To) == 0 {
+			return field.ErrorList{field.Required(path.Child("to"), "")}
+		}
+		return nil
+	})
+	configure("slack", contactType.Slack != nil, func(path *field.Path) field.ErrorList {
+		return ValidateValueOrRef(path.Child("webhook"), contactType.Slack.Webhook)
+	})
+	configure("opsgenie", contactType.Opsgenie != nil, func(path *field.Path) field.ErrorList {
+		errs := ValidateValueOrRef(path.Child("api_key"), contactType.Opsgenie.APIKey)
+		if contactType.Opsgenie.APIURL != nil {
+			errs = append(errs, ValidateValueOrRef(path.Child("api_url"), contactType.Opsgenie.APIURL.ValueOrRef)...)
+		}
+		return errs
+	})
+	configure("discord", contactType.Discord != nil, func(path *field.Path) field.ErrorList {
+		return ValidateValueOrRef(path.Child("webhook"), contactType.Discord.Webhook)
+	})
+	configure("pagerduty", contactType.PagerDuty != nil, func(path *field.Path) field.ErrorList {
+		return ValidateValueOrRef(path.Child("integration_key"), contactType.PagerDuty.IntegrationKey)
+	})
+	configure("teams", contactType.Teams != nil, func(path *field.Path) field.ErrorList {
+		return ValidateValueOrRef(path.Child("webhook"), contactType.Teams.Webhook)
+	})
+	configure("webhook", contactType.Webhook != nil, func(path *field.Path) field.ErrorList {
+		return validateWebhookContactType(path, *contactType.Webhook)
+	})
+	configure("telegram", contactType.Telegram != nil, func(path *field.Path) field.ErrorList {
+		errs := ValidateValueOrRef(path.Child("bot_token"), contactType.Telegram.BotToken)
+		if contactType.Telegram.ChatID == "" {
+			errs = append(errs, field.Required(path.Child("chat_id"), ""))
+		}
+		return errs
+	})
+	configure("googlechat", contactType.GoogleChat != nil, func(path *field.Path) field.ErrorList {
+		return ValidateValueOrRef(path.Child("webhook"), contactType.GoogleChat.Webhook)
+	})
+
+	if configured == 0 {
+		errs = append(errs, field.Required(path, ErrInvalidContactPointType.Error()+": no contact point type specified"))
+	}
+
+	return errs
+}
+
+func validateWebhookContactType(path *field.Path, webhook v1alpha1.WebhookContactType) field.ErrorList {
+	errs := ValidateValueOrRef(path.Child("url"), webhook.URL)
+
+	basicAuth := webhook.Username != "" || webhook.Password != nil
+	if webhook.Password != nil {
+		errs = append(errs, ValidateValueOrRef(path.Child("password"), *webhook.Password)...)
+	}
+
+	if webhook.AuthorizationCredentials != nil {
+		errs = append(errs, ValidateValueOrRef(path.Child("authorization_credentials"), *webhook.AuthorizationCredentials)...)
+
+		if basicAuth {
+			errs = append(errs, field.Forbidden(path.Child("authorization_credentials"), "can not be used with basic authentication"))
+		}
+	} else if webhook.AuthorizationScheme != "" {
+		errs = append(errs, field.Required(path.Child("authorization_credentials"), "required when authorization_scheme is set"))
+	}
+
+	if webhook.MaxAlerts < 0 {
+		errs = append(errs, field.Invalid(path.Child("max_alerts"), webhook.MaxAlerts, "must be positive"))
+	}
+
+	return errs
+}
+
+var weekdays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+var months = []string{"january", "february", "march", "april", "may", "june", "july", "august", "september", "october", "november", "december"}
+
+func validateTimeInterval(path *field.Path, interval v1alpha1.TimeInterval) field.ErrorList {
+	var errs field.ErrorList
+
+	for i, timeRange := range interval.Times {
+		rangePath := path.Child("times").Index(i)
+
+		start, startErrs := validateTimeOfDay(rangePath.Child("start_time"), timeRange.StartTime)
+		end, endErrs := validateTimeOfDay(rangePath.Child("end_time"), timeRange.EndTime)
+		errs = append(errs, startErrs...)
+		errs = append(errs, endErrs...)
+
+		if len(startErrs) == 0 && len(endErrs) == 0 && start >= end {
+			errs = append(errs, field.Invalid(rangePath.Child("end_time"), timeRange.EndTime, "must be after start_time"))
+		}
+	}
+
+	errs = append(errs, validateRanges(path.Child("weekdays"), interval.Weekdays, func(value string) (int, bool) {
+		return indexOf(weekdays, strings.ToLower(value))
+	})...)
+	errs = append(errs, validateRanges(path.Child("days_of_month"), interval.DaysOfMonth, func(value string) (int, bool) {
+		day, err := strconv.Atoi(value)
+		if err != nil || day == 0 || day < -31 || day > 31 {
+			return 0, false
+		}
+		// negative days count from the end of the month, and can't be ordered
+		// against positive ones
+		if day < 0 {
+			return day + 32, true
+		}
+		return day, true
+	})...)
+	errs = append(errs, validateRanges(path.Child("months"), interval.Months, func(value string) (int, bool) {
+		if month, err := strconv.Atoi(value); err == nil {
+			return month, month >= 1 && month <= 12
+		}
+		month, ok := indexOf(months, strings.ToLower(value))
+		return month + 1, ok
+	})...)
+	errs = append(errs, validateRanges(path.Child("years"), interval.Years, func(value string) (int, bool) {
+		year, err := strconv.Atoi(value)
+		return year, err == nil && year > 0
+	})...)
+
+	return errs
+}
+
+// validateTimeOfDay checks a HH:MM time, and returns it as minutes since the
+// start of the day.
+func validateTimeOfDay(path *field.Path, value string) (int, field.ErrorList) {
+	parts := strings.Split(value, ":")
+	if len(parts) == 2 {
+		hours, hoursErr := strconv.Atoi(parts[0])
+		minutes, minutesErr := strconv.Atoi(parts[1])
+
+		if hoursErr == nil && minutesErr == nil && hours >= 0 && minutes >= 0 && minutes < 60 && hours*60+minutes <= 24*60 {
+			return hours*60 + minutes, nil
+		}
+	}
+
+	return 0, field.ErrorList{field.Invalid(path, value, "expected a time between 00:00 and 24:00, as HH:MM")}
+}
+
+// validateRanges checks a list of values or of "start:end" ranges of values.
+func validateRanges(path *field.Path, values []string, parse func(value string) (int, bool)) field.ErrorList {
+	var errs field.ErrorList
+
+	for i, value := range values {
+		bounds := strings.SplitN(value, ":", 2)
+
+		start, startOK := parse(bounds[0])
+		end, endOK := start, startOK
+		if len(bounds) == 2 {
+			end, endOK = parse(bounds[1])
+		}
+
+		switch {
+		case !startOK || !endOK:
+			errs = append(errs, field.Invalid(path.Index(i), value, "invalid value or range"))
+		case start > end:
+			errs = append(errs, field.Invalid(path.Index(i), value, "the start of the range must not be after its end"))
+		}
+	}
+
+	return errs
+}
+
+func indexOf(values []string, value string) (int, bool) {
+	for i, candidate := range values {
+		if candidate == value {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+func validateLabelsMatchingRule(path *field.Path, rule v1alpha1.LabelsMatchingRule) field.ErrorList {
+	operators := 0
+	for _, labels := range []map[string]string{rule.Eq, rule.Neq, rule.Matches, rule.NotMatches} {
+		if len(labels) != 0 {
+			operators++
+		}
+	}
+
+	switch {
+	case operators == 0:
+		return field.ErrorList{field.Required(path, ErrInvalidRoutingRule.Error()+": one of eq, neq, matches or not_matches required")}
+	case operators > 1:
+		return field.ErrorList{field.Forbidden(path, "only one of eq, neq, matches or not_matches may be specified")}
+	}
+
+	return nil
+}
@@ -0,0 +1,206 @@
+package grafana
+
+import (
+	"testing"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateAlertManagerSpecAcceptsValidSpecs(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateAlertManagerSpec(v1alpha1.AlertManagerSpec{
+		DefaultContactPoint: "team-a",
+		ContactPoints: []v1alpha1.ContactPoint{
+			{
+				Name: "team-a",
+				Contacts: []v1alpha1.ContactPointType{
+					{Email: &v1alpha1.EmailContactType{To: []string{"team-a@example.com"}}},
+					{Slack: &v1alpha1.SlackContactType{Webhook: v1alpha1.ValueOrRef{ValueRef: &v1alpha1.ValueRef{SecretKeyRef: secretKeyRef("slack", "webhook")}}}},
+				},
+			},
+		},
+		Routing: []v1alpha1.RoutingPolicy{
+			{ContactPoint: "team-a", Rules: []v1alpha1.LabelsMatchingRule{{Eq: map[string]string{"team": "a"}}}},
+		},
+	})
+
+	req.Empty(errs)
+}
+
+func TestValidateAlertManagerSpecRejectsUnknownContactPoints(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateAlertManagerSpec(v1alpha1.AlertManagerSpec{
+		DefaultContactPoint: "team-b",
+		ContactPoints: []v1alpha1.ContactPoint{
+			{Name: "team-a", Contacts: []v1alpha1.ContactPointType{{Email: &v1alpha1.EmailContactType{To: []string{"team-a@example.com"}}}}},
+		},
+		Routing: []v1alpha1.RoutingPolicy{
+			{ContactPoint: "team-c", Rules: []v1alpha1.LabelsMatchingRule{{Eq: map[string]string{"team": "c"}}}},
+		},
+	})
+
+	req.Len(errs, 2)
+	req.Equal(field.ErrorTypeNotFound, errs[0].Type)
+	req.Equal("spec.default_contact_point", errs[0].Field)
+	req.Equal(field.ErrorTypeNotFound, errs[1].Type)
+	req.Equal("spec.routing[0].to", errs[1].Field)
+}
+
+func TestValidateAlertManagerSpecRejectsInvalidContactPoints(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateAlertManagerSpec(v1alpha1.AlertManagerSpec{
+		ContactPoints: []v1alpha1.ContactPoint{
+			{Name: "team-a", Contacts: []v1alpha1.ContactPointType{{}}},
+			{Name: "team-a", Contacts: []v1alpha1.ContactPointType{{Discord: &v1alpha1.DiscordContactType{}}}},
+		},
+	})
+
+	req.Len(errs, 3)
+	req.Equal("spec.contact_points[0].contacts[0]", errs[0].Field)
+	req.Equal(field.ErrorTypeDuplicate, errs[1].Type)
+	req.Equal("spec.contact_points[1].name", errs[1].Field)
+	req.Equal("spec.contact_points[1].contacts[0].discord.webhook", errs[2].Field)
+}
+
+func TestValidateAlertManagerSpecRejectsInvalidOnCallContactPoints(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateAlertManagerSpec(v1alpha1.AlertManagerSpec{
+		ContactPoints: []v1alpha1.ContactPoint{
+			{
+				Name: "on-call",
+				Contacts: []v1alpha1.ContactPointType{
+					{PagerDuty: &v1alpha1.PagerDutyContactType{}},
+					{Telegram: &v1alpha1.TelegramContactType{BotToken: v1alpha1.ValueOrRef{Value: "token"}}},
+					{Webhook: &v1alpha1.WebhookContactType{
+						URL:                      v1alpha1.ValueOrRef{Value: "https://on-call.example.com/hook"},
+						Username:                 "dark",
+						Password:                 &v1alpha1.ValueOrRef{Value: "secret"},
+						AuthorizationCredentials: &v1alpha1.ValueOrRef{Value: "token"},
+					}},
+					{Webhook: &v1alpha1.WebhookContactType{URL: v1alpha1.ValueOrRef{Value: "https://on-call.example.com/hook"}, AuthorizationScheme: "Bearer"}},
+				},
+			},
+		},
+	})
+
+	req.Len(errs, 4)
+	req.Equal("spec.contact_points[0].contacts[0].pagerduty.integration_key", errs[0].Field)
+	req.Equal(field.ErrorTypeRequired, errs[1].Type)
+	req.Equal("spec.contact_points[0].contacts[1].telegram.chat_id", errs[1].Field)
+	req.Equal(field.ErrorTypeForbidden, errs[2].Type)
+	req.Equal("spec.contact_points[0].contacts[2].webhook.authorization_credentials", errs[2].Field)
+	req.Equal(field.ErrorTypeRequired, errs[3].Type)
+	req.Equal("spec.contact_points[0].contacts[3].webhook.authorization_credentials", errs[3].Field)
+}
+
+func TestValidateAlertManagerSpecRejectsInvalidNestedPolicies(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateAlertManagerSpec(v1alpha1.AlertManagerSpec{
+		ContactPoints: []v1alpha1.ContactPoint{
+			{Name: "team-a", Contacts: []v1alpha1.ContactPointType{{Email: &v1alpha1.EmailContactType{To: []string{"team-a@example.com"}}}}},
+		},
+		Routing: []v1alpha1.RoutingPolicy{
+			{
+				ContactPoint: "team-a",
+				Rules:        []v1alpha1.LabelsMatchingRule{{Eq: map[string]string{"team": "a"}}},
+				Routing: []v1alpha1.RoutingPolicy{
+					{Rules: []v1alpha1.LabelsMatchingRule{{Eq: map[string]string{"severity": "critical"}}}, RepeatInterval: "1h"},
+					{
+						Rules:     []v1alpha1.LabelsMatchingRule{{Eq: map[string]string{"severity": "warning"}}},
+						GroupWait: "soon",
+						Routing: []v1alpha1.RoutingPolicy{
+							{ContactPoint: "team-b", Rules: []v1alpha1.LabelsMatchingRule{{Eq: map[string]string{"service": "api"}}}},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	req.Len(errs, 2)
+	req.Equal(field.ErrorTypeInvalid, errs[0].Type)
+	req.Equal("spec.routing[0].routing[1].group_wait", errs[0].Field)
+	req.Equal(field.ErrorTypeNotFound, errs[1].Type)
+	req.Equal("spec.routing[0].routing[1].routing[0].to", errs[1].Field)
+}
+
+func TestValidateAlertManagerSpecChecksMuteTimings(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateAlertManagerSpec(v1alpha1.AlertManagerSpec{
+		ContactPoints: []v1alpha1.ContactPoint{
+			{Name: "team-a", Contacts: []v1alpha1.ContactPointType{{Email: &v1alpha1.EmailContactType{To: []string{"team-a@example.com"}}}}},
+		},
+		MuteTimings: []v1alpha1.MuteTiming{
+			{
+				Name: "nights-and-weekends",
+				TimeIntervals: []v1alpha1.TimeInterval{
+					{Weekdays: []string{"Saturday", "sunday"}},
+					{Times: []v1alpha1.TimeRange{{StartTime: "22:00", EndTime: "24:00"}}, Weekdays: []string{"monday:friday"}, Location: "Europe/Paris"},
+					{DaysOfMonth: []string{"1:5", "-3:-1"}, Months: []string{"january:march", "12"}, Years: []string{"2026:2027"}},
+				},
+			},
+			{
+				Name: "broken",
+				TimeIntervals: []v1alpha1.TimeInterval{
+					{Times: []v1alpha1.TimeRange{{StartTime: "18:00", EndTime: "08:00"}, {StartTime: "25:00", EndTime: "26:00"}}},
+					{Weekdays: []string{"friday:monday", "caturday"}, DaysOfMonth: []string{"0"}, Months: []string{"13"}},
+				},
+			},
+		},
+		Routing: []v1alpha1.RoutingPolicy{
+			{ContactPoint: "team-a", MuteTimings: []string{"nights-and-weekends", "holidays"}},
+		},
+	})
+
+	req.Len(errs, 8)
+	req.Equal("spec.mute_timings[1].time_intervals[0].times[0].end_time", errs[0].Field)
+	req.Equal("spec.mute_timings[1].time_intervals[0].times[1].start_time", errs[1].Field)
+	req.Equal("spec.mute_timings[1].time_intervals[0].times[1].end_time", errs[2].Field)
+	req.Equal("spec.mute_timings[1].time_intervals[1].weekdays[0]", errs[3].Field)
+	req.Equal("spec.mute_timings[1].time_intervals[1].weekdays[1]", errs[4].Field)
+	req.Equal("spec.mute_timings[1].time_intervals[1].days_of_month[0]", errs[5].Field)
+	req.Equal("spec.mute_timings[1].time_intervals[1].months[0]", errs[6].Field)
+	req.Equal(field.ErrorTypeNotFound, errs[7].Type)
+	req.Equal("spec.routing[0].mute_timings[1]", errs[7].Field)
+}
+
+func TestValidateAlertManagerSpecRejectsInvalidMatchingRules(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateAlertManagerSpec(v1alpha1.AlertManagerSpec{
+		ContactPoints: []v1alpha1.ContactPoint{
+			{Name: "team-a", Contacts: []v1alpha1.ContactPointType{{Email: &v1alpha1.EmailContactType{To: []string{"team-a@example.com"}}}}},
+		},
+		Routing: []v1alpha1.RoutingPolicy{
+			{
+				ContactPoint: "team-a",
+				Rules: []v1alpha1.LabelsMatchingRule{
+					{},
+					{Eq: map[string]string{"team": "a"}, Neq: map[string]string{"env": "dev"}},
+				},
+			},
+		},
+	})
+
+	req.Len(errs, 2)
+	req.Equal(field.ErrorTypeRequired, errs[0].Type)
+	req.Equal("spec.routing[0].if_labels[0]", errs[0].Field)
+	req.Equal(field.ErrorTypeForbidden, errs[1].Type)
+	req.Equal("spec.routing[0].if_labels[1]", errs[1].Field)
+}
+
+func secretKeyRef(name string, key string) *v1.SecretKeySelector {
+	return &v1.SecretKeySelector{
+		LocalObjectReference: v1.LocalObjectReference{Name: name},
+		Key:                  key,
+	}
+}
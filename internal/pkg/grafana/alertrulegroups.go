@@ -0,0 +1,382 @@
+package grafana
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/grabana"
+	"github.com/K-Phoen/grabana/alert"
+	"github.com/K-Phoen/grabana/alert/queries/graphite"
+	"github.com/K-Phoen/grabana/alert/queries/loki"
+	"github.com/K-Phoen/grabana/alert/queries/prometheus"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var ErrInvalidAlertCondition = fmt.Errorf("invalid alert condition")
+var ErrInvalidAlertTarget = fmt.Errorf("invalid alert target")
+
+// alertRuleGroupDatasource is the key under which the datasource of a rule
+// group is given to grabana.
+const alertRuleGroupDatasource = "datasource"
+
+const defaultAlertRuleGroupInterval = "1m"
+
+// AlertRuleGroup describes a group of alert rules, and where it lives in
+// Grafana.
+type AlertRuleGroup struct {
+	Name      string
+	Namespace string
+	Folder    DashboardFolder
+	Spec      v1alpha1.GrafanaAlertRuleGroupSpec
+
+	// PerSeries evaluates the condition of each rule on every series returned
+	// by its queries, instead of on all of them at once.
+	PerSeries bool
+}
+
+type AlertRuleGroups struct {
+	logger        logr.Logger
+	grafanaClient *Client
+	k8sClient     client.Reader
+}
+
+func NewAlertRuleGroups(logger logr.Logger, grafanaClient *Client, k8sClient client.Reader) *AlertRuleGroups {
+	return &AlertRuleGroups{
+		logger:        logger,
+		grafanaClient: grafanaClient,
+		k8sClient:     k8sClient,
+	}
+}
+
+// Upsert creates or replaces a rule group, and returns the title of the
+// folder holding it.
+func (groups *AlertRuleGroups) Upsert(ctx context.Context, group AlertRuleGroup) (string, error) {
+	groups.logger.Info("upserting alert rule group", "name", group.Name)
+
+	model, err := SpecToAlertRuleGroup(group.Name, group.Spec)
+	if err != nil {
+		return "", err
+	}
+
+	folder, _, err := groups.grafanaClient.resolveFolder(ctx, group.Folder)
+	if err != nil {
+		return "", err
+	}
+
+	datasourceUID, err := resolveDatasourceUID(ctx, groups.k8sClient, groups.grafanaClient, group.Namespace, group.Spec.Datasource)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve datasource: %w", err)
+	}
+
+	if group.PerSeries {
+		model.HookDatasourceUID(datasourceUID)
+
+		perSeriesGroup, err := toPerSeriesRuleGroup(*model.Builder)
+		if err != nil {
+			return "", err
+		}
+
+		if err := groups.grafanaClient.saveRuleGroup(ctx, folder.Title, perSeriesGroup); err != nil {
+			return "", err
+		}
+
+		return folder.Title, nil
+	}
+
+	model.Datasource = alertRuleGroupDatasource
+	err = groups.grafanaClient.AddAlert(ctx, folder.Title, *model, map[string]string{
+		alertRuleGroupDatasource: datasourceUID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return folder.Title, nil
+}
+
+// Delete removes a rule group from the given folder. Rule groups that don't
+// exist are ignored.
+func (groups *AlertRuleGroups) Delete(ctx context.Context, folderTitle string, name string) error {
+	groups.logger.Info("deleting alert rule group", "folder", folderTitle, "name", name)
+
+	err := groups.grafanaClient.DeleteAlertGroup(ctx, folderTitle, name)
+	if err != nil && !errors.Is(err, grabana.ErrAlertNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+// SpecToAlertRuleGroup converts a rule group spec into its grabana model.
+// The datasource queried by the rules is left to the caller.
+func SpecToAlertRuleGroup(name string, spec v1alpha1.GrafanaAlertRuleGroupSpec) (*alert.Alert, error) {
+	interval := spec.Interval
+	if interval == "" {
+		interval = defaultAlertRuleGroupInterval
+	}
+
+	group := alert.New(name, alert.EvaluateEvery(interval))
+	group.Builder.Rules = nil
+
+	for _, rule := range spec.Rules {
+		opts, err := alertRuleOpts(rule)
+		if err != nil {
+			return nil, fmt.Errorf("rule '%s': %w", rule.Title, err)
+		}
+
+		ruleModel := alert.New(rule.Title, opts...).Builder.Rules[0]
+		for key, value := range rule.Annotations {
+			ruleModel.Annotations[key] = value
+		}
+
+		group.Builder.Rules = append(group.Builder.Rules, ruleModel)
+	}
+
+	return group, nil
+}
+
+func alertRuleOpts(rule v1alpha1.AlertRule) ([]alert.Option, error) {
+	var opts []alert.Option
+
+	if rule.Summary != "" {
+		opts = append(opts, alert.Summary(rule.Summary))
+	}
+	if rule.Description != "" {
+		opts = append(opts, alert.Description(rule.Description))
+	}
+	if rule.Runbook != "" {
+		opts = append(opts, alert.Runbook(rule.Runbook))
+	}
+	if rule.For != "" {
+		opts = append(opts, alert.For(rule.For))
+	}
+	if len(rule.Labels) != 0 {
+		opts = append(opts, alert.Tags(rule.Labels))
+	}
+
+	switch rule.OnNoData {
+	case "":
+	case "no_data":
+		opts = append(opts, alert.OnNoData(alert.NoDataEmpty))
+	case "alerting":
+		opts = append(opts, alert.OnNoData(alert.NoDataAlerting))
+	case "ok":
+		opts = append(opts, alert.OnNoData(alert.NoDataOK))
+	default:
+		return nil, fmt.Errorf("invalid on_no_data mode '%s'", rule.OnNoData)
+	}
+
+	switch rule.OnExecutionError {
+	case "":
+	case "alerting":
+		opts = append(opts, alert.OnExecutionError(alert.ErrorAlerting))
+	case "error":
+		opts = append(opts, alert.OnExecutionError(alert.ErrorKO))
+	case "ok":
+		opts = append(opts, alert.OnExecutionError(alert.ErrorOK))
+	default:
+		return nil, fmt.Errorf("invalid on_execution_error mode '%s'", rule.OnExecutionError)
+	}
+
+	for i, condition := range rule.If {
+		opt, err := alertConditionOpt(condition)
+		if err != nil {
+			return nil, fmt.Errorf("condition %d: %w", i, err)
+		}
+
+		opts = append(opts, opt)
+	}
+
+	for i, target := range rule.Targets {
+		opt, err := alertTargetOpt(target)
+		if err != nil {
+			return nil, fmt.Errorf("target %d: %w", i, err)
+		}
+
+		opts = append(opts, opt)
+	}
+
+	return opts, nil
+}
+
+func alertConditionOpt(condition v1alpha1.AlertCondition) (alert.Option, error) {
+	reducer, queryRef, err := alertConditionReducerOf(condition)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluator, err := alertConditionEvaluator(condition)
+	if err != nil {
+		return nil, err
+	}
+
+	if condition.Operand == "or" {
+		return alert.IfOr(reducer, queryRef, evaluator), nil
+	}
+
+	return alert.If(reducer, queryRef, evaluator), nil
+}
+
+type alertConditionReducer struct {
+	reducer  alert.QueryReducer
+	queryRef string
+}
+
+// alertConditionReducers lists the reducers an alert condition may use,
+// along with the ref of the query they are applied to.
+func alertConditionReducers(condition v1alpha1.AlertCondition) []alertConditionReducer {
+	return []alertConditionReducer{
+		{reducer: alert.Avg, queryRef: condition.Avg},
+		{reducer: alert.Sum, queryRef: condition.Sum},
+		{reducer: alert.Count, queryRef: condition.Count},
+		{reducer: alert.Last, queryRef: condition.Last},
+		{reducer: alert.Min, queryRef: condition.Min},
+		{reducer: alert.Max, queryRef: condition.Max},
+		{reducer: alert.Median, queryRef: condition.Median},
+		{reducer: alert.Diff, queryRef: condition.Diff},
+		{reducer: alert.PercentDiff, queryRef: condition.PercentDiff},
+	}
+}
+
+func alertConditionReducerOf(condition v1alpha1.AlertCondition) (alert.QueryReducer, string, error) {
+	var reducer alert.QueryReducer
+	var queryRef string
+
+	for _, candidate := range alertConditionReducers(condition) {
+		if candidate.queryRef == "" {
+			continue
+		}
+		if queryRef != "" {
+			return "", "", fmt.Errorf("%w: only one reducer can be used", ErrInvalidAlertCondition)
+		}
+
+		reducer = candidate.reducer
+		queryRef = candidate.queryRef
+	}
+
+	if queryRef == "" {
+		return "", "", fmt.Errorf("%w: a reducer is required", ErrInvalidAlertCondition)
+	}
+
+	return reducer, queryRef, nil
+}
+
+func alertConditionEvaluator(condition v1alpha1.AlertCondition) (alert.ConditionEvaluator, error) {
+	var evaluators []alert.ConditionEvaluator
+
+	if condition.HasNoValue {
+		evaluators = append(evaluators, alert.HasNoValue())
+	}
+	if condition.Above != nil {
+		value, err := condition.Above.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("%w: above: %s", ErrInvalidAlertCondition, err)
+		}
+
+		evaluators = append(evaluators, alert.IsAbove(value))
+	}
+	if condition.Below != nil {
+		value, err := condition.Below.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("%w: below: %s", ErrInvalidAlertCondition, err)
+		}
+
+		evaluators = append(evaluators, alert.IsBelow(value))
+	}
+	if len(condition.OutsideRange) != 0 {
+		lower, upper, err := alertConditionRange(condition.OutsideRange)
+		if err != nil {
+			return nil, fmt.Errorf("%w: outside_range: %s", ErrInvalidAlertCondition, err)
+		}
+
+		evaluators = append(evaluators, alert.IsOutsideRange(lower, upper))
+	}
+	if len(condition.WithinRange) != 0 {
+		lower, upper, err := alertConditionRange(condition.WithinRange)
+		if err != nil {
+			return nil, fmt.Errorf("%w: within_range: %s", ErrInvalidAlertCondition, err)
+		}
+
+		evaluators = append(evaluators, alert.IsWithinRange(lower, upper))
+	}
+
+	if len(evaluators) != 1 {
+		return nil, fmt.Errorf("%w: exactly one threshold is required", ErrInvalidAlertCondition)
+	}
+
+	return evaluators[0], nil
+}
+
+func alertConditionRange(bounds []v1alpha1.Number) (float64, float64, error) {
+	if len(bounds) != 2 {
+		return 0, 0, fmt.Errorf("expected two bounds, got %d", len(bounds))
+	}
+
+	lower, err := bounds[0].Float64()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	upper, err := bounds[1].Float64()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return lower, upper, nil
+}
+
+func alertTargetOpt(target v1alpha1.AlertTarget) (alert.Option, error) {
+	switch {
+	case target.Prometheus != nil:
+		var opts []prometheus.Option
+		if target.Prometheus.Legend != "" {
+			opts = append(opts, prometheus.Legend(target.Prometheus.Legend))
+		}
+		if target.Prometheus.Lookback != "" {
+			lookback, err := time.ParseDuration(target.Prometheus.Lookback)
+			if err != nil {
+				return nil, fmt.Errorf("%w: lookback: %s", ErrInvalidAlertTarget, err)
+			}
+
+			opts = append(opts, prometheus.TimeRange(lookback, 0))
+		}
+
+		return alert.WithPrometheusQuery(target.Prometheus.Ref, target.Prometheus.Query, opts...), nil
+	case target.Loki != nil:
+		var opts []loki.Option
+		if target.Loki.Legend != "" {
+			opts = append(opts, loki.Legend(target.Loki.Legend))
+		}
+		if target.Loki.Lookback != "" {
+			lookback, err := time.ParseDuration(target.Loki.Lookback)
+			if err != nil {
+				return nil, fmt.Errorf("%w: lookback: %s", ErrInvalidAlertTarget, err)
+			}
+
+			opts = append(opts, loki.TimeRange(lookback, 0))
+		}
+
+		return alert.WithLokiQuery(target.Loki.Ref, target.Loki.Query, opts...), nil
+	case target.Graphite != nil:
+		var opts []graphite.Option
+		if target.Graphite.Legend != "" {
+			opts = append(opts, graphite.Legend(target.Graphite.Legend))
+		}
+		if target.Graphite.Lookback != "" {
+			lookback, err := time.ParseDuration(target.Graphite.Lookback)
+			if err != nil {
+				return nil, fmt.Errorf("%w: lookback: %s", ErrInvalidAlertTarget, err)
+			}
+
+			opts = append(opts, graphite.TimeRange(lookback, 0))
+		}
+
+		return alert.WithGraphiteQuery(target.Graphite.Ref, target.Graphite.Query, opts...), nil
+	}
+
+	return nil, fmt.Errorf("%w: no query configured", ErrInvalidAlertTarget)
+}
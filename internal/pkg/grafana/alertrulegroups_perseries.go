@@ -0,0 +1,191 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/K-Phoen/sdk"
+)
+
+// expressionsDatasource designates Grafana's server-side expressions.
+const expressionsDatasource = "__expr__"
+
+const (
+	perSeriesReduceRef    = "_alert_reduce_"
+	perSeriesConditionRef = "_alert_condition_"
+)
+
+// perSeriesReducers maps the reducers of classic conditions to the ones of
+// server-side "reduce" expressions.
+var perSeriesReducers = map[string]string{
+	"avg":   "mean",
+	"sum":   "sum",
+	"count": "count",
+	"last":  "last",
+	"min":   "min",
+	"max":   "max",
+}
+
+// perSeriesEvaluators lists the evaluators of classic conditions supported by
+// server-side "threshold" expressions.
+var perSeriesEvaluators = map[string]bool{
+	"gt":            true,
+	"lt":            true,
+	"within_range":  true,
+	"outside_range": true,
+}
+
+// perSeriesRuleGroup describes a rule group as expected by Grafana's ruler
+// API, with conditions made of server-side expressions: grabana only knows
+// about classic conditions.
+type perSeriesRuleGroup struct {
+	Name     string               `json:"name"`
+	Interval string               `json:"interval"`
+	Rules    []perSeriesAlertRule `json:"rules"`
+}
+
+type perSeriesAlertRule struct {
+	For          string                `json:"for"`
+	GrafanaAlert perSeriesGrafanaAlert `json:"grafana_alert"`
+	Annotations  map[string]string     `json:"annotations,omitempty"`
+	Labels       map[string]string     `json:"labels,omitempty"`
+}
+
+type perSeriesGrafanaAlert struct {
+	Title               string                `json:"title"`
+	Condition           string                `json:"condition"`
+	NoDataState         string                `json:"no_data_state"`
+	ExecutionErrorState string                `json:"exec_err_state,omitempty"`
+	Data                []perSeriesAlertQuery `json:"data"`
+}
+
+type perSeriesAlertQuery struct {
+	RefID             string                      `json:"refId"`
+	QueryType         string                      `json:"queryType"`
+	RelativeTimeRange *sdk.AlertRelativeTimeRange `json:"relativeTimeRange,omitempty"`
+	DatasourceUID     string                      `json:"datasourceUid"`
+	Model             interface{}                 `json:"model"`
+}
+
+type expressionModel struct {
+	RefID      string                 `json:"refId"`
+	Type       string                 `json:"type"`
+	Expression string                 `json:"expression"`
+	Reducer    string                 `json:"reducer,omitempty"`
+	Conditions []expressionThreshold  `json:"conditions,omitempty"`
+	Datasource sdk.AlertDatasourceRef `json:"datasource"`
+}
+
+type expressionThreshold struct {
+	Evaluator sdk.AlertEvaluator `json:"evaluator"`
+}
+
+// toPerSeriesRuleGroup converts a rule group built by grabana so that each
+// series returned by the queries of its rules is reduced and compared to the
+// threshold on its own: every series becomes an alert instance, with its own
+// labels. Rules must have a single condition.
+func toPerSeriesRuleGroup(group sdk.Alert) (perSeriesRuleGroup, error) {
+	converted := perSeriesRuleGroup{
+		Name:     group.Name,
+		Interval: group.Interval,
+	}
+
+	for _, rule := range group.Rules {
+		grafanaAlert, err := toPerSeriesGrafanaAlert(*rule.GrafanaAlert)
+		if err != nil {
+			return perSeriesRuleGroup{}, fmt.Errorf("rule '%s': %w", rule.GrafanaAlert.Title, err)
+		}
+
+		converted.Rules = append(converted.Rules, perSeriesAlertRule{
+			For:          rule.For,
+			GrafanaAlert: grafanaAlert,
+			Annotations:  rule.Annotations,
+			Labels:       rule.Labels,
+		})
+	}
+
+	return converted, nil
+}
+
+func toPerSeriesGrafanaAlert(grafanaAlert sdk.GrafanaAlert) (perSeriesGrafanaAlert, error) {
+	converted := perSeriesGrafanaAlert{
+		Title:               grafanaAlert.Title,
+		Condition:           perSeriesConditionRef,
+		NoDataState:         grafanaAlert.NoDataState,
+		ExecutionErrorState: grafanaAlert.ExecutionErrorState,
+	}
+
+	var conditions []sdk.AlertCondition
+	for _, query := range grafanaAlert.Data {
+		if query.Model.Type == "classic_conditions" {
+			conditions = append(conditions, query.Model.Conditions...)
+			continue
+		}
+
+		converted.Data = append(converted.Data, perSeriesAlertQuery{
+			RefID:             query.RefID,
+			QueryType:         query.QueryType,
+			RelativeTimeRange: query.RelativeTimeRange,
+			DatasourceUID:     query.DatasourceUID,
+			Model:             query.Model,
+		})
+	}
+
+	if len(conditions) != 1 || len(conditions[0].Query.Params) != 1 {
+		return perSeriesGrafanaAlert{}, fmt.Errorf("%w: exactly one condition is required", ErrInvalidAlertCondition)
+	}
+	condition := conditions[0]
+
+	reducer, ok := perSeriesReducers[condition.Reducer.Type]
+	if !ok {
+		return perSeriesGrafanaAlert{}, fmt.Errorf("%w: reducer '%s' can not be applied per series", ErrInvalidAlertCondition, condition.Reducer.Type)
+	}
+	if !perSeriesEvaluators[condition.Evaluator.Type] {
+		return perSeriesGrafanaAlert{}, fmt.Errorf("%w: threshold '%s' can not be applied per series", ErrInvalidAlertCondition, condition.Evaluator.Type)
+	}
+
+	converted.Data = append(converted.Data,
+		expressionQuery(expressionModel{
+			RefID:      perSeriesReduceRef,
+			Type:       "reduce",
+			Expression: condition.Query.Params[0],
+			Reducer:    reducer,
+		}),
+		expressionQuery(expressionModel{
+			RefID:      perSeriesConditionRef,
+			Type:       "threshold",
+			Expression: perSeriesReduceRef,
+			Conditions: []expressionThreshold{{Evaluator: condition.Evaluator}},
+		}),
+	)
+
+	return converted, nil
+}
+
+func expressionQuery(model expressionModel) perSeriesAlertQuery {
+	model.Datasource = sdk.AlertDatasourceRef{UID: expressionsDatasource, Type: expressionsDatasource}
+
+	return perSeriesAlertQuery{
+		RefID:         model.RefID,
+		DatasourceUID: expressionsDatasource,
+		Model:         model,
+	}
+}
+
+// saveRuleGroup creates or replaces a rule group in the given folder.
+func (client *Client) saveRuleGroup(ctx context.Context, folderTitle string, group perSeriesRuleGroup) error {
+	resp, err := client.sendJSON(ctx, http.MethodPost, "/api/ruler/grafana/api/v1/rules/"+url.PathEscape(folderTitle), group)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return client.httpError(resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,215 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+)
+
+func alertRuleGroupSpec() v1alpha1.GrafanaAlertRuleGroupSpec {
+	above := v1alpha1.Number("0.05")
+
+	return v1alpha1.GrafanaAlertRuleGroupSpec{
+		Folder:     "Platform",
+		Interval:   "30s",
+		Datasource: v1alpha1.ValueOrDatasourceRef{UID: "prometheus-uid"},
+		Rules: []v1alpha1.AlertRule{
+			{
+				Title:            "High error rate",
+				Summary:          "Too many errors",
+				For:              "10m",
+				OnNoData:         "ok",
+				OnExecutionError: "error",
+				Labels:           map[string]string{"severity": "critical"},
+				Annotations:      map[string]string{"dashboard": "https://grafana/d/api"},
+				If: []v1alpha1.AlertCondition{
+					{Avg: "A", Above: &above},
+				},
+				Targets: []v1alpha1.AlertTarget{
+					{Prometheus: &v1alpha1.AlertQuery{Ref: "A", Query: "sum(rate(errors[5m]))", Lookback: "10m"}},
+				},
+			},
+			{
+				Title: "No traffic",
+				If: []v1alpha1.AlertCondition{
+					{Last: "B", HasNoValue: true},
+				},
+				Targets: []v1alpha1.AlertTarget{
+					{Prometheus: &v1alpha1.AlertQuery{Ref: "B", Query: "sum(rate(requests[5m]))"}},
+				},
+			},
+		},
+	}
+}
+
+func TestSpecToAlertRuleGroup(t *testing.T) {
+	req := require.New(t)
+
+	group, err := SpecToAlertRuleGroup("api", alertRuleGroupSpec())
+	req.NoError(err)
+
+	req.Equal("api", group.Builder.Name)
+	req.Equal("30s", group.Builder.Interval)
+	req.Len(group.Builder.Rules, 2)
+
+	errorRate := group.Builder.Rules[0]
+	req.Equal("High error rate", errorRate.GrafanaAlert.Title)
+	req.Equal("10m", errorRate.For)
+	req.Equal("OK", errorRate.GrafanaAlert.NoDataState)
+	req.Equal("Error", errorRate.GrafanaAlert.ExecutionErrorState)
+	req.Equal(map[string]string{"severity": "critical"}, errorRate.Labels)
+	req.Equal(map[string]string{"summary": "Too many errors", "dashboard": "https://grafana/d/api"}, errorRate.Annotations)
+	// the classic condition, then the query
+	req.Len(errorRate.GrafanaAlert.Data, 2)
+	req.Equal("A", errorRate.GrafanaAlert.Data[1].RefID)
+
+	noTraffic := group.Builder.Rules[1]
+	req.Equal("No traffic", noTraffic.GrafanaAlert.Title)
+	req.Equal("5m", noTraffic.For)
+}
+
+func TestSpecToAlertRuleGroupRejectsInvalidConditions(t *testing.T) {
+	req := require.New(t)
+
+	spec := alertRuleGroupSpec()
+	spec.Rules[0].If[0].Above = nil
+
+	_, err := SpecToAlertRuleGroup("api", spec)
+	req.ErrorIs(err, ErrInvalidAlertCondition)
+}
+
+func TestUpsertAlertRuleGroup(t *testing.T) {
+	req := require.New(t)
+
+	var saved map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/folders/platform-uid":
+			writeJSON(t, w, rawFolder{ID: 2, UID: "platform-uid", Title: "Platform"})
+		case "DELETE /api/ruler/grafana/api/v1/rules/Platform/api":
+			w.WriteHeader(http.StatusNotFound)
+		case "POST /api/ruler/grafana/api/v1/rules/Platform":
+			req.NoError(json.NewDecoder(r.Body).Decode(&saved))
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{Host: server.URL})
+	req.NoError(err)
+
+	folderTitle, err := NewAlertRuleGroups(logr.Discard(), client, nil).Upsert(context.Background(), AlertRuleGroup{
+		Name:      "api",
+		Namespace: "default",
+		Folder:    DashboardFolder{UID: "platform-uid"},
+		Spec:      alertRuleGroupSpec(),
+	})
+	req.NoError(err)
+
+	req.Equal("Platform", folderTitle)
+	req.Equal("api", saved["name"])
+
+	rules := saved["rules"].([]interface{})
+	req.Len(rules, 2)
+
+	query := rules[0].(map[string]interface{})["grafana_alert"].(map[string]interface{})["data"].([]interface{})[1].(map[string]interface{})
+	req.Equal("prometheus-uid", query["datasourceUid"])
+}
+
+func TestUpsertPerSeriesAlertRuleGroup(t *testing.T) {
+	req := require.New(t)
+
+	spec := alertRuleGroupSpec()
+	spec.Rules = spec.Rules[:1]
+
+	var saved map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/folders/platform-uid":
+			writeJSON(t, w, rawFolder{ID: 2, UID: "platform-uid", Title: "Platform"})
+		case "POST /api/ruler/grafana/api/v1/rules/Platform":
+			req.NoError(json.NewDecoder(r.Body).Decode(&saved))
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{Host: server.URL})
+	req.NoError(err)
+
+	_, err = NewAlertRuleGroups(logr.Discard(), client, nil).Upsert(context.Background(), AlertRuleGroup{
+		Name:      "api",
+		Namespace: "default",
+		Folder:    DashboardFolder{UID: "platform-uid"},
+		Spec:      spec,
+		PerSeries: true,
+	})
+	req.NoError(err)
+
+	req.Equal("api", saved["name"])
+	req.Equal("30s", saved["interval"])
+
+	rule := saved["rules"].([]interface{})[0].(map[string]interface{})
+	req.Equal("10m", rule["for"])
+	req.Equal(map[string]interface{}{"severity": "critical"}, rule["labels"])
+
+	grafanaAlert := rule["grafana_alert"].(map[string]interface{})
+	req.Equal("_alert_condition_", grafanaAlert["condition"])
+
+	data := grafanaAlert["data"].([]interface{})
+	req.Len(data, 3)
+
+	query := data[0].(map[string]interface{})
+	req.Equal("A", query["refId"])
+	req.Equal("prometheus-uid", query["datasourceUid"])
+
+	reduce := data[1].(map[string]interface{})["model"].(map[string]interface{})
+	req.Equal("reduce", reduce["type"])
+	req.Equal("A", reduce["expression"])
+	req.Equal("mean", reduce["reducer"])
+
+	threshold := data[2].(map[string]interface{})["model"].(map[string]interface{})
+	req.Equal("threshold", threshold["type"])
+	req.Equal("_alert_reduce_", threshold["expression"])
+	req.Equal([]interface{}{
+		map[string]interface{}{"evaluator": map[string]interface{}{"type": "gt", "params": []interface{}{0.05}}},
+	}, threshold["conditions"])
+}
+
+func TestToPerSeriesRuleGroupRejectsUnsupportedConditions(t *testing.T) {
+	req := require.New(t)
+
+	spec := alertRuleGroupSpec()
+	spec.Rules = spec.Rules[1:]
+
+	group, err := SpecToAlertRuleGroup("api", spec)
+	req.NoError(err)
+
+	_, err = toPerSeriesRuleGroup(*group.Builder)
+	req.ErrorIs(err, ErrInvalidAlertCondition)
+}
+
+func TestDeleteAlertRuleGroupIgnoresMissingGroups(t *testing.T) {
+	req := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req.Equal("DELETE /api/ruler/grafana/api/v1/rules/Platform/api", r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{Host: server.URL})
+	req.NoError(err)
+
+	req.NoError(NewAlertRuleGroups(logr.Discard(), client, nil).Delete(context.Background(), "Platform", "api"))
+}
@@ -0,0 +1,195 @@
+package grafana
+
+import (
+	"strings"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidateAlertRuleGroupSpec statically checks an alert rule group spec: the
+// datasource and folder it references are not resolved.
+func ValidateAlertRuleGroupSpec(spec v1alpha1.GrafanaAlertRuleGroupSpec) field.ErrorList {
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if spec.FolderRef == nil && spec.Folder == "" {
+		errs = append(errs, field.Required(specPath.Child("folder"), "a folder or folderRef is required"))
+	}
+	if spec.FolderRef != nil && spec.FolderRef.Name == "" {
+		errs = append(errs, field.Required(specPath.Child("folderRef", "name"), ""))
+	}
+
+	errs = append(errs, validateDuration(specPath.Child("interval"), spec.Interval)...)
+	errs = append(errs, validateDatasourceRef(specPath.Child("datasource"), spec.Datasource)...)
+
+	if len(spec.Rules) == 0 {
+		errs = append(errs, field.Required(specPath.Child("rules"), "at least one rule is required"))
+	}
+
+	titles := sets.NewString()
+	for i, rule := range spec.Rules {
+		rulePath := specPath.Child("rules").Index(i)
+
+		if rule.Title == "" {
+			errs = append(errs, field.Required(rulePath.Child("title"), ""))
+		} else if titles.Has(rule.Title) {
+			errs = append(errs, field.Duplicate(rulePath.Child("title"), rule.Title))
+		}
+		titles.Insert(rule.Title)
+
+		errs = append(errs, validateAlertRule(rulePath, rule)...)
+	}
+
+	return errs
+}
+
+func validateAlertRule(path *field.Path, rule v1alpha1.AlertRule) field.ErrorList {
+	var errs field.ErrorList
+
+	errs = append(errs, validateDuration(path.Child("for"), rule.For)...)
+
+	if len(rule.Targets) == 0 {
+		errs = append(errs, field.Required(path.Child("targets"), "at least one target is required"))
+	}
+
+	refs := sets.NewString()
+	for i, target := range rule.Targets {
+		targetPath := path.Child("targets").Index(i)
+
+		query, targetErrs := validateAlertTarget(targetPath, target)
+		errs = append(errs, targetErrs...)
+		if query == nil || query.Ref == "" {
+			continue
+		}
+
+		if refs.Has(query.Ref) {
+			errs = append(errs, field.Duplicate(targetPath.Child("ref"), query.Ref))
+		}
+		refs.Insert(query.Ref)
+	}
+
+	if len(rule.If) == 0 {
+		errs = append(errs, field.Required(path.Child("if"), "at least one condition is required"))
+	}
+
+	for i, condition := range rule.If {
+		errs = append(errs, validateAlertCondition(path.Child("if").Index(i), condition, refs)...)
+	}
+
+	return errs
+}
+
+func validateAlertTarget(path *field.Path, target v1alpha1.AlertTarget) (*v1alpha1.AlertQuery, field.ErrorList) {
+	var errs field.ErrorList
+	var query *v1alpha1.AlertQuery
+	var queryPath *field.Path
+
+	for _, candidate := range []struct {
+		name  string
+		query *v1alpha1.AlertQuery
+	}{
+		{name: "prometheus", query: target.Prometheus},
+		{name: "loki", query: target.Loki},
+		{name: "graphite", query: target.Graphite},
+	} {
+		if candidate.query == nil {
+			continue
+		}
+		if query != nil {
+			return nil, field.ErrorList{field.Forbidden(path.Child(candidate.name), "only one of prometheus, loki or graphite may be specified")}
+		}
+
+		query = candidate.query
+		queryPath = path.Child(candidate.name)
+	}
+
+	if query == nil {
+		return nil, field.ErrorList{field.Required(path, ErrInvalidAlertTarget.Error()+": one of prometheus, loki or graphite required")}
+	}
+
+	if query.Ref == "" {
+		errs = append(errs, field.Required(queryPath.Child("ref"), ""))
+	}
+	if query.Query == "" {
+		errs = append(errs, field.Required(queryPath.Child("query"), ""))
+	}
+	errs = append(errs, validateDuration(queryPath.Child("lookback"), query.Lookback)...)
+
+	return query, errs
+}
+
+func validateAlertCondition(path *field.Path, condition v1alpha1.AlertCondition, refs sets.String) field.ErrorList {
+	var errs field.ErrorList
+
+	var reducers []string
+	for _, candidate := range alertConditionReducers(condition) {
+		if candidate.queryRef == "" {
+			continue
+		}
+
+		reducers = append(reducers, string(candidate.reducer))
+		if !refs.Has(candidate.queryRef) {
+			errs = append(errs, field.NotFound(path.Child(string(candidate.reducer)), candidate.queryRef))
+		}
+	}
+
+	switch {
+	case len(reducers) == 0:
+		errs = append(errs, field.Required(path, ErrInvalidAlertCondition.Error()+": one of avg, sum, count, last, min, max, median, diff or percent_diff required"))
+	case len(reducers) > 1:
+		errs = append(errs, field.Forbidden(path, "only one of "+strings.Join(reducers, ", ")+" may be specified"))
+	}
+
+	thresholds := 0
+	if condition.HasNoValue {
+		thresholds++
+	}
+	if condition.Above != nil {
+		thresholds++
+		errs = append(errs, validateNumber(path.Child("above"), *condition.Above)...)
+	}
+	if condition.Below != nil {
+		thresholds++
+		errs = append(errs, validateNumber(path.Child("below"), *condition.Below)...)
+	}
+	if len(condition.OutsideRange) != 0 {
+		thresholds++
+		errs = append(errs, validateRange(path.Child("outside_range"), condition.OutsideRange)...)
+	}
+	if len(condition.WithinRange) != 0 {
+		thresholds++
+		errs = append(errs, validateRange(path.Child("within_range"), condition.WithinRange)...)
+	}
+
+	switch {
+	case thresholds == 0:
+		errs = append(errs, field.Required(path, ErrInvalidAlertCondition.Error()+": one of has_no_value, above, below, outside_range or within_range required"))
+	case thresholds > 1:
+		errs = append(errs, field.Forbidden(path, "only one of has_no_value, above, below, outside_range or within_range may be specified"))
+	}
+
+	return errs
+}
+
+func validateRange(path *field.Path, bounds []v1alpha1.Number) field.ErrorList {
+	if len(bounds) != 2 {
+		return field.ErrorList{field.Invalid(path, bounds, "expected a lower and an upper bound")}
+	}
+
+	var errs field.ErrorList
+	for i, bound := range bounds {
+		errs = append(errs, validateNumber(path.Index(i), bound)...)
+	}
+
+	return errs
+}
+
+func validateNumber(path *field.Path, number v1alpha1.Number) field.ErrorList {
+	if _, err := number.Float64(); err != nil {
+		return field.ErrorList{field.Invalid(path, number, "not a number")}
+	}
+
+	return nil
+}
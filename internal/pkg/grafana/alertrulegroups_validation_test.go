@@ -0,0 +1,96 @@
+package grafana
+
+import (
+	"testing"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateAlertRuleGroupSpecAcceptsValidSpecs(t *testing.T) {
+	req := require.New(t)
+
+	req.Empty(ValidateAlertRuleGroupSpec(alertRuleGroupSpec()))
+}
+
+func TestValidateAlertRuleGroupSpecRequiresAFolder(t *testing.T) {
+	req := require.New(t)
+
+	spec := alertRuleGroupSpec()
+	spec.Folder = ""
+
+	errs := ValidateAlertRuleGroupSpec(spec)
+
+	req.Len(errs, 1)
+	req.Equal(field.ErrorTypeRequired, errs[0].Type)
+	req.Equal("spec.folder", errs[0].Field)
+}
+
+func TestValidateAlertRuleGroupSpecRejectsDuplicateTitles(t *testing.T) {
+	req := require.New(t)
+
+	spec := alertRuleGroupSpec()
+	spec.Rules[1].Title = spec.Rules[0].Title
+
+	errs := ValidateAlertRuleGroupSpec(spec)
+
+	req.Len(errs, 1)
+	req.Equal(field.ErrorTypeDuplicate, errs[0].Type)
+	req.Equal("spec.rules[1].title", errs[0].Field)
+}
+
+func TestValidateAlertRuleGroupSpecRejectsConditionsOnUnknownQueries(t *testing.T) {
+	req := require.New(t)
+
+	spec := alertRuleGroupSpec()
+	spec.Rules[0].If[0].Avg = "Z"
+
+	errs := ValidateAlertRuleGroupSpec(spec)
+
+	req.Len(errs, 1)
+	req.Equal(field.ErrorTypeNotFound, errs[0].Type)
+	req.Equal("spec.rules[0].if[0].avg", errs[0].Field)
+}
+
+func TestValidateAlertRuleGroupSpecRejectsConditionsWithSeveralThresholds(t *testing.T) {
+	req := require.New(t)
+
+	spec := alertRuleGroupSpec()
+	spec.Rules[0].If[0].HasNoValue = true
+
+	errs := ValidateAlertRuleGroupSpec(spec)
+
+	req.Len(errs, 1)
+	req.Equal(field.ErrorTypeForbidden, errs[0].Type)
+	req.Equal("spec.rules[0].if[0]", errs[0].Field)
+}
+
+func TestValidateAlertRuleGroupSpecRejectsInvalidThresholds(t *testing.T) {
+	req := require.New(t)
+
+	spec := alertRuleGroupSpec()
+	spec.Rules[0].If[0].Above = nil
+	spec.Rules[0].If[0].WithinRange = []v1alpha1.Number{"1", "lots"}
+
+	errs := ValidateAlertRuleGroupSpec(spec)
+
+	req.Len(errs, 1)
+	req.Equal(field.ErrorTypeInvalid, errs[0].Type)
+	req.Equal("spec.rules[0].if[0].within_range[1]", errs[0].Field)
+}
+
+func TestValidateAlertRuleGroupSpecRejectsTargetsWithSeveralQueries(t *testing.T) {
+	req := require.New(t)
+
+	spec := alertRuleGroupSpec()
+	spec.Rules[1].Targets[0].Loki = &v1alpha1.AlertQuery{Ref: "B", Query: `count_over_time({app="api"}[5m])`}
+
+	errs := ValidateAlertRuleGroupSpec(spec)
+
+	req.Len(errs, 2)
+	req.Equal(field.ErrorTypeForbidden, errs[0].Type)
+	req.Equal("spec.rules[1].targets[0].loki", errs[0].Field)
+	// the condition now references an unknown query
+	req.Equal(field.ErrorTypeNotFound, errs[1].Type)
+}
@@ -1,22 +1,124 @@
 package grafana
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"text/template"
+	"time"
 
 	"github.com/K-Phoen/dark/internal/pkg/kubernetes"
 	"github.com/K-Phoen/grabana"
 	"github.com/go-logr/logr"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Modes in which API keys can be created.
+const (
+	// APIKeyMode creates legacy Grafana API keys.
+	APIKeyMode = "apiKey"
+
+	// ServiceAccountMode creates a service account and a token for it.
+	ServiceAccountMode = "serviceAccount"
 )
 
 type APIKey struct {
 	Name string
 	Role string
+	Mode string
+
+	// Lifetime of the keys created in Grafana. Zero means no expiry.
+	SecondsToLive int64
+	// Delay after which keys are replaced. Zero disables periodic rotations.
+	RotateEvery time.Duration
+	// Delay during which a replaced key remains valid.
+	RotationOverlap time.Duration
 
 	SecretName      string
 	SecretNamespace string
 	TokenKey        string
+
+	SecretLabels      map[string]string
+	SecretAnnotations map[string]string
+	// Extra keys of the secret, as Go templates.
+	SecretTemplates map[string]string
+	// Owner of the secret, if any.
+	SecretOwner *metav1.OwnerReference
+}
+
+// SecretTemplateData holds the values available to the templates of the extra
+// keys of an API key secret.
+type SecretTemplateData struct {
+	// Token is the API key or service account token.
+	Token string
+	// Host is the URL of the Grafana instance.
+	Host string
+	// Name is the name of the key in Grafana.
+	Name string
+	// Role is the role of the key: admin, editor or viewer.
+	Role string
+}
+
+// APIKeyState describes the keys created in Grafana for an APIKey.
+type APIKeyState struct {
+	// Name in Grafana of the key currently exposed in the secret.
+	Current   string
+	CreatedAt time.Time
+	// Zero when the current key doesn't expire.
+	ExpiresAt time.Time
+
+	// Key replaced by the current one, kept valid until RevokePreviousAt.
+	Previous         string
+	RevokePreviousAt time.Time
+
+	LastRotationTime time.Time
+
+	// SHA-256 of the token exposed in the secret, used to detect secrets
+	// tampered with.
+	TokenHash string
+}
+
+// NextRotation returns the time at which the current key should be replaced,
+// or a zero time if it never needs to be.
+// Expiring keys are replaced before they expire: once 90% of their lifetime
+// elapsed, or earlier to honor the rotation overlap.
+func (key APIKey) NextRotation(state APIKeyState) time.Time {
+	var next time.Time
+
+	if key.RotateEvery > 0 {
+		next = state.CreatedAt.Add(key.RotateEvery)
+	}
+
+	if !state.ExpiresAt.IsZero() {
+		margin := time.Duration(key.SecondsToLive) * time.Second / 10
+		if key.RotationOverlap > margin {
+			margin = key.RotationOverlap
+		}
+
+		beforeExpiry := state.ExpiresAt.Add(-margin)
+		if next.IsZero() || beforeExpiry.Before(next) {
+			next = beforeExpiry
+		}
+	}
+
+	return next
+}
+
+// NextCheck returns the time at which the key should be reconciled again,
+// either to rotate it or to revoke the key it replaced, or a zero time if
+// nothing is scheduled.
+func (key APIKey) NextCheck(state APIKeyState) time.Time {
+	next := key.NextRotation(state)
+
+	if state.Previous != "" && (next.IsZero() || state.RevokePreviousAt.Before(next)) {
+		next = state.RevokePreviousAt
+	}
+
+	return next
 }
 
 func (key APIKey) GrabanaRole() (grabana.APIKeyRole, error) {
@@ -32,101 +134,408 @@ func (key APIKey) GrabanaRole() (grabana.APIKeyRole, error) {
 	return grabana.ViewerRole, fmt.Errorf("invalid role")
 }
 
+// ServiceAccountRole returns the role of the key, as expected by service
+// accounts.
+func (key APIKey) ServiceAccountRole() (string, error) {
+	switch key.Role {
+	case "admin":
+		return "Admin", nil
+	case "editor":
+		return "Editor", nil
+	case "viewer":
+		return "Viewer", nil
+	}
+
+	return "", fmt.Errorf("invalid role")
+}
+
+// APIKeyNameAnnotation and APIKeyTokenHashAnnotation record on the secret of
+// an API key the name and the token hash of the key it holds: the status of
+// the APIKey might lag behind its secret.
+const (
+	APIKeyNameAnnotation      = "dark/api-key-name"
+	APIKeyTokenHashAnnotation = "dark/api-key-token-hash"
+)
+
 type secretsWriter interface {
 	Read(ctx context.Context, namespace string, ref v1.SecretKeySelector) (string, error)
+	Annotations(ctx context.Context, namespace string, name string) (map[string]string, error)
 	Upsert(ctx context.Context, request kubernetes.SecretUpsertRequest) error
 }
 
+// keyStore creates and revokes the keys (or tokens) of a given APIKey.
+type keyStore interface {
+	exists(ctx context.Context, name string) (bool, error)
+	create(ctx context.Context, name string, secondsToLive int64) (string, error)
+	revoke(ctx context.Context, name string) error
+}
+
 type APIKeys struct {
 	logger        logr.Logger
-	grabanaClient *grabana.Client
+	grafanaClient *Client
 	secrets       secretsWriter
+	now           func() time.Time
 }
 
-func NewAPIKeys(logger logr.Logger, grabanaClient *grabana.Client, secrets secretsWriter) *APIKeys {
+func NewAPIKeys(logger logr.Logger, grafanaClient *Client, secrets secretsWriter) *APIKeys {
 	return &APIKeys{
 		logger:        logger,
-		grabanaClient: grabanaClient,
+		grafanaClient: grafanaClient,
 		secrets:       secrets,
+		now:           time.Now,
 	}
 }
 
-func (keys *APIKeys) Reconcile(ctx context.Context, key APIKey) error {
+// Reconcile makes sure that a valid key exists in Grafana and that it is
+// exposed in the key's Kubernetes secret. Keys are rotated when they are due,
+// and the keys they replaced are revoked once the rotation overlap elapsed.
+// The returned state must be given to the next call.
+func (keys *APIKeys) Reconcile(ctx context.Context, key APIKey, state APIKeyState) (APIKeyState, error) {
 	logger := keys.logger.WithValues("key", key.Name)
+	now := keys.now()
 
-	existingGrafanaKeys, err := keys.grabanaClient.APIKeys(ctx)
+	store, err := keys.store(ctx, key, state)
+	if err != nil {
+		logger.Error(err, "could not prepare key store")
+		return state, err
+	}
+
+	if state.Previous != "" && !now.Before(state.RevokePreviousAt) {
+		if err := keys.revokePrevious(ctx, store, &state); err != nil {
+			return state, err
+		}
+	}
+
+	// keys created before their state was tracked are named after the APIKey
+	if state.Current == "" {
+		state.Current = key.Name
+	}
+
+	token, secretExists, err := keys.readToken(ctx, key)
+	if err != nil {
+		return state, err
+	}
+
+	// secrets created before their token was tracked are trusted
+	if secretExists && state.TokenHash == "" {
+		state.TokenHash = tokenHash(token)
+	}
+
+	if secretExists && tokenHash(token) != state.TokenHash {
+		state, err = keys.recoverState(ctx, store, key, state, token, now)
+		if err != nil {
+			return state, err
+		}
+	}
+
+	keyExists, err := store.exists(ctx, state.Current)
 	if err != nil {
 		logger.Error(err, "could not check existing keys in Grafana")
-		return err
+		return state, err
 	}
 
-	// the API key does not exist in Grafana, we need to create it and its Kubernetes secret
-	if _, ok := existingGrafanaKeys[key.Name]; !ok {
-		return keys.createKey(ctx, key)
+	tampered := secretExists && tokenHash(token) != state.TokenHash
+	if tampered {
+		logger.Info("token in secret was modified, re-creating the key")
 	}
 
-	// the API key exists, but the secret does not. We need to re-create both
-	nope := false
-	_, err = keys.secrets.Read(ctx, key.SecretNamespace, v1.SecretKeySelector{
-		LocalObjectReference: v1.LocalObjectReference{
-			Name: key.SecretName,
-		},
-		Key:      key.TokenKey,
-		Optional: &nope,
-	})
+	// the key or its secret is missing: the token can't be recovered, we need to re-create both
+	if !keyExists || !secretExists || tampered {
+		if keyExists {
+			if err := store.revoke(ctx, state.Current); err != nil {
+				return state, err
+			}
+		}
+
+		return keys.issue(ctx, store, key, state, state.Current, now)
+	}
+
+	// keys that existed before rotations were enabled start their lifecycle now
+	if state.CreatedAt.IsZero() {
+		state.CreatedAt = now
+	}
 
-	if err == kubernetes.ErrSecretNotFound || err == kubernetes.ErrKeyNotFoundInSecret {
-		if err := keys.Delete(ctx, key.Name); err != nil {
+	nextRotation := key.NextRotation(state)
+	if nextRotation.IsZero() || now.Before(nextRotation) {
+		// api key exists, secret exist = the secret only needs to reflect the latest layout
+		return state, keys.writeSecret(ctx, key, state.Current, token)
+	}
+
+	return keys.rotate(ctx, store, key, state, now)
+}
+
+func (keys *APIKeys) Delete(ctx context.Context, key APIKey, state APIKeyState) error {
+	if key.Mode == ServiceAccountMode {
+		if err := keys.deleteServiceAccount(ctx, key.Name); err != nil {
 			return err
 		}
+	}
+
+	// keys issued before switching to service accounts, and not migrated,
+	// are still legacy API keys
+	store := &legacyKeyStore{grafanaClient: keys.grafanaClient}
+
+	for _, name := range []string{key.Name, state.Current, state.Previous} {
+		if name == "" {
+			continue
+		}
 
-		return keys.createKey(ctx, key)
+		if err := store.revoke(ctx, name); err != nil {
+			keys.logger.Error(err, "could not delete key in Grafana", "key", name)
+			return err
+		}
 	}
 
-	// api key exists, secret exist = nothing to do (we assume secret is up-to-date)
 	return nil
 }
 
-func (keys *APIKeys) Delete(ctx context.Context, name string) error {
-	if err := keys.grabanaClient.DeleteAPIKeyByName(ctx, name); err != nil && err != grabana.ErrAPIKeyNotFound {
-		keys.logger.Error(err, "could not delete key in Grafana", "key", name)
-		return err
+// recoverState catches up with a key issued by DARK whose state was not
+// recorded yet: its name and token hash are recorded on its secret, and the
+// key it replaced, if any, is revoked once the rotation overlap elapsed.
+// Secrets not matching their own record were modified outside of DARK.
+func (keys *APIKeys) recoverState(ctx context.Context, store keyStore, key APIKey, state APIKeyState, token string, now time.Time) (APIKeyState, error) {
+	annotations, err := keys.secrets.Annotations(ctx, key.SecretNamespace, key.SecretName)
+	if err != nil {
+		return state, err
 	}
 
-	return nil
+	name := annotations[APIKeyNameAnnotation]
+	if name == "" || annotations[APIKeyTokenHashAnnotation] != tokenHash(token) {
+		return state, nil
+	}
+
+	keys.logger.Info("recovering api key state from its secret", "key", key.Name, "name", name)
+
+	if name != state.Current {
+		// a rotation overlapping a previous one: the oldest key has to go
+		if state.Previous != "" && state.Previous != name {
+			if err := keys.revokePrevious(ctx, store, &state); err != nil {
+				return state, err
+			}
+		}
+
+		state.Previous = state.Current
+		state.RevokePreviousAt = now.Add(key.RotationOverlap)
+		state.LastRotationTime = now
+		state.Current = name
+		state.CreatedAt = now
+		state.ExpiresAt = time.Time{}
+		if key.SecondsToLive > 0 {
+			state.ExpiresAt = now.Add(time.Duration(key.SecondsToLive) * time.Second)
+		}
+	}
+
+	state.TokenHash = tokenHash(token)
+
+	return state, nil
 }
 
-func (keys *APIKeys) createKey(ctx context.Context, key APIKey) error {
+// rotate replaces the current key with a new one. The replaced key is revoked
+// once the rotation overlap elapsed.
+func (keys *APIKeys) rotate(ctx context.Context, store keyStore, key APIKey, state APIKeyState, now time.Time) (APIKeyState, error) {
+	keys.logger.Info("rotating api key", "key", key.Name, "current", state.Current)
+
+	// a rotation overlapping a previous one: the oldest key has to go
+	if state.Previous != "" {
+		if err := keys.revokePrevious(ctx, store, &state); err != nil {
+			return state, err
+		}
+	}
+
+	replaced := state.Current
+
+	state, err := keys.issue(ctx, store, key, state, key.Name+"-"+now.UTC().Format("20060102150405"), now)
+	if err != nil {
+		return state, err
+	}
+
+	state.LastRotationTime = now
+	state.Previous = replaced
+	state.RevokePreviousAt = now.Add(key.RotationOverlap)
+
+	if key.RotationOverlap == 0 {
+		if err := keys.revokePrevious(ctx, store, &state); err != nil {
+			return state, err
+		}
+	}
+
+	return state, nil
+}
+
+// issue creates a key with the given name and stores it in the key's secret.
+func (keys *APIKeys) issue(ctx context.Context, store keyStore, key APIKey, state APIKeyState, name string, now time.Time) (APIKeyState, error) {
 	logger := keys.logger.WithValues("key", key.Name)
 
-	fmt.Printf("creating new api key\n")
+	logger.Info("creating new api key", "name", name)
 
-	role, err := key.GrabanaRole()
+	token, err := store.create(ctx, name, key.SecondsToLive)
 	if err != nil {
+		logger.Error(err, "could not create Grafana API key")
+		return state, err
+	}
+
+	if err := keys.writeSecret(ctx, key, name, token); err != nil {
+		// the new key is useless without its secret
+		_ = store.revoke(ctx, name)
+
+		return state, err
+	}
+
+	state.Current = name
+	state.TokenHash = tokenHash(token)
+	state.CreatedAt = now
+	state.ExpiresAt = time.Time{}
+	if key.SecondsToLive > 0 {
+		state.ExpiresAt = now.Add(time.Duration(key.SecondsToLive) * time.Second)
+	}
+
+	return state, nil
+}
+
+func (keys *APIKeys) revokePrevious(ctx context.Context, store keyStore, state *APIKeyState) error {
+	keys.logger.Info("revoking replaced api key", "name", state.Previous)
+
+	if err := store.revoke(ctx, state.Previous); err != nil {
 		return err
 	}
 
-	// create the token in Grafana
-	token, err := keys.grabanaClient.CreateAPIKey(ctx, grabana.CreateAPIKeyRequest{
-		Name: key.Name,
-		Role: role,
+	state.Previous = ""
+	state.RevokePreviousAt = time.Time{}
+
+	return nil
+}
+
+func (keys *APIKeys) store(ctx context.Context, key APIKey, state APIKeyState) (keyStore, error) {
+	if key.Mode == ServiceAccountMode {
+		return keys.serviceAccountStore(ctx, key, state)
+	}
+
+	role, err := key.GrabanaRole()
+	if err != nil {
+		return nil, err
+	}
+
+	return &legacyKeyStore{grafanaClient: keys.grafanaClient, role: role}, nil
+}
+
+// readToken reads the token held by the Kubernetes secret of the given key.
+// It returns whether the token was found.
+func (keys *APIKeys) readToken(ctx context.Context, key APIKey) (string, bool, error) {
+	nope := false
+	token, err := keys.secrets.Read(ctx, key.SecretNamespace, v1.SecretKeySelector{
+		LocalObjectReference: v1.LocalObjectReference{
+			Name: key.SecretName,
+		},
+		Key:      key.TokenKey,
+		Optional: &nope,
+	})
+	if errors.Is(err, kubernetes.ErrSecretNotFound) || errors.Is(err, kubernetes.ErrKeyNotFoundInSecret) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return token, true, nil
+}
+
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// writeSecret stores the given token in the Kubernetes secret of the key,
+// along with the extra keys rendered from its templates.
+func (keys *APIKeys) writeSecret(ctx context.Context, key APIKey, name string, token string) error {
+	secretPayload, err := RenderAPIKeySecret(key, SecretTemplateData{
+		Token: token,
+		Host:  keys.grafanaClient.url(""),
+		Name:  name,
+		Role:  key.Role,
 	})
 	if err != nil {
-		logger.Error(err, "could not create Grafana API key")
 		return err
 	}
 
-	secretPayload := make(map[string][]byte)
-	secretPayload[key.TokenKey] = []byte(token)
+	annotations := map[string]string{}
+	for annotation, value := range key.SecretAnnotations {
+		annotations[annotation] = value
+	}
+	annotations[APIKeyNameAnnotation] = name
+	annotations[APIKeyTokenHashAnnotation] = tokenHash(token)
+
+	request := kubernetes.SecretUpsertRequest{
+		Name:        key.SecretName,
+		Namespace:   key.SecretNamespace,
+		Labels:      key.SecretLabels,
+		Annotations: annotations,
+		Data:        secretPayload,
+	}
+	if key.SecretOwner != nil {
+		request.OwnerReferences = []metav1.OwnerReference{*key.SecretOwner}
+	}
 
 	// map it to a kubernetes secret
-	err = keys.secrets.Upsert(ctx, kubernetes.SecretUpsertRequest{
-		Name:      key.SecretName,
-		Namespace: key.SecretNamespace,
-		Data:      secretPayload,
-	})
+	if err := keys.secrets.Upsert(ctx, request); err != nil {
+		keys.logger.Error(err, "could not create Kubernetes secret for API key", "key", key.Name)
+		return err
+	}
+
+	return nil
+}
+
+// RenderAPIKeySecret computes the content of the secret of an API key: its
+// token and the extra keys rendered from its templates.
+func RenderAPIKeySecret(key APIKey, data SecretTemplateData) (map[string][]byte, error) {
+	secretPayload := make(map[string][]byte, len(key.SecretTemplates)+1)
+
+	for secretKey, content := range key.SecretTemplates {
+		tpl, err := template.New(secretKey).Option("missingkey=error").Parse(content)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template for secret key %s: %w", secretKey, err)
+		}
+
+		buffer := &bytes.Buffer{}
+		if err := tpl.Execute(buffer, data); err != nil {
+			return nil, fmt.Errorf("could not render template for secret key %s: %w", secretKey, err)
+		}
+
+		secretPayload[secretKey] = buffer.Bytes()
+	}
+
+	secretPayload[key.TokenKey] = []byte(data.Token)
+
+	return secretPayload, nil
+}
+
+// legacyKeyStore manages legacy Grafana API keys.
+type legacyKeyStore struct {
+	grafanaClient *Client
+	role          grabana.APIKeyRole
+}
+
+func (store *legacyKeyStore) exists(ctx context.Context, name string) (bool, error) {
+	existingGrafanaKeys, err := store.grafanaClient.APIKeys(ctx)
 	if err != nil {
-		logger.Error(err, "could not create Kubernetes secret for API key")
+		return false, err
+	}
+
+	_, ok := existingGrafanaKeys[name]
+
+	return ok, nil
+}
+
+func (store *legacyKeyStore) create(ctx context.Context, name string, secondsToLive int64) (string, error) {
+	return store.grafanaClient.CreateAPIKey(ctx, grabana.CreateAPIKeyRequest{
+		Name:          name,
+		Role:          store.role,
+		SecondsToLive: int(secondsToLive),
+	})
+}
+
+func (store *legacyKeyStore) revoke(ctx context.Context, name string) error {
+	if err := store.grafanaClient.DeleteAPIKeyByName(ctx, name); err != nil && err != grabana.ErrAPIKeyNotFound {
 		return err
 	}
 
@@ -0,0 +1,305 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLegacyKeys emulates Grafana's legacy API keys endpoints.
+type fakeLegacyKeys struct {
+	lock   sync.Mutex
+	nextID int
+	keys   map[string]int
+}
+
+func newFakeLegacyKeys(names ...string) *fakeLegacyKeys {
+	fake := &fakeLegacyKeys{keys: map[string]int{}}
+	for _, name := range names {
+		fake.nextID++
+		fake.keys[name] = fake.nextID
+	}
+
+	return fake
+}
+
+func (fake *fakeLegacyKeys) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fake.lock.Lock()
+	defer fake.lock.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/auth/keys":
+		keys := []map[string]interface{}{}
+		for name, id := range fake.keys {
+			keys = append(keys, map[string]interface{}{"id": id, "name": name})
+		}
+		_ = json.NewEncoder(w).Encode(keys)
+	case r.Method == http.MethodPost && r.URL.Path == "/api/auth/keys":
+		var request struct {
+			Name string `json:"name"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+
+		fake.nextID++
+		fake.keys[request.Name] = fake.nextID
+		_ = json.NewEncoder(w).Encode(map[string]string{"key": "token-" + request.Name})
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/auth/keys/"):
+		id, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/auth/keys/"))
+		for name, keyID := range fake.keys {
+			if keyID == id {
+				delete(fake.keys, name)
+			}
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (fake *fakeLegacyKeys) names() []string {
+	fake.lock.Lock()
+	defer fake.lock.Unlock()
+
+	names := make([]string, 0, len(fake.keys))
+	for name := range fake.keys {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+func testAPIKeys(t *testing.T, handler http.Handler, secrets *inMemorySecrets, now time.Time) *APIKeys {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(ClientConfig{Host: server.URL})
+	require.NoError(t, err)
+
+	keys := NewAPIKeys(logr.Discard(), client, secrets)
+	keys.now = func() time.Time { return now }
+
+	return keys
+}
+
+func testLegacyKey() APIKey {
+	return APIKey{
+		Name:            "ci",
+		Role:            "viewer",
+		Mode:            APIKeyMode,
+		SecretName:      "ci",
+		SecretNamespace: "apps",
+		TokenKey:        "token",
+	}
+}
+
+func TestReconcileCreatesExpiringKeys(t *testing.T) {
+	req := require.New(t)
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	fakeGrafana := newFakeLegacyKeys()
+	secrets := &inMemorySecrets{secrets: map[string]map[string][]byte{}}
+	keys := testAPIKeys(t, fakeGrafana, secrets, now)
+
+	key := testLegacyKey()
+	key.SecondsToLive = 3600
+
+	state, err := keys.Reconcile(context.Background(), key, APIKeyState{})
+	req.NoError(err)
+
+	req.Equal("ci", state.Current)
+	req.Equal(now, state.CreatedAt)
+	req.Equal(now.Add(time.Hour), state.ExpiresAt)
+	req.Equal("token-ci", string(secrets.secrets["apps/ci"]["token"]))
+	// rotated once 90% of its lifetime elapsed
+	req.Equal(now.Add(54*time.Minute), key.NextRotation(state))
+}
+
+func TestReconcileRotatesKeysWithAnOverlap(t *testing.T) {
+	req := require.New(t)
+
+	createdAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := createdAt.Add(31 * 24 * time.Hour)
+	fakeGrafana := newFakeLegacyKeys("ci")
+	secrets := &inMemorySecrets{secrets: map[string]map[string][]byte{
+		"apps/ci": {"token": []byte("token-ci")},
+	}}
+
+	key := testLegacyKey()
+	key.RotateEvery = 30 * 24 * time.Hour
+	key.RotationOverlap = time.Hour
+
+	state, err := testAPIKeys(t, fakeGrafana, secrets, now).Reconcile(context.Background(), key, APIKeyState{
+		Current:   "ci",
+		CreatedAt: createdAt,
+	})
+	req.NoError(err)
+
+	req.Equal("ci-20230201000000", state.Current)
+	req.Equal(now, state.CreatedAt)
+	req.Equal(now, state.LastRotationTime)
+	req.Equal("ci", state.Previous)
+	req.Equal(now.Add(time.Hour), state.RevokePreviousAt)
+	req.Equal(now.Add(time.Hour), key.NextCheck(state))
+	req.Equal("token-ci-20230201000000", string(secrets.secrets["apps/ci"]["token"]))
+	req.ElementsMatch([]string{"ci", "ci-20230201000000"}, fakeGrafana.names())
+
+	// once the overlap elapsed, the replaced key is revoked
+	state, err = testAPIKeys(t, fakeGrafana, secrets, now.Add(2*time.Hour)).Reconcile(context.Background(), key, state)
+	req.NoError(err)
+
+	req.Equal("ci-20230201000000", state.Current)
+	req.Empty(state.Previous)
+	req.ElementsMatch([]string{"ci-20230201000000"}, fakeGrafana.names())
+	req.Equal(now.Add(key.RotateEvery), key.NextCheck(state))
+}
+
+func TestReconcileRecreatesKeysWhoseSecretIsGone(t *testing.T) {
+	req := require.New(t)
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	fakeGrafana := newFakeLegacyKeys("ci")
+	secrets := &inMemorySecrets{secrets: map[string]map[string][]byte{}}
+
+	state, err := testAPIKeys(t, fakeGrafana, secrets, now).Reconcile(context.Background(), testLegacyKey(), APIKeyState{})
+	req.NoError(err)
+
+	req.Equal("ci", state.Current)
+	req.Equal("token-ci", string(secrets.secrets["apps/ci"]["token"]))
+	req.ElementsMatch([]string{"ci"}, fakeGrafana.names())
+}
+
+func TestReconcileRecreatesKeysWhoseSecretWasTamperedWith(t *testing.T) {
+	req := require.New(t)
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	fakeGrafana := newFakeLegacyKeys("ci")
+	secrets := &inMemorySecrets{secrets: map[string]map[string][]byte{
+		"apps/ci": {"token": []byte("forged")},
+	}}
+
+	state, err := testAPIKeys(t, fakeGrafana, secrets, now).Reconcile(context.Background(), testLegacyKey(), APIKeyState{
+		Current:   "ci",
+		CreatedAt: now,
+		TokenHash: tokenHash("token-ci"),
+	})
+	req.NoError(err)
+
+	req.Equal("ci", state.Current)
+	req.Equal(tokenHash("token-ci"), state.TokenHash)
+	req.Equal("token-ci", string(secrets.secrets["apps/ci"]["token"]))
+	req.ElementsMatch([]string{"ci"}, fakeGrafana.names())
+}
+
+func TestRenderAPIKeySecret(t *testing.T) {
+	req := require.New(t)
+
+	key := testLegacyKey()
+	key.TokenKey = "GRAFANA_TOKEN"
+	key.SecretTemplates = map[string]string{
+		"GRAFANA_URL": "{{ .Host }}",
+		"grafana.ini": "[auth]\nkey = {{ .Name }} ({{ .Role }})\ntoken = {{ .Token }}",
+	}
+
+	payload, err := RenderAPIKeySecret(key, SecretTemplateData{
+		Token: "glsa_secret",
+		Host:  "http://grafana",
+		Name:  "ci",
+		Role:  "viewer",
+	})
+	req.NoError(err)
+
+	req.Equal("glsa_secret", string(payload["GRAFANA_TOKEN"]))
+	req.Equal("http://grafana", string(payload["GRAFANA_URL"]))
+	req.Equal("[auth]\nkey = ci (viewer)\ntoken = glsa_secret", string(payload["grafana.ini"]))
+}
+
+func TestReconcileRecordsTheKeyOnItsSecret(t *testing.T) {
+	req := require.New(t)
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	secrets := &inMemorySecrets{secrets: map[string]map[string][]byte{}}
+
+	key := testLegacyKey()
+	key.SecretAnnotations = map[string]string{"team": "platform", APIKeyNameAnnotation: "forged"}
+
+	_, err := testAPIKeys(t, newFakeLegacyKeys(), secrets, now).Reconcile(context.Background(), key, APIKeyState{})
+	req.NoError(err)
+
+	req.Equal(map[string]string{
+		"team":                    "platform",
+		APIKeyNameAnnotation:      "ci",
+		APIKeyTokenHashAnnotation: tokenHash("token-ci"),
+	}, secrets.annotations["apps/ci"])
+}
+
+func TestReconcileCatchesUpWithRotationsMissingFromTheState(t *testing.T) {
+	req := require.New(t)
+
+	createdAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := createdAt.Add(31 * 24 * time.Hour)
+	fakeGrafana := newFakeLegacyKeys("ci")
+	secrets := &inMemorySecrets{secrets: map[string]map[string][]byte{
+		"apps/ci": {"token": []byte("token-ci")},
+	}}
+
+	key := testLegacyKey()
+	key.RotateEvery = 30 * 24 * time.Hour
+	key.RotationOverlap = time.Hour
+
+	staleState := APIKeyState{Current: "ci", CreatedAt: createdAt, TokenHash: tokenHash("token-ci")}
+
+	_, err := testAPIKeys(t, fakeGrafana, secrets, now).Reconcile(context.Background(), key, staleState)
+	req.NoError(err)
+
+	// the rotated state was not recorded: the APIKey is reconciled again from a stale state
+	state, err := testAPIKeys(t, fakeGrafana, secrets, now.Add(time.Minute)).Reconcile(context.Background(), key, staleState)
+	req.NoError(err)
+
+	req.Equal("ci-20230201000000", state.Current)
+	req.Equal(tokenHash("token-ci-20230201000000"), state.TokenHash)
+	req.Equal("ci", state.Previous)
+	req.Equal("token-ci-20230201000000", string(secrets.secrets["apps/ci"]["token"]))
+	req.ElementsMatch([]string{"ci", "ci-20230201000000"}, fakeGrafana.names())
+
+	// the replaced key is still revoked once the overlap elapsed
+	state, err = testAPIKeys(t, fakeGrafana, secrets, state.RevokePreviousAt).Reconcile(context.Background(), key, state)
+	req.NoError(err)
+
+	req.Empty(state.Previous)
+	req.ElementsMatch([]string{"ci-20230201000000"}, fakeGrafana.names())
+}
+
+func TestReconcileRecreatesKeysWhoseSecretDoesNotMatchItsRecord(t *testing.T) {
+	req := require.New(t)
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	fakeGrafana := newFakeLegacyKeys("ci")
+	secrets := &inMemorySecrets{
+		secrets: map[string]map[string][]byte{
+			"apps/ci": {"token": []byte("forged")},
+		},
+		annotations: map[string]map[string]string{
+			"apps/ci": {APIKeyNameAnnotation: "ci", APIKeyTokenHashAnnotation: tokenHash("token-ci")},
+		},
+	}
+
+	state, err := testAPIKeys(t, fakeGrafana, secrets, now).Reconcile(context.Background(), testLegacyKey(), APIKeyState{
+		Current:   "ci",
+		CreatedAt: now,
+		TokenHash: tokenHash("token-ci"),
+	})
+	req.NoError(err)
+
+	req.Equal(tokenHash("token-ci"), state.TokenHash)
+	req.Equal("token-ci", string(secrets.secrets["apps/ci"]["token"]))
+}
@@ -0,0 +1,94 @@
+package grafana
+
+import (
+	"io"
+	"text/template"
+	"time"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidateAPIKeySpec statically checks an API key spec.
+func ValidateAPIKeySpec(spec v1alpha1.APIKeySpec) field.ErrorList {
+	var errs field.ErrorList
+
+	specPath := field.NewPath("spec")
+
+	if _, err := (APIKey{Role: spec.Role}).GrabanaRole(); err != nil {
+		errs = append(errs, field.NotSupported(specPath.Child("role"), spec.Role, []string{"admin", "editor", "viewer"}))
+	}
+
+	if spec.Mode != "" && spec.Mode != APIKeyMode && spec.Mode != ServiceAccountMode {
+		errs = append(errs, field.NotSupported(specPath.Child("mode"), spec.Mode, []string{APIKeyMode, ServiceAccountMode}))
+	}
+
+	if spec.Secret != nil {
+		errs = append(errs, validateAPIKeySecret(specPath.Child("secret"), *spec.Secret)...)
+	}
+
+	errs = append(errs, validateDuration(specPath.Child("rotateEvery"), spec.RotateEvery)...)
+	errs = append(errs, validateDuration(specPath.Child("rotationOverlap"), spec.RotationOverlap)...)
+
+	if spec.SecondsToLive < 0 {
+		errs = append(errs, field.Invalid(specPath.Child("secondsToLive"), spec.SecondsToLive, "must not be negative"))
+	}
+
+	overlap, err := time.ParseDuration(spec.RotationOverlap)
+	if spec.RotationOverlap == "" || err != nil {
+		return errs
+	}
+
+	if spec.SecondsToLive > 0 && overlap >= time.Duration(spec.SecondsToLive)*time.Second {
+		errs = append(errs, field.Invalid(specPath.Child("rotationOverlap"), spec.RotationOverlap, "must be shorter than the lifetime of the keys"))
+	}
+
+	if rotateEvery, err := time.ParseDuration(spec.RotateEvery); err == nil && overlap >= rotateEvery {
+		errs = append(errs, field.Invalid(specPath.Child("rotationOverlap"), spec.RotationOverlap, "must be shorter than the rotation period"))
+	}
+
+	return errs
+}
+
+func validateAPIKeySecret(path *field.Path, secret v1alpha1.APIKeySecret) field.ErrorList {
+	var errs field.ErrorList
+
+	if secret.Name != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(secret.Name) {
+			errs = append(errs, field.Invalid(path.Child("name"), secret.Name, msg))
+		}
+	}
+
+	tokenKey := secret.TokenKey
+	if tokenKey == "" {
+		tokenKey = "token"
+	} else {
+		for _, msg := range validation.IsConfigMapKey(tokenKey) {
+			errs = append(errs, field.Invalid(path.Child("tokenKey"), tokenKey, msg))
+		}
+	}
+
+	for secretKey, content := range secret.Templates {
+		templatePath := path.Child("templates").Key(secretKey)
+
+		for _, msg := range validation.IsConfigMapKey(secretKey) {
+			errs = append(errs, field.Invalid(templatePath, secretKey, msg))
+		}
+
+		if secretKey == tokenKey {
+			errs = append(errs, field.Duplicate(templatePath, secretKey))
+		}
+
+		// rendering the template with empty values catches references to unknown fields
+		tpl, err := template.New(secretKey).Parse(content)
+		if err == nil {
+			err = tpl.Execute(io.Discard, SecretTemplateData{})
+		}
+		if err != nil {
+			errs = append(errs, field.Invalid(templatePath, content, err.Error()))
+		}
+	}
+
+	return errs
+}
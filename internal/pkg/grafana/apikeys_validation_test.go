@@ -0,0 +1,62 @@
+package grafana
+
+import (
+	"testing"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateAPIKeySpecAcceptsValidSpecs(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateAPIKeySpec(v1alpha1.APIKeySpec{
+		Role:            "viewer",
+		SecondsToLive:   86400,
+		RotateEvery:     "12h",
+		RotationOverlap: "1h",
+		Secret: &v1alpha1.APIKeySecret{
+			Name:     "grafana-credentials",
+			TokenKey: "GRAFANA_TOKEN",
+			Templates: map[string]string{
+				"GRAFANA_URL": "{{ .Host }}",
+			},
+		},
+	})
+
+	req.Empty(errs)
+}
+
+func TestValidateAPIKeySpecRejectsOverlapsLongerThanRotations(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateAPIKeySpec(v1alpha1.APIKeySpec{
+		Role:            "viewer",
+		RotateEvery:     "1h",
+		RotationOverlap: "2h",
+	})
+
+	req.Len(errs, 1)
+	req.Equal(field.ErrorTypeInvalid, errs[0].Type)
+	req.Equal("spec.rotationOverlap", errs[0].Field)
+}
+
+func TestValidateAPIKeySpecRejectsInvalidSecretTemplates(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateAPIKeySpec(v1alpha1.APIKeySpec{
+		Role: "viewer",
+		Secret: &v1alpha1.APIKeySecret{
+			Templates: map[string]string{
+				"token":       "{{ .Token }}",
+				"GRAFANA_URL": "{{ .URL }}",
+			},
+		},
+	})
+
+	req.Len(errs, 2)
+
+	fields := []string{errs[0].Field, errs[1].Field}
+	req.ElementsMatch([]string{"spec.secret.templates[token]", "spec.secret.templates[GRAFANA_URL]"}, fields)
+}
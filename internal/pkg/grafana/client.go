@@ -0,0 +1,165 @@
+package grafana
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/K-Phoen/dark/internal/pkg/metrics"
+	"github.com/K-Phoen/grabana"
+)
+
+var ErrInvalidCACertificate = fmt.Errorf("invalid CA certificate")
+
+// ClientConfig describes how to reach and authenticate to a Grafana instance.
+type ClientConfig struct {
+	Host string
+
+	APIToken string
+
+	Username string
+	Password string
+
+	SkipTLSVerify bool
+	CACertificate string
+}
+
+// Client is a Grafana HTTP client.
+// It embeds a grabana client, and implements the few API calls grabana doesn't
+// expose.
+type Client struct {
+	*grabana.Client
+
+	http   *http.Client
+	config ClientConfig
+}
+
+func NewClient(config ClientConfig) (*Client, error) {
+	tlsConfig := &tls.Config{
+		//nolint:gosec
+		InsecureSkipVerify: config.SkipTLSVerify,
+	}
+
+	if config.CACertificate != "" {
+		certPool, err := x509.SystemCertPool()
+		if err != nil {
+			certPool = x509.NewCertPool()
+		}
+
+		if !certPool.AppendCertsFromPEM([]byte(config.CACertificate)) {
+			return nil, ErrInvalidCACertificate
+		}
+
+		tlsConfig.RootCAs = certPool
+	}
+
+	opts := []grabana.Option{}
+	if config.APIToken != "" {
+		opts = append(opts, grabana.WithAPIToken(config.APIToken))
+	} else if config.Username != "" {
+		opts = append(opts, grabana.WithBasicAuth(config.Username, config.Password))
+	}
+
+	httpClient := makeHTTPClient(tlsConfig)
+
+	return &Client{
+		Client: grabana.NewClient(httpClient, config.Host, opts...),
+		http:   httpClient,
+		config: config,
+	}, nil
+}
+
+func makeHTTPClient(tlsConfig *tls.Config) *http.Client {
+	return &http.Client{
+		Transport: metrics.InstrumentTransport(&http.Transport{
+			TLSClientConfig: tlsConfig,
+		}),
+		Timeout: 10 * time.Second, // Large, but better than no timeout.
+	}
+}
+
+// Health checks that the Grafana instance is up and reachable.
+func (client *Client) Health(ctx context.Context) error {
+	resp, err := client.get(ctx, "/api/health")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return client.httpError(resp)
+	}
+
+	return nil
+}
+
+func (client *Client) modifyRequest(request *http.Request) {
+	if client.config.APIToken != "" {
+		request.Header.Add("Authorization", "Bearer "+client.config.APIToken)
+	} else if client.config.Username != "" {
+		request.SetBasicAuth(client.config.Username, client.config.Password)
+	}
+}
+
+func (client *Client) httpError(resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("could not query grafana: %s (HTTP status %d)", body, resp.StatusCode)
+}
+
+func (client *Client) get(ctx context.Context, path string) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, client.url(path), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client.modifyRequest(request)
+
+	return client.http.Do(request)
+}
+
+func (client *Client) delete(ctx context.Context, path string) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete, client.url(path), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client.modifyRequest(request)
+
+	return client.http.Do(request)
+}
+
+func (client *Client) sendJSON(ctx context.Context, method string, path string, body interface{}) (*http.Response, error) {
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, client.url(path), bytes.NewReader(buf))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Add("Content-Type", "application/json")
+	client.modifyRequest(request)
+
+	return client.http.Do(request)
+}
+
+func (client *Client) url(path string) string {
+	return client.config.Host + path
+}
+
+func decodeJSON(input io.Reader, data interface{}) error {
+	return json.NewDecoder(input).Decode(data)
+}
@@ -4,51 +4,191 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
 
+	"github.com/K-Phoen/dark/api/v1alpha1"
 	"github.com/K-Phoen/grabana"
 	"github.com/K-Phoen/grabana/dashboard"
 	"github.com/K-Phoen/grabana/decoder"
+	"github.com/K-Phoen/sdk"
 	"gopkg.in/yaml.v3"
 )
 
+// DeployedDashboard describes a dashboard, as deployed in Grafana.
+type DeployedDashboard struct {
+	UID       string
+	URL       string
+	Version   uint
+	FolderUID string
+
+	// FolderCreated tells whether the folder was created while deploying
+	// the dashboard.
+	FolderCreated bool
+}
+
+// DashboardFolder designates the folder holding a dashboard, either by title
+// or by UID. Folders designated by title are created if needed.
+type DashboardFolder struct {
+	Title string
+	UID   string
+}
+
 type Creator struct {
-	grabanaClient *grabana.Client
+	grafanaClient *Client
 }
 
-func NewCreator(grabanaClient *grabana.Client) *Creator {
-	return &Creator{grabanaClient: grabanaClient}
+func NewCreator(grafanaClient *Client) *Creator {
+	return &Creator{grafanaClient: grafanaClient}
 }
 
-func (creator *Creator) FromRawSpec(ctx context.Context, folderName string, uid string, rawJSON []byte) error {
-	if folderName == "" {
-		return fmt.Errorf("folder can not be empty")
+func (creator *Creator) FromRawSpec(ctx context.Context, folder DashboardFolder, uid string, rawJSON []byte) (DeployedDashboard, error) {
+	if folder.Title == "" && folder.UID == "" {
+		return DeployedDashboard{}, fmt.Errorf("folder can not be empty")
 	}
 
-	spec := make(map[string]interface{})
-	if err := json.Unmarshal(rawJSON, &spec); err != nil {
-		return fmt.Errorf("could not unmarshall dashboard json spec: %w", err)
+	dashboardBuilder, err := builderFromRawSpec(uid, rawJSON)
+	if err != nil {
+		return DeployedDashboard{}, err
 	}
 
-	dashboardYaml, err := yaml.Marshal(spec)
+	return creator.upsertDashboard(ctx, folder, dashboardBuilder)
+}
+
+// Drifted tells whether the dashboard held by Grafana differs from the one
+// FromRawSpec deploys for the given spec. Both are normalized through the
+// same model, and only the fields DARK sets are compared: fields Grafana adds
+// when storing a dashboard, its ID and its version don't count as drifts.
+// A dashboard missing from Grafana or living in another folder is considered
+// as drifted.
+func (creator *Creator) Drifted(ctx context.Context, folder DashboardFolder, uid string, rawJSON []byte) (bool, error) {
+	dashboardBuilder, err := builderFromRawSpec(uid, rawJSON)
 	if err != nil {
-		return fmt.Errorf("could not convert dashboard spec to yaml: %w", err)
+		return false, err
 	}
 
-	dashboardBuilder, err := decoder.UnmarshalYAML(bytes.NewBuffer(dashboardYaml))
+	actual, err := creator.grafanaClient.dashboardByUID(ctx, uid)
+	if err == grabana.ErrDashboardNotFound {
+		return true, nil
+	}
 	if err != nil {
-		return fmt.Errorf("could not unmarshall dashboard YAML spec: %w", err)
+		return false, fmt.Errorf("could not fetch dashboard from Grafana: %w", err)
 	}
 
-	if err := dashboard.UID(uid)(&dashboardBuilder); err != nil {
-		return fmt.Errorf("could not set dashboard UID: %w", err)
+	if folder.UID != "" && actual.Meta.FolderUID != folder.UID {
+		return true, nil
+	}
+	if folder.UID == "" && !strings.EqualFold(actual.Meta.FolderTitle, folder.Title) {
+		return true, nil
+	}
+
+	expectedContent, err := normalizedBoard(dashboardBuilder.Internal())
+	if err != nil {
+		return false, err
+	}
+	actualContent, err := normalizedBoard(&actual.Board)
+	if err != nil {
+		return false, err
+	}
+
+	return !containsJSON(actualContent, expectedContent), nil
+}
+
+// normalizedBoard returns the content of the given dashboard, as decoded from
+// Grafana's API, without the fields Grafana manages.
+func normalizedBoard(board *sdk.Board) (map[string]interface{}, error) {
+	buf, err := json.Marshal(board)
+	if err != nil {
+		return nil, err
 	}
 
-	return creator.upsertDashboard(ctx, folderName, dashboardBuilder)
+	decoded := sdk.Board{}
+	if err := json.Unmarshal(buf, &decoded); err != nil {
+		return nil, err
+	}
+
+	buf, err = json.Marshal(decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	content := map[string]interface{}{}
+	if err := json.Unmarshal(buf, &content); err != nil {
+		return nil, err
+	}
+
+	delete(content, "id")
+	delete(content, "uid")
+	delete(content, "version")
+	forgetPanelIDs(content)
+
+	return content, nil
+}
+
+// forgetPanelIDs removes the IDs of the panels held by the given decoded
+// JSON value: grabana numbers panels anew every time it builds a dashboard.
+func forgetPanelIDs(value interface{}) {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		if panels, ok := typedValue["panels"].([]interface{}); ok {
+			for _, panel := range panels {
+				if panelFields, ok := panel.(map[string]interface{}); ok {
+					delete(panelFields, "id")
+				}
+			}
+		}
+
+		for _, field := range typedValue {
+			forgetPanelIDs(field)
+		}
+	case []interface{}:
+		for _, item := range typedValue {
+			forgetPanelIDs(item)
+		}
+	}
+}
+
+// containsJSON tells whether the given decoded JSON value holds all the
+// fields of the expected one, with the same values.
+func containsJSON(actual interface{}, expected interface{}) bool {
+	switch expectedValue := expected.(type) {
+	case map[string]interface{}:
+		actualValue, ok := actual.(map[string]interface{})
+		if !ok {
+			return false
+		}
+
+		for key, value := range expectedValue {
+			if !containsJSON(actualValue[key], value) {
+				return false
+			}
+		}
+
+		return true
+	case []interface{}:
+		actualValue, ok := actual.([]interface{})
+		if !ok || len(actualValue) != len(expectedValue) {
+			return false
+		}
+
+		for i := range expectedValue {
+			if !containsJSON(actualValue[i], expectedValue[i]) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return reflect.DeepEqual(actual, expected)
+	}
 }
 
 func (creator *Creator) Delete(ctx context.Context, uid string) error {
-	err := creator.grabanaClient.DeleteDashboard(ctx, uid)
+	err := creator.grafanaClient.DeleteDashboard(ctx, uid)
 	if err != nil && err != grabana.ErrDashboardNotFound {
 		return err
 	}
@@ -56,15 +196,134 @@ func (creator *Creator) Delete(ctx context.Context, uid string) error {
 	return nil
 }
 
-func (creator *Creator) upsertDashboard(ctx context.Context, folderName string, dashboardBuilder dashboard.Builder) error {
-	folder, err := creator.grabanaClient.FindOrCreateFolder(ctx, folderName)
+// SetPermissions replaces the permissions of the given dashboard.
+func (creator *Creator) SetPermissions(ctx context.Context, uid string, permissions []v1alpha1.Permission) error {
+	resolved, err := creator.grafanaClient.resolvePermissions(ctx, permissions)
 	if err != nil {
 		return err
 	}
 
-	if _, err := creator.grabanaClient.UpsertDashboard(ctx, folder, dashboardBuilder); err != nil {
-		return fmt.Errorf("could not create dashboard: %w", err)
+	return creator.grafanaClient.setPermissions(ctx, "/api/dashboards/uid/"+url.PathEscape(uid)+"/permissions", resolved)
+}
+
+// DeleteFolderIfEmpty deletes the given folder if it doesn't hold any
+// dashboard or folder anymore.
+func (creator *Creator) DeleteFolderIfEmpty(ctx context.Context, uid string) error {
+	_, err := deleteFolderIfEmpty(ctx, creator.grafanaClient, uid)
+
+	return err
+}
+
+func (creator *Creator) upsertDashboard(ctx context.Context, dashboardFolder DashboardFolder, dashboardBuilder dashboard.Builder) (DeployedDashboard, error) {
+	folder, folderCreated, err := creator.grafanaClient.resolveFolder(ctx, dashboardFolder)
+	if err != nil {
+		return DeployedDashboard{}, err
 	}
 
-	return nil
+	upserted, err := creator.grafanaClient.UpsertDashboard(ctx, folder, dashboardBuilder)
+	if err != nil {
+		return DeployedDashboard{}, fmt.Errorf("could not create dashboard: %w", err)
+	}
+
+	// the version isn't part of the upsert response
+	deployed, err := creator.grafanaClient.dashboardByUID(ctx, upserted.UID)
+	if err != nil {
+		return DeployedDashboard{}, fmt.Errorf("could not fetch deployed dashboard: %w", err)
+	}
+
+	return DeployedDashboard{
+		UID:       upserted.UID,
+		URL:       creator.grafanaClient.url(upserted.URL),
+		Version:   deployed.Board.Version,
+		FolderUID: folder.UID,
+
+		FolderCreated: folderCreated,
+	}, nil
+}
+
+// resolveFolder returns the designated folder, creating it if it is
+// designated by its title and doesn't exist yet. It also returns whether the
+// folder was created.
+func (client *Client) resolveFolder(ctx context.Context, folder DashboardFolder) (*grabana.Folder, bool, error) {
+	if folder.UID == "" {
+		existing, err := client.GetFolderByTitle(ctx, folder.Title)
+		if err != nil && !errors.Is(err, grabana.ErrFolderNotFound) {
+			return nil, false, fmt.Errorf("could not find folder %s: %w", folder.Title, err)
+		}
+		if existing != nil {
+			return existing, false, nil
+		}
+
+		created, err := client.CreateFolder(ctx, folder.Title)
+		if err != nil {
+			return nil, false, fmt.Errorf("could not create folder %s: %w", folder.Title, err)
+		}
+
+		return created, true, nil
+	}
+
+	existing, err := client.folderByUID(ctx, folder.UID)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not find folder %s: %w", folder.UID, err)
+	}
+
+	return &grabana.Folder{ID: existing.ID, UID: existing.UID, Title: existing.Title}, false, nil
+}
+
+func builderFromRawSpec(uid string, rawJSON []byte) (dashboard.Builder, error) {
+	spec := make(map[string]interface{})
+	if err := json.Unmarshal(rawJSON, &spec); err != nil {
+		return dashboard.Builder{}, fmt.Errorf("could not unmarshall dashboard json spec: %w", err)
+	}
+
+	dashboardYaml, err := yaml.Marshal(spec)
+	if err != nil {
+		return dashboard.Builder{}, fmt.Errorf("could not convert dashboard spec to yaml: %w", err)
+	}
+
+	dashboardBuilder, err := decoder.UnmarshalYAML(bytes.NewBuffer(dashboardYaml))
+	if err != nil {
+		return dashboard.Builder{}, fmt.Errorf("could not unmarshall dashboard YAML spec: %w", locateSpecError(spec, err))
+	}
+
+	if uid == "" {
+		return dashboardBuilder, nil
+	}
+
+	if err := dashboard.UID(uid)(&dashboardBuilder); err != nil {
+		return dashboard.Builder{}, fmt.Errorf("could not set dashboard UID: %w", err)
+	}
+
+	return dashboardBuilder, nil
+}
+
+type rawDashboard struct {
+	Board sdk.Board `json:"dashboard"`
+	Meta  struct {
+		FolderTitle string `json:"folderTitle"`
+		FolderUID   string `json:"folderUid"`
+	} `json:"meta"`
+}
+
+func (client *Client) dashboardByUID(ctx context.Context, uid string) (*rawDashboard, error) {
+	resp, err := client.get(ctx, "/api/dashboards/uid/"+url.PathEscape(uid))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, grabana.ErrDashboardNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, client.httpError(resp)
+	}
+
+	var response rawDashboard
+	if err := decodeJSON(resp.Body, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
 }
@@ -0,0 +1,172 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testCreator(t *testing.T, handler http.HandlerFunc) *Creator {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(ClientConfig{Host: server.URL})
+	require.NoError(t, err)
+
+	return NewCreator(client)
+}
+
+const testDashboardSpec = `{
+	"title": "API",
+	"tags": ["api"],
+	"variables": [{"interval": {"name": "interval", "values": ["30s", "1m"]}}],
+	"rows": [{
+		"name": "Requests",
+		"panels": [
+			{"text": {"title": "Help", "markdown": "*Hello*"}},
+			{"timeseries": {"title": "Rate", "targets": [{"prometheus": {"query": "rate(http_requests_total[5m])"}}]}}
+		]
+	}]
+}`
+
+// storedDashboard returns the given spec, as stored by Grafana once deployed:
+// with fields DARK doesn't send.
+func storedDashboard(t *testing.T, spec string) map[string]interface{} {
+	t.Helper()
+	req := require.New(t)
+
+	dashboardBuilder, err := builderFromRawSpec("api", []byte(spec))
+	req.NoError(err)
+
+	buf, err := json.Marshal(dashboardBuilder.Internal())
+	req.NoError(err)
+
+	board := map[string]interface{}{}
+	req.NoError(json.Unmarshal(buf, &board))
+
+	board["id"] = 12
+	board["version"] = 7
+	board["fiscalYearStartMonth"] = 0
+	board["weekStart"] = ""
+
+	return board
+}
+
+// deployedDashboard serves the given dashboard, in the given folder.
+func deployedDashboard(t *testing.T, folderUID string, folderTitle string, board map[string]interface{}) http.HandlerFunc {
+	t.Helper()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method+" "+r.URL.Path != "GET /api/dashboards/uid/api" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+
+		writeJSON(t, w, map[string]interface{}{
+			"dashboard": board,
+			"meta": map[string]interface{}{
+				"folderUid":   folderUID,
+				"folderTitle": folderTitle,
+			},
+		})
+	}
+}
+
+func TestDashboardsInSyncAreNotDrifted(t *testing.T) {
+	req := require.New(t)
+
+	creator := testCreator(t, deployedDashboard(t, "platform-uid", "Platform", storedDashboard(t, testDashboardSpec)))
+
+	drifted, err := creator.Drifted(context.Background(), DashboardFolder{Title: "platform"}, "api", []byte(testDashboardSpec))
+	req.NoError(err)
+	req.False(drifted)
+
+	drifted, err = creator.Drifted(context.Background(), DashboardFolder{UID: "platform-uid"}, "api", []byte(testDashboardSpec))
+	req.NoError(err)
+	req.False(drifted)
+}
+
+func TestDashboardsEditedInGrafanaAreDrifted(t *testing.T) {
+	req := require.New(t)
+
+	edited := storedDashboard(t, testDashboardSpec)
+	edited["title"] = "API (edited)"
+
+	creator := testCreator(t, deployedDashboard(t, "platform-uid", "Platform", edited))
+
+	drifted, err := creator.Drifted(context.Background(), DashboardFolder{Title: "Platform"}, "api", []byte(testDashboardSpec))
+	req.NoError(err)
+	req.True(drifted)
+}
+
+func TestDashboardsWithRemovedPanelsAreDrifted(t *testing.T) {
+	req := require.New(t)
+
+	edited := storedDashboard(t, `{"title": "API", "tags": ["api"], "rows": [{"name": "Requests"}]}`)
+
+	creator := testCreator(t, deployedDashboard(t, "platform-uid", "Platform", edited))
+
+	drifted, err := creator.Drifted(context.Background(), DashboardFolder{Title: "Platform"}, "api", []byte(testDashboardSpec))
+	req.NoError(err)
+	req.True(drifted)
+}
+
+func TestMissingDashboardsAreDrifted(t *testing.T) {
+	req := require.New(t)
+
+	creator := testCreator(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	drifted, err := creator.Drifted(context.Background(), DashboardFolder{Title: "Platform"}, "api", []byte(testDashboardSpec))
+	req.NoError(err)
+	req.True(drifted)
+}
+
+func TestDashboardsInAnotherFolderAreDrifted(t *testing.T) {
+	req := require.New(t)
+
+	creator := testCreator(t, deployedDashboard(t, "backend-uid", "Backend", storedDashboard(t, testDashboardSpec)))
+
+	drifted, err := creator.Drifted(context.Background(), DashboardFolder{Title: "Platform"}, "api", []byte(testDashboardSpec))
+	req.NoError(err)
+	req.True(drifted)
+
+	drifted, err = creator.Drifted(context.Background(), DashboardFolder{UID: "platform-uid"}, "api", []byte(testDashboardSpec))
+	req.NoError(err)
+	req.True(drifted)
+}
+
+func TestFromRawSpecReportsCreatedFolders(t *testing.T) {
+	req := require.New(t)
+
+	creator := testCreator(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/folders":
+			writeJSON(t, w, []interface{}{})
+		case "POST /api/folders":
+			writeJSON(t, w, map[string]interface{}{"id": 2, "uid": "platform-uid", "title": "Platform"})
+		case "GET /api/ruler/grafana/api/v1/rules":
+			writeJSON(t, w, map[string]interface{}{})
+		case "POST /api/dashboards/db":
+			writeJSON(t, w, map[string]interface{}{"id": 12, "uid": "api", "url": "/d/api/api", "version": 1})
+		case "GET /api/dashboards/uid/api":
+			writeJSON(t, w, map[string]interface{}{"dashboard": map[string]interface{}{"uid": "api", "version": 1}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	deployed, err := creator.FromRawSpec(context.Background(), DashboardFolder{Title: "Platform"}, "api", []byte(`{"title": "API"}`))
+	req.NoError(err)
+
+	req.Equal("api", deployed.UID)
+	req.Equal(uint(1), deployed.Version)
+	req.Equal("platform-uid", deployed.FolderUID)
+	req.True(deployed.FolderCreated)
+}
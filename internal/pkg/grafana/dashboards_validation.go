@@ -0,0 +1,173 @@
+package grafana
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/K-Phoen/grabana/decoder"
+	"gopkg.in/yaml.v3"
+)
+
+// SpecError describes an invalid dashboard spec, and points at the faulty
+// part of it.
+type SpecError struct {
+	// Path to the invalid part of the spec. Ex: rows[2].panels[0].timeseries.targets[1]
+	// An empty path means that the error couldn't be narrowed down.
+	Path string
+	Err  error
+}
+
+func (err *SpecError) Error() string {
+	if err.Path == "" {
+		return err.Err.Error()
+	}
+
+	return fmt.Sprintf("%s: %s", err.Path, err.Err)
+}
+
+func (err *SpecError) Unwrap() error {
+	return err.Err
+}
+
+// ValidateRawSpec checks that the given JSON spec describes a valid dashboard.
+// It runs the same pipeline as Creator.FromRawSpec, without talking to Grafana.
+func ValidateRawSpec(rawJSON []byte) error {
+	_, err := builderFromRawSpec("", rawJSON)
+
+	return err
+}
+
+// locateSpecError narrows down the part of the spec responsible for a
+// decoding error by decoding smaller and smaller versions of it: first without
+// any row nor variable, then one row, panel and target at a time.
+func locateSpecError(spec map[string]interface{}, err error) *SpecError {
+	base := withoutKeys(spec, "rows", "variables")
+	if baseErr := decodeSpec(base); baseErr != nil {
+		return &SpecError{Err: baseErr}
+	}
+
+	for i, variable := range listAt(spec, "variables") {
+		if varErr := decodeSpec(withKey(base, "variables", []interface{}{variable})); varErr != nil {
+			return &SpecError{Path: fmt.Sprintf("variables[%d]", i), Err: varErr}
+		}
+	}
+
+	for i, row := range listAt(spec, "rows") {
+		rowPath := fmt.Sprintf("rows[%d]", i)
+		rowSpec, ok := row.(map[string]interface{})
+		if !ok {
+			if rowErr := decodeSpec(withKey(base, "rows", []interface{}{row})); rowErr != nil {
+				return &SpecError{Path: rowPath, Err: rowErr}
+			}
+			continue
+		}
+
+		if rowErr := decodeRow(base, rowSpec); rowErr != nil {
+			return locateRowError(base, rowSpec, rowPath, rowErr)
+		}
+	}
+
+	return &SpecError{Err: err}
+}
+
+func locateRowError(base map[string]interface{}, rowSpec map[string]interface{}, rowPath string, err error) *SpecError {
+	rowBase := withoutKeys(rowSpec, "panels")
+	if baseErr := decodeRow(base, rowBase); baseErr != nil {
+		return &SpecError{Path: rowPath, Err: baseErr}
+	}
+
+	for i, panel := range listAt(rowSpec, "panels") {
+		panelPath := fmt.Sprintf("%s.panels[%d]", rowPath, i)
+		decodePanel := func(panel interface{}) error {
+			return decodeRow(base, withKey(rowBase, "panels", []interface{}{panel}))
+		}
+
+		panelErr := decodePanel(panel)
+		if panelErr == nil {
+			continue
+		}
+
+		// panels are described as a single "type: spec" entry
+		panelSpec, ok := panel.(map[string]interface{})
+		if !ok || len(panelSpec) != 1 {
+			return &SpecError{Path: panelPath, Err: panelErr}
+		}
+
+		panelType := sortedKeys(panelSpec)[0]
+		panelPath = panelPath + "." + panelType
+
+		typeSpec, ok := panelSpec[panelType].(map[string]interface{})
+		if !ok {
+			return &SpecError{Path: panelPath, Err: panelErr}
+		}
+
+		typeBase := withoutKeys(typeSpec, "targets")
+		if baseErr := decodePanel(map[string]interface{}{panelType: typeBase}); baseErr != nil {
+			return &SpecError{Path: panelPath, Err: baseErr}
+		}
+
+		for j, target := range listAt(typeSpec, "targets") {
+			targetErr := decodePanel(map[string]interface{}{panelType: withKey(typeBase, "targets", []interface{}{target})})
+			if targetErr != nil {
+				return &SpecError{Path: fmt.Sprintf("%s.targets[%d]", panelPath, j), Err: targetErr}
+			}
+		}
+
+		return &SpecError{Path: panelPath, Err: panelErr}
+	}
+
+	return &SpecError{Path: rowPath, Err: err}
+}
+
+func decodeRow(base map[string]interface{}, row map[string]interface{}) error {
+	return decodeSpec(withKey(base, "rows", []interface{}{row}))
+}
+
+func decodeSpec(spec map[string]interface{}) error {
+	dashboardYaml, err := yaml.Marshal(spec)
+	if err != nil {
+		return err
+	}
+
+	_, err = decoder.UnmarshalYAML(bytes.NewBuffer(dashboardYaml))
+
+	return err
+}
+
+func listAt(spec map[string]interface{}, key string) []interface{} {
+	list, _ := spec[key].([]interface{})
+
+	return list
+}
+
+func withKey(spec map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	result := withoutKeys(spec)
+	result[key] = value
+
+	return result
+}
+
+func withoutKeys(spec map[string]interface{}, keys ...string) map[string]interface{} {
+	result := make(map[string]interface{}, len(spec))
+	for key, value := range spec {
+		result[key] = value
+	}
+
+	for _, key := range keys {
+		delete(result, key)
+	}
+
+	return result
+}
+
+func sortedKeys(spec map[string]interface{}) []string {
+	keys := make([]string, 0, len(spec))
+	for key := range spec {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
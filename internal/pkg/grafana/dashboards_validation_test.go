@@ -0,0 +1,93 @@
+package grafana
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/K-Phoen/grabana/decoder"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRawSpecAcceptsValidDashboards(t *testing.T) {
+	req := require.New(t)
+
+	spec := `{
+  "title": "Awesome dashboard",
+  "rows": [
+    {"name": "Prometheus", "panels": [
+      {"timeseries": {"title": "HTTP Rate", "targets": [{"prometheus": {"query": "rate(http_requests_total[5m])"}}]}}
+    ]}
+  ]
+}`
+
+	req.NoError(ValidateRawSpec([]byte(spec)))
+}
+
+func TestValidateRawSpecLocatesInvalidTargets(t *testing.T) {
+	req := require.New(t)
+
+	spec := `{
+  "title": "Awesome dashboard",
+  "rows": [
+    {"name": "First", "panels": [{"text": {"title": "Hello", "markdown": "world"}}]},
+    {"name": "Second", "panels": [
+      {"text": {"title": "Hello", "markdown": "world"}},
+      {"timeseries": {"title": "HTTP Rate", "targets": [
+        {"prometheus": {"query": "rate(http_requests_total[5m])"}},
+        {}
+      ]}}
+    ]}
+  ]
+}`
+
+	err := ValidateRawSpec([]byte(spec))
+
+	var specErr *SpecError
+	req.ErrorAs(err, &specErr)
+	req.Equal("rows[1].panels[1].timeseries.targets[1]", specErr.Path)
+	req.True(errors.Is(err, decoder.ErrTargetNotConfigured))
+}
+
+func TestValidateRawSpecLocatesUnknownFields(t *testing.T) {
+	req := require.New(t)
+
+	spec := `{
+  "title": "Awesome dashboard",
+  "rows": [
+    {"name": "First", "panels": [{"text": {"title": "Hello", "markdown": "world", "unknown": "field"}}]}
+  ]
+}`
+
+	err := ValidateRawSpec([]byte(spec))
+
+	var specErr *SpecError
+	req.ErrorAs(err, &specErr)
+	req.Equal("rows[0].panels[0].text", specErr.Path)
+}
+
+func TestValidateRawSpecLocatesInvalidVariables(t *testing.T) {
+	req := require.New(t)
+
+	spec := `{
+  "title": "Awesome dashboard",
+  "variables": [{"interval": {"name": "interval", "label": "Interval", "values": ["30s", "1m"]}}, {}]
+}`
+
+	err := ValidateRawSpec([]byte(spec))
+
+	var specErr *SpecError
+	req.ErrorAs(err, &specErr)
+	req.Equal("variables[1]", specErr.Path)
+	req.True(errors.Is(err, decoder.ErrVariableNotConfigured))
+}
+
+func TestValidateRawSpecReportsTopLevelErrors(t *testing.T) {
+	req := require.New(t)
+
+	err := ValidateRawSpec([]byte(`{"title": "Awesome dashboard", "timezone": "mars"}`))
+
+	var specErr *SpecError
+	req.ErrorAs(err, &specErr)
+	req.Empty(specErr.Path)
+	req.True(errors.Is(err, decoder.ErrInvalidTimezone))
+}
@@ -2,38 +2,119 @@ package grafana
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/K-Phoen/dark/api/v1alpha1"
-	"github.com/K-Phoen/grabana"
 	"github.com/K-Phoen/grabana/datasource"
 	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// maxUIDLength is the maximum length of UIDs accepted by Grafana.
+const maxUIDLength = 40
+
+var ErrDatasourceNotFound = fmt.Errorf("datasource not found")
 var ErrDatasourceNotConfigured = fmt.Errorf("datasource not configured")
 var ErrInvalidDatasourceRef = fmt.Errorf("invalid datasource reference")
 var ErrInvalidAccessMode = fmt.Errorf("invalid access mode")
+var ErrDatasourceNotManaged = fmt.Errorf("datasource already exists and is not managed by DARK")
 
 type refReader interface {
 	RefToValue(ctx context.Context, namespace string, ref v1alpha1.ValueOrRef) (string, error)
 }
 
+// Datasource is a datasource model, along with the identity under which it is
+// stored in Grafana.
+type Datasource struct {
+	Model datasource.Datasource
+
+	UID  string
+	Name string
+	// UID under which the datasource was previously stored, if any.
+	PreviousUID string
+	// Whether the datasource was synchronized by a version of DARK that
+	// didn't assign UIDs: it can then be found by name.
+	SynchronizedWithoutUID bool
+}
+
+func (ds Datasource) MarshalJSON() ([]byte, error) {
+	buf, err := json.Marshal(ds.Model)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := map[string]interface{}{}
+	if err := json.Unmarshal(buf, &payload); err != nil {
+		return nil, err
+	}
+
+	payload["uid"] = ds.UID
+	payload["name"] = ds.Name
+
+	return json.Marshal(payload)
+}
+
+// DatasourceUID returns the UID under which the datasource described by the
+// given manifest is stored in Grafana.
+func DatasourceUID(objectRef types.NamespacedName, spec v1alpha1.DatasourceSpec) string {
+	if spec.UID != "" {
+		return spec.UID
+	}
+
+	// derived from the namespace to keep datasources from different namespaces apart
+	sum := sha256.Sum256([]byte(objectRef.Namespace + "/" + objectRef.Name))
+
+	return hex.EncodeToString(sum[:])[:maxUIDLength]
+}
+
+// DatasourceDisplayName returns the name of the datasource described by the
+// given manifest in Grafana.
+func DatasourceDisplayName(objectRef types.NamespacedName, spec v1alpha1.DatasourceSpec) string {
+	if spec.DisplayName != "" {
+		return spec.DisplayName
+	}
+
+	return objectRef.Name
+}
+
 type Datasources struct {
 	logger        logr.Logger
-	grabanaClient *grabana.Client
+	grafanaClient *Client
 	refReader     refReader
+	k8sClient     client.Reader
 }
 
-func NewDatasources(logger logr.Logger, grabanaClient *grabana.Client, refReader refReader) *Datasources {
+func NewDatasources(logger logr.Logger, grafanaClient *Client, refReader refReader, k8sClient client.Reader) *Datasources {
 	return &Datasources{
 		logger:        logger,
-		grabanaClient: grabanaClient,
+		grafanaClient: grafanaClient,
 		refReader:     refReader,
+		k8sClient:     k8sClient,
 	}
 }
 
-func (datasources *Datasources) SpecToModel(ctx context.Context, objectRef types.NamespacedName, spec v1alpha1.DatasourceSpec) (datasource.Datasource, error) {
+func (datasources *Datasources) SpecToModel(ctx context.Context, objectRef types.NamespacedName, spec v1alpha1.DatasourceSpec) (Datasource, error) {
+	model, err := datasources.specToModel(ctx, objectRef, spec)
+	if err != nil {
+		return Datasource{}, err
+	}
+
+	return Datasource{
+		Model: model,
+		UID:   DatasourceUID(objectRef, spec),
+		Name:  DatasourceDisplayName(objectRef, spec),
+	}, nil
+}
+
+func (datasources *Datasources) specToModel(ctx context.Context, objectRef types.NamespacedName, spec v1alpha1.DatasourceSpec) (datasource.Datasource, error) {
 	if spec.Prometheus != nil {
 		return datasources.prometheusSpecToModel(ctx, objectRef, spec.Prometheus)
 	}
@@ -52,24 +133,76 @@ func (datasources *Datasources) SpecToModel(ctx context.Context, objectRef types
 	if spec.CloudWatch != nil {
 		return datasources.cloudWatchSpecToModel(ctx, objectRef, spec.CloudWatch)
 	}
+	if spec.InfluxDB != nil {
+		return datasources.influxDBSpecToModel(ctx, objectRef, spec.InfluxDB)
+	}
+	if spec.Graphite != nil {
+		return datasources.graphiteSpecToModel(ctx, objectRef, spec.Graphite)
+	}
+	if spec.Elasticsearch != nil {
+		return datasources.elasticsearchSpecToModel(ctx, objectRef, spec.Elasticsearch)
+	}
+	if spec.PostgreSQL != nil {
+		return datasources.postgreSQLSpecToModel(ctx, objectRef, spec.PostgreSQL)
+	}
+	if spec.MySQL != nil {
+		return datasources.mySQLSpecToModel(ctx, objectRef, spec.MySQL)
+	}
+	if spec.Generic != nil {
+		return datasources.genericSpecToModel(ctx, objectRef, spec.Generic)
+	}
 
 	return nil, ErrDatasourceNotConfigured
 }
 
-func (datasources *Datasources) Upsert(ctx context.Context, model datasource.Datasource) error {
-	datasources.logger.Info("upserting datasource", "name", model.Name())
-	return datasources.grabanaClient.UpsertDatasource(ctx, model)
+// Upsert creates or updates a datasource, identified by its UID.
+// A datasource previously stored under another UID is updated in place. So is
+// a datasource with the same name if it was created by an earlier version of
+// DARK, that didn't assign UIDs. Any other datasource with the same name is
+// left alone and reported with ErrDatasourceNotManaged.
+func (datasources *Datasources) Upsert(ctx context.Context, ds Datasource) error {
+	datasources.logger.Info("upserting datasource", "uid", ds.UID, "name", ds.Name)
+
+	existing, err := datasources.grafanaClient.datasourceByUID(ctx, ds.UID)
+	if err == ErrDatasourceNotFound && ds.PreviousUID != "" && ds.PreviousUID != ds.UID {
+		existing, err = datasources.grafanaClient.datasourceByUID(ctx, ds.PreviousUID)
+	}
+	if err == ErrDatasourceNotFound && ds.PreviousUID == "" {
+		existing, err = datasources.grafanaClient.datasourceByName(ctx, ds.Name)
+		if err == nil && !ds.SynchronizedWithoutUID {
+			return fmt.Errorf("%s: %w", ds.Name, ErrDatasourceNotManaged)
+		}
+	}
+	if err == ErrDatasourceNotFound {
+		return datasources.grafanaClient.createDatasource(ctx, ds)
+	}
+	if err != nil {
+		return err
+	}
+
+	return datasources.grafanaClient.updateDatasource(ctx, existing.ID, ds)
+}
+
+// Delete deletes the datasource with the given UID.
+func (datasources *Datasources) Delete(ctx context.Context, uid string) error {
+	datasources.logger.Info("deleting datasource", "uid", uid)
+
+	return datasources.grafanaClient.deleteDatasource(ctx, uid)
 }
 
-func (datasources *Datasources) Delete(ctx context.Context, name string) error {
+// DeleteByName deletes the datasource with the given name, if any.
+func (datasources *Datasources) DeleteByName(ctx context.Context, name string) error {
 	datasources.logger.Info("deleting datasource", "name", name)
 
-	err := datasources.grabanaClient.DeleteDatasource(ctx, name)
-	if err == grabana.ErrDatasourceNotFound {
+	existing, err := datasources.grafanaClient.datasourceByName(ctx, name)
+	if err == ErrDatasourceNotFound {
 		return nil
 	}
+	if err != nil {
+		return err
+	}
 
-	return err
+	return datasources.grafanaClient.deleteDatasource(ctx, existing.UID)
 }
 
 func (datasources *Datasources) basicAuthCredentials(ctx context.Context, namespace string, auth *v1alpha1.BasicAuth) (string, string, error) {
@@ -85,14 +218,120 @@ func (datasources *Datasources) basicAuthCredentials(ctx context.Context, namesp
 	return username, password, nil
 }
 
-func (datasources *Datasources) datasourceUIDFromRef(ctx context.Context, ref *v1alpha1.ValueOrDatasourceRef) (string, error) {
+func (datasources *Datasources) url(ctx context.Context, namespace string, url v1alpha1.StringOrRef) (string, error) {
+	value, err := datasources.refReader.RefToValue(ctx, namespace, url.ValueOrRef)
+	if err != nil {
+		return "", fmt.Errorf("could not extract URL: %w", err)
+	}
+
+	return value, nil
+}
+
+// datasourceUIDFromRef resolves a reference to a datasource into its UID.
+func (datasources *Datasources) datasourceUIDFromRef(ctx context.Context, namespace string, ref *v1alpha1.ValueOrDatasourceRef) (string, error) {
+	return resolveDatasourceUID(ctx, datasources.k8sClient, datasources.grafanaClient, namespace, *ref)
+}
+
+// resolveDatasourceUID resolves a reference to a datasource into its UID.
+// Names designate Datasource manifests living in the given namespace, or
+// datasources not managed by DARK when no such manifest exists.
+func resolveDatasourceUID(ctx context.Context, k8sClient client.Reader, grafanaClient *Client, namespace string, ref v1alpha1.ValueOrDatasourceRef) (string, error) {
 	if ref.UID != "" {
 		return ref.UID, nil
 	}
 
-	if ref.Name != "" {
-		return datasources.grabanaClient.GetDatasourceUIDByName(ctx, ref.Name)
+	if ref.Name == "" {
+		return "", ErrInvalidDatasourceRef
+	}
+
+	manifest := &v1alpha1.Datasource{}
+	err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, manifest)
+	if err == nil {
+		return DatasourceUID(types.NamespacedName{Namespace: namespace, Name: ref.Name}, manifest.Spec), nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return "", err
+	}
+
+	existing, err := grafanaClient.datasourceByName(ctx, ref.Name)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", ref.Name, err)
+	}
+
+	return existing.UID, nil
+}
+
+type grafanaDatasource struct {
+	ID   int64  `json:"id"`
+	UID  string `json:"uid"`
+	Name string `json:"name"`
+}
+
+func (client *Client) datasourceByUID(ctx context.Context, uid string) (*grafanaDatasource, error) {
+	return client.findDatasource(ctx, "/api/datasources/uid/"+url.PathEscape(uid))
+}
+
+func (client *Client) datasourceByName(ctx context.Context, name string) (*grafanaDatasource, error) {
+	return client.findDatasource(ctx, "/api/datasources/name/"+url.PathEscape(name))
+}
+
+func (client *Client) findDatasource(ctx context.Context, path string) (*grafanaDatasource, error) {
+	resp, err := client.get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrDatasourceNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, client.httpError(resp)
+	}
+
+	var ds grafanaDatasource
+	if err := decodeJSON(resp.Body, &ds); err != nil {
+		return nil, err
+	}
+
+	return &ds, nil
+}
+
+func (client *Client) createDatasource(ctx context.Context, ds Datasource) error {
+	return client.saveDatasource(ctx, http.MethodPost, "/api/datasources", ds)
+}
+
+func (client *Client) updateDatasource(ctx context.Context, id int64, ds Datasource) error {
+	return client.saveDatasource(ctx, http.MethodPut, "/api/datasources/"+strconv.FormatInt(id, 10), ds)
+}
+
+func (client *Client) saveDatasource(ctx context.Context, method string, path string, ds Datasource) error {
+	resp, err := client.sendJSON(ctx, method, path, ds)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return client.httpError(resp)
+	}
+
+	return nil
+}
+
+func (client *Client) deleteDatasource(ctx context.Context, uid string) error {
+	resp, err := client.delete(ctx, "/api/datasources/uid/"+url.PathEscape(uid))
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return client.httpError(resp)
 	}
 
-	return "", ErrInvalidDatasourceRef
+	return nil
 }
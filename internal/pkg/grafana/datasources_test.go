@@ -0,0 +1,378 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type valuesOnlyRefReader struct{}
+
+func (valuesOnlyRefReader) RefToValue(_ context.Context, _ string, ref v1alpha1.ValueOrRef) (string, error) {
+	return ref.Value, nil
+}
+
+func specToJSON(t *testing.T, spec v1alpha1.DatasourceSpec) map[string]interface{} {
+	t.Helper()
+	req := require.New(t)
+
+	datasources := NewDatasources(logr.Discard(), nil, valuesOnlyRefReader{}, nil)
+
+	model, err := datasources.SpecToModel(context.Background(), types.NamespacedName{Namespace: "default", Name: "ds"}, spec)
+	req.NoError(err)
+
+	payload, err := json.Marshal(model)
+	req.NoError(err)
+
+	decoded := map[string]interface{}{}
+	req.NoError(json.Unmarshal(payload, &decoded))
+
+	return decoded
+}
+
+func TestInfluxDBDatasourceWithInfluxQL(t *testing.T) {
+	req := require.New(t)
+
+	payload := specToJSON(t, v1alpha1.DatasourceSpec{
+		InfluxDB: &v1alpha1.InfluxDBDatasource{
+			URL:             v1alpha1.NewStringOrRef("http://influxdb:8086"),
+			Database:        "metrics",
+			User:            &v1alpha1.ValueOrRef{Value: "grafana"},
+			Password:        &v1alpha1.ValueOrRef{Value: "s3cr3t"},
+			HTTPMethod:      "POST",
+			MinTimeInterval: "10s",
+			Timeout:         "1m",
+			BasicAuth: &v1alpha1.BasicAuth{
+				Username: v1alpha1.ValueOrRef{Value: "proxy"},
+				Password: v1alpha1.ValueOrRef{Value: "proxy-s3cr3t"},
+			},
+		},
+	})
+
+	req.Equal("influxdb", payload["type"])
+	req.Equal("ds", payload["name"])
+	req.Equal("http://influxdb:8086", payload["url"])
+	req.Equal("metrics", payload["database"])
+	req.Equal("grafana", payload["user"])
+	req.Equal(true, payload["basicAuth"])
+	req.Equal("proxy", payload["basicAuthUser"])
+	req.Equal(map[string]interface{}{
+		"version":      "InfluxQL",
+		"httpMethod":   "POST",
+		"httpMode":     "POST",
+		"dbName":       "metrics",
+		"maxSeries":    float64(1000),
+		"timeInterval": "10s",
+		"timeout":      float64(60),
+	}, payload["jsonData"])
+	req.Equal(map[string]interface{}{"password": "s3cr3t"}, payload["secureJsonData"])
+}
+
+func TestInfluxDBDatasourceWithFlux(t *testing.T) {
+	req := require.New(t)
+
+	payload := specToJSON(t, v1alpha1.DatasourceSpec{
+		InfluxDB: &v1alpha1.InfluxDBDatasource{
+			URL:           v1alpha1.NewStringOrRef("http://influxdb:8086"),
+			QueryLanguage: Flux,
+			Organization:  "acme",
+			DefaultBucket: "metrics",
+			Token:         &v1alpha1.ValueOrRef{Value: "s3cr3t"},
+			Timeout:       "1m",
+		},
+	})
+
+	req.Equal("influxdb", payload["type"])
+	req.Equal("ds", payload["name"])
+	req.Equal("http://influxdb:8086", payload["url"])
+	req.Equal(map[string]interface{}{
+		"version":       "Flux",
+		"organization":  "acme",
+		"defaultBucket": "metrics",
+		"timeout":       float64(60),
+	}, payload["jsonData"])
+	req.Equal(map[string]interface{}{"token": "s3cr3t"}, payload["secureJsonData"])
+}
+
+func TestPostgreSQLDatasource(t *testing.T) {
+	req := require.New(t)
+
+	version := 1200
+	payload := specToJSON(t, v1alpha1.DatasourceSpec{
+		PostgreSQL: &v1alpha1.PostgreSQLDatasource{
+			URL:      v1alpha1.NewStringOrRef("postgres:5432"),
+			Database: "app",
+			User:     v1alpha1.ValueOrRef{Value: "grafana"},
+			Password: v1alpha1.ValueOrRef{Value: "s3cr3t"},
+			Version:  &version,
+		},
+	})
+
+	req.Equal("postgres", payload["type"])
+	req.Equal("app", payload["database"])
+	req.Equal("grafana", payload["user"])
+	req.Equal(map[string]interface{}{
+		"database":        "app",
+		"sslmode":         "require",
+		"postgresVersion": float64(1200),
+	}, payload["jsonData"])
+	req.Equal(map[string]interface{}{"password": "s3cr3t"}, payload["secureJsonData"])
+}
+
+func TestElasticsearchDatasourceWithOpenSearchFlavor(t *testing.T) {
+	req := require.New(t)
+
+	payload := specToJSON(t, v1alpha1.DatasourceSpec{
+		Elasticsearch: &v1alpha1.ElasticsearchDatasource{
+			URL:     v1alpha1.NewStringOrRef("http://opensearch:9200"),
+			Flavor:  OpenSearchFlavor,
+			Version: "2.11.0",
+			Index:   "logs-*",
+		},
+	})
+
+	req.Equal("grafana-opensearch-datasource", payload["type"])
+	req.Equal("logs-*", payload["database"])
+
+	jsonData := payload["jsonData"].(map[string]interface{})
+	req.Equal("opensearch", jsonData["flavor"])
+	req.Equal("2.11.0", jsonData["version"])
+	req.Equal("@timestamp", jsonData["timeField"])
+}
+
+func TestGenericDatasourcePassesSettingsThrough(t *testing.T) {
+	req := require.New(t)
+
+	url := v1alpha1.NewStringOrRef("clickhouse:9000")
+	payload := specToJSON(t, v1alpha1.DatasourceSpec{
+		Generic: &v1alpha1.GenericDatasource{
+			Type:     "grafana-clickhouse-datasource",
+			URL:      &url,
+			JSONData: &runtime.RawExtension{Raw: []byte(`{"port": 9000, "protocol": "native", "username": "grafana"}`)},
+			SecureJSONData: map[string]v1alpha1.ValueOrRef{
+				"password": {Value: "s3cr3t"},
+			},
+		},
+	})
+
+	req.Equal("grafana-clickhouse-datasource", payload["type"])
+	req.Equal("proxy", payload["access"])
+	req.Equal("clickhouse:9000", payload["url"])
+	req.Equal(map[string]interface{}{
+		"port":     float64(9000),
+		"protocol": "native",
+		"username": "grafana",
+	}, payload["jsonData"])
+	req.Equal(map[string]interface{}{"password": "s3cr3t"}, payload["secureJsonData"])
+}
+
+func TestDefaultDatasourceUIDsDependOnTheNamespace(t *testing.T) {
+	req := require.New(t)
+
+	uidA := DatasourceUID(types.NamespacedName{Namespace: "team-a", Name: "prometheus"}, v1alpha1.DatasourceSpec{})
+	uidB := DatasourceUID(types.NamespacedName{Namespace: "team-b", Name: "prometheus"}, v1alpha1.DatasourceSpec{})
+
+	req.Len(uidA, maxUIDLength)
+	req.NotEqual(uidA, uidB)
+	req.Equal(uidA, DatasourceUID(types.NamespacedName{Namespace: "team-a", Name: "prometheus"}, v1alpha1.DatasourceSpec{}))
+	req.Equal("custom", DatasourceUID(types.NamespacedName{Namespace: "team-a", Name: "prometheus"}, v1alpha1.DatasourceSpec{UID: "custom"}))
+}
+
+func TestUpsertUpdatesDatasourcesPreviouslyStoredUnderAnotherUID(t *testing.T) {
+	req := require.New(t)
+
+	var updated map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/datasources/uid/new-uid":
+			w.WriteHeader(http.StatusNotFound)
+		case "GET /api/datasources/uid/old-uid":
+			writeJSON(t, w, grafanaDatasource{ID: 4, UID: "old-uid", Name: "prometheus"})
+		case "PUT /api/datasources/4":
+			req.NoError(json.NewDecoder(r.Body).Decode(&updated))
+			writeJSON(t, w, map[string]interface{}{})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{Host: server.URL})
+	req.NoError(err)
+
+	datasources := NewDatasources(logr.Discard(), client, valuesOnlyRefReader{}, nil)
+
+	model, err := datasources.SpecToModel(context.Background(), types.NamespacedName{Namespace: "default", Name: "prometheus"}, v1alpha1.DatasourceSpec{
+		UID:         "new-uid",
+		DisplayName: "Prometheus",
+		Prometheus: &v1alpha1.PrometheusDatasource{
+			URL: v1alpha1.NewStringOrRef("http://prometheus:9090"),
+		},
+	})
+	req.NoError(err)
+	model.PreviousUID = "old-uid"
+
+	req.NoError(datasources.Upsert(context.Background(), model))
+	req.Equal("new-uid", updated["uid"])
+	req.Equal("Prometheus", updated["name"])
+	req.Equal("prometheus", updated["type"])
+}
+
+func TestUpsertCreatesDatasourcesWithTheirUID(t *testing.T) {
+	req := require.New(t)
+
+	var created map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/datasources/uid/uid", "GET /api/datasources/name/graphite":
+			w.WriteHeader(http.StatusNotFound)
+		case "POST /api/datasources":
+			req.NoError(json.NewDecoder(r.Body).Decode(&created))
+			writeJSON(t, w, map[string]interface{}{})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{Host: server.URL})
+	req.NoError(err)
+
+	datasources := NewDatasources(logr.Discard(), client, valuesOnlyRefReader{}, nil)
+
+	model, err := datasources.SpecToModel(context.Background(), types.NamespacedName{Namespace: "default", Name: "graphite"}, v1alpha1.DatasourceSpec{
+		UID: "uid",
+		Graphite: &v1alpha1.GraphiteDatasource{
+			URL: v1alpha1.NewStringOrRef("http://graphite"),
+		},
+	})
+	req.NoError(err)
+
+	req.NoError(datasources.Upsert(context.Background(), model))
+	req.Equal("uid", created["uid"])
+	req.Equal("graphite", created["name"])
+}
+
+func TestUpsertTakesOverDatasourcesCreatedWithoutUIDByName(t *testing.T) {
+	req := require.New(t)
+
+	var updated map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/datasources/uid/uid":
+			w.WriteHeader(http.StatusNotFound)
+		case "GET /api/datasources/name/graphite":
+			writeJSON(t, w, grafanaDatasource{ID: 7, UID: "Xyz12ab", Name: "graphite"})
+		case "PUT /api/datasources/7":
+			req.NoError(json.NewDecoder(r.Body).Decode(&updated))
+			writeJSON(t, w, map[string]interface{}{})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{Host: server.URL})
+	req.NoError(err)
+
+	datasources := NewDatasources(logr.Discard(), client, valuesOnlyRefReader{}, nil)
+
+	model, err := datasources.SpecToModel(context.Background(), types.NamespacedName{Namespace: "default", Name: "graphite"}, v1alpha1.DatasourceSpec{
+		UID: "uid",
+		Graphite: &v1alpha1.GraphiteDatasource{
+			URL: v1alpha1.NewStringOrRef("http://graphite"),
+		},
+	})
+	req.NoError(err)
+	model.SynchronizedWithoutUID = true
+
+	req.NoError(datasources.Upsert(context.Background(), model))
+	req.Equal("uid", updated["uid"])
+	req.Equal("graphite", updated["name"])
+}
+
+func TestUpsertLeavesDatasourcesNotManagedByDARKAlone(t *testing.T) {
+	req := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/datasources/uid/uid":
+			w.WriteHeader(http.StatusNotFound)
+		case "GET /api/datasources/name/graphite":
+			writeJSON(t, w, grafanaDatasource{ID: 7, UID: "Xyz12ab", Name: "graphite"})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{Host: server.URL})
+	req.NoError(err)
+
+	datasources := NewDatasources(logr.Discard(), client, valuesOnlyRefReader{}, nil)
+
+	model, err := datasources.SpecToModel(context.Background(), types.NamespacedName{Namespace: "default", Name: "graphite"}, v1alpha1.DatasourceSpec{
+		UID: "uid",
+		Graphite: &v1alpha1.GraphiteDatasource{
+			URL: v1alpha1.NewStringOrRef("http://graphite"),
+		},
+	})
+	req.NoError(err)
+
+	err = datasources.Upsert(context.Background(), model)
+	req.ErrorIs(err, ErrDatasourceNotManaged)
+}
+
+func TestDeleteByNameDeletesTheDatasourceWithThatName(t *testing.T) {
+	req := require.New(t)
+
+	deleted := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/datasources/name/graphite":
+			writeJSON(t, w, grafanaDatasource{ID: 7, UID: "Xyz12ab", Name: "graphite"})
+		case "DELETE /api/datasources/uid/Xyz12ab":
+			deleted = true
+			writeJSON(t, w, map[string]interface{}{})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{Host: server.URL})
+	req.NoError(err)
+
+	datasources := NewDatasources(logr.Discard(), client, valuesOnlyRefReader{}, nil)
+
+	req.NoError(datasources.DeleteByName(context.Background(), "graphite"))
+	req.True(deleted)
+}
+
+func TestDeleteByNameIgnoresMissingDatasources(t *testing.T) {
+	req := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method+" "+r.URL.Path != "GET /api/datasources/name/graphite" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{Host: server.URL})
+	req.NoError(err)
+
+	datasources := NewDatasources(logr.Discard(), client, valuesOnlyRefReader{}, nil)
+
+	req.NoError(datasources.DeleteByName(context.Background(), "graphite"))
+}
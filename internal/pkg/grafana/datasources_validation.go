@@ -0,0 +1,460 @@
+package grafana
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var uidRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// ValidateDatasourceSpec statically checks a datasource spec: referenced
+// values (secrets, other datasources, ...) are not resolved.
+func ValidateDatasourceSpec(spec v1alpha1.DatasourceSpec) field.ErrorList {
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	configured := 0
+	configure := func(datasourceType string, set bool, validate func(path *field.Path) field.ErrorList) {
+		if !set {
+			return
+		}
+
+		configured++
+		if configured > 1 {
+			errs = append(errs, field.Forbidden(specPath.Child(datasourceType), "only one datasource type may be specified"))
+			return
+		}
+
+		errs = append(errs, validate(specPath.Child(datasourceType))...)
+	}
+
+	if spec.UID != "" && (len(spec.UID) > maxUIDLength || !uidRegex.MatchString(spec.UID)) {
+		errs = append(errs, field.Invalid(specPath.Child("uid"), spec.UID, "must be at most 40 letters, digits, dashes or underscores"))
+	}
+
+	configure("prometheus", spec.Prometheus != nil, func(path *field.Path) field.ErrorList {
+		return validatePrometheusDatasource(path, spec.Prometheus)
+	})
+	configure("stackdriver", spec.Stackdriver != nil, func(path *field.Path) field.ErrorList {
+		return validateStackdriverDatasource(path, spec.Stackdriver)
+	})
+	configure("jaeger", spec.Jaeger != nil, func(path *field.Path) field.ErrorList {
+		return validateJaegerDatasource(path, spec.Jaeger)
+	})
+	configure("loki", spec.Loki != nil, func(path *field.Path) field.ErrorList {
+		return validateLokiDatasource(path, spec.Loki)
+	})
+	configure("tempo", spec.Tempo != nil, func(path *field.Path) field.ErrorList {
+		return validateTempoDatasource(path, spec.Tempo)
+	})
+	configure("cloudwatch", spec.CloudWatch != nil, func(path *field.Path) field.ErrorList {
+		return validateCloudWatchDatasource(path, spec.CloudWatch)
+	})
+	configure("influxdb", spec.InfluxDB != nil, func(path *field.Path) field.ErrorList {
+		return validateInfluxDBDatasource(path, spec.InfluxDB)
+	})
+	configure("graphite", spec.Graphite != nil, func(path *field.Path) field.ErrorList {
+		return validateGraphiteDatasource(path, spec.Graphite)
+	})
+	configure("elasticsearch", spec.Elasticsearch != nil, func(path *field.Path) field.ErrorList {
+		return validateElasticsearchDatasource(path, spec.Elasticsearch)
+	})
+	configure("postgresql", spec.PostgreSQL != nil, func(path *field.Path) field.ErrorList {
+		return validatePostgreSQLDatasource(path, spec.PostgreSQL)
+	})
+	configure("mysql", spec.MySQL != nil, func(path *field.Path) field.ErrorList {
+		return validateMySQLDatasource(path, spec.MySQL)
+	})
+	configure("generic", spec.Generic != nil, func(path *field.Path) field.ErrorList {
+		return validateGenericDatasource(path, spec.Generic)
+	})
+
+	if configured == 0 {
+		errs = append(errs, field.Required(specPath, ErrDatasourceNotConfigured.Error()))
+	}
+
+	return errs
+}
+
+func validatePrometheusDatasource(path *field.Path, spec *v1alpha1.PrometheusDatasource) field.ErrorList {
+	var errs field.ErrorList
+
+	errs = append(errs, ValidateValueOrRef(path.Child("url"), spec.URL.ValueOrRef)...)
+	errs = append(errs, validateDuration(path.Child("scrape_interval"), spec.ScrapeInterval)...)
+	errs = append(errs, validateDuration(path.Child("query_timeout"), spec.QueryTimeout)...)
+	errs = append(errs, validateBasicAuth(path.Child("basic_auth"), spec.BasicAuth)...)
+
+	if spec.AccessMode != "" && spec.AccessMode != "proxy" && spec.AccessMode != "direct" {
+		errs = append(errs, field.NotSupported(path.Child("access_mode"), spec.AccessMode, []string{"proxy", "direct"}))
+	}
+	if spec.CACertificate != nil {
+		errs = append(errs, ValidateValueOrRef(path.Child("ca_certificate"), *spec.CACertificate)...)
+	}
+
+	for i, exemplar := range spec.Exemplars {
+		exemplarPath := path.Child("exemplars").Index(i)
+
+		switch {
+		case exemplar.URL != "" && exemplar.Datasource != nil:
+			errs = append(errs, field.Forbidden(exemplarPath, "only one of url and datasource may be specified"))
+		case exemplar.URL == "" && exemplar.Datasource == nil:
+			errs = append(errs, field.Required(exemplarPath, ErrInvalidExemplar.Error()+": url or datasource required"))
+		case exemplar.Datasource != nil:
+			errs = append(errs, validateDatasourceRef(exemplarPath.Child("datasource"), *exemplar.Datasource)...)
+		}
+	}
+
+	return errs
+}
+
+func validateStackdriverDatasource(path *field.Path, spec *v1alpha1.StackdriverDatasource) field.ErrorList {
+	if spec.JWTAuthentication == nil {
+		return nil
+	}
+
+	return ValidateValueOrRef(path.Child("jwt_authentication"), *spec.JWTAuthentication)
+}
+
+func validateJaegerDatasource(path *field.Path, spec *v1alpha1.JaegerDatasource) field.ErrorList {
+	var errs field.ErrorList
+
+	errs = append(errs, ValidateValueOrRef(path.Child("url"), spec.URL.ValueOrRef)...)
+	errs = append(errs, validateDuration(path.Child("timeout"), spec.Timeout)...)
+	errs = append(errs, validateBasicAuth(path.Child("basic_auth"), spec.BasicAuth)...)
+	errs = append(errs, validateTraceToLogs(path.Child("trace_to_logs"), spec.TraceToLogs)...)
+
+	if spec.CACertificate != nil {
+		errs = append(errs, ValidateValueOrRef(path.Child("ca_certificate"), *spec.CACertificate)...)
+	}
+
+	return errs
+}
+
+func validateLokiDatasource(path *field.Path, spec *v1alpha1.LokiDatasource) field.ErrorList {
+	var errs field.ErrorList
+
+	errs = append(errs, ValidateValueOrRef(path.Child("url"), spec.URL.ValueOrRef)...)
+	errs = append(errs, validateDuration(path.Child("timeout"), spec.Timeout)...)
+	errs = append(errs, validateBasicAuth(path.Child("basic_auth"), spec.BasicAuth)...)
+
+	if spec.CACertificate != nil {
+		errs = append(errs, ValidateValueOrRef(path.Child("ca_certificate"), *spec.CACertificate)...)
+	}
+
+	for i, derivedField := range spec.DerivedFields {
+		if derivedField.Datasource != nil {
+			errs = append(errs, validateDatasourceRef(path.Child("derived_fields").Index(i).Child("datasource"), *derivedField.Datasource)...)
+		}
+	}
+
+	return errs
+}
+
+func validateTempoDatasource(path *field.Path, spec *v1alpha1.TempoDatasource) field.ErrorList {
+	var errs field.ErrorList
+
+	errs = append(errs, ValidateValueOrRef(path.Child("url"), spec.URL.ValueOrRef)...)
+	errs = append(errs, validateDuration(path.Child("timeout"), spec.Timeout)...)
+	errs = append(errs, validateBasicAuth(path.Child("basic_auth"), spec.BasicAuth)...)
+	errs = append(errs, validateTraceToLogs(path.Child("trace_to_logs"), spec.TraceToLogs)...)
+
+	if spec.CACertificate != nil {
+		errs = append(errs, ValidateValueOrRef(path.Child("ca_certificate"), *spec.CACertificate)...)
+	}
+
+	return errs
+}
+
+func validateCloudWatchDatasource(path *field.Path, spec *v1alpha1.CloudWatchDatasource) field.ErrorList {
+	if spec.Auth == nil || spec.Auth.Keys == nil {
+		return nil
+	}
+
+	keysPath := path.Child("auth", "keys")
+	if spec.Auth.Keys.Secret == nil {
+		return field.ErrorList{field.Required(keysPath.Child("secret"), "")}
+	}
+
+	return ValidateValueOrRef(keysPath.Child("secret"), *spec.Auth.Keys.Secret)
+}
+
+func validateInfluxDBDatasource(path *field.Path, spec *v1alpha1.InfluxDBDatasource) field.ErrorList {
+	var errs field.ErrorList
+
+	errs = append(errs, ValidateValueOrRef(path.Child("url"), spec.URL.ValueOrRef)...)
+	errs = append(errs, validateDuration(path.Child("min_time_interval"), spec.MinTimeInterval)...)
+	errs = append(errs, validateHTTPSettings(path, spec.Timeout, spec.BasicAuth, spec.CACertificate)...)
+
+	if spec.QueryLanguage == Flux {
+		if spec.Organization == "" {
+			errs = append(errs, field.Required(path.Child("organization"), "required with the Flux query language"))
+		}
+		if spec.Token == nil {
+			errs = append(errs, field.Required(path.Child("token"), "required with the Flux query language"))
+		} else {
+			errs = append(errs, ValidateValueOrRef(path.Child("token"), *spec.Token)...)
+		}
+
+		errs = append(errs, forbidFields(path, "only supported by the InfluxQL query language", map[string]bool{
+			"database":    spec.Database != "",
+			"user":        spec.User != nil,
+			"password":    spec.Password != nil,
+			"http_method": spec.HTTPMethod != "",
+		})...)
+
+		return errs
+	}
+
+	if spec.Database == "" {
+		errs = append(errs, field.Required(path.Child("database"), "required with the InfluxQL query language"))
+	}
+	if spec.User != nil {
+		errs = append(errs, ValidateValueOrRef(path.Child("user"), *spec.User)...)
+	}
+	if spec.Password != nil {
+		errs = append(errs, ValidateValueOrRef(path.Child("password"), *spec.Password)...)
+	}
+
+	errs = append(errs, forbidFields(path, "only supported by the Flux query language", map[string]bool{
+		"organization":   spec.Organization != "",
+		"default_bucket": spec.DefaultBucket != "",
+		"token":          spec.Token != nil,
+	})...)
+
+	return errs
+}
+
+func validateGraphiteDatasource(path *field.Path, spec *v1alpha1.GraphiteDatasource) field.ErrorList {
+	var errs field.ErrorList
+
+	errs = append(errs, ValidateValueOrRef(path.Child("url"), spec.URL.ValueOrRef)...)
+	errs = append(errs, validateHTTPSettings(path, spec.Timeout, spec.BasicAuth, spec.CACertificate)...)
+
+	return errs
+}
+
+func validateElasticsearchDatasource(path *field.Path, spec *v1alpha1.ElasticsearchDatasource) field.ErrorList {
+	var errs field.ErrorList
+
+	errs = append(errs, ValidateValueOrRef(path.Child("url"), spec.URL.ValueOrRef)...)
+	errs = append(errs, validateDuration(path.Child("min_time_interval"), spec.MinTimeInterval)...)
+	errs = append(errs, validateHTTPSettings(path, spec.Timeout, spec.BasicAuth, spec.CACertificate)...)
+
+	if spec.Index == "" {
+		errs = append(errs, field.Required(path.Child("index"), ""))
+	}
+	if spec.MaxConcurrentShardRequests != nil && *spec.MaxConcurrentShardRequests < 1 {
+		errs = append(errs, field.Invalid(path.Child("max_concurrent_shard_requests"), *spec.MaxConcurrentShardRequests, "must be greater than 0"))
+	}
+
+	return errs
+}
+
+func validatePostgreSQLDatasource(path *field.Path, spec *v1alpha1.PostgreSQLDatasource) field.ErrorList {
+	var errs field.ErrorList
+
+	errs = append(errs, ValidateValueOrRef(path.Child("url"), spec.URL.ValueOrRef)...)
+	errs = append(errs, validateSQLConnection(path, spec.Database, spec.User, spec.Password, spec.MinTimeInterval)...)
+
+	return errs
+}
+
+func validateMySQLDatasource(path *field.Path, spec *v1alpha1.MySQLDatasource) field.ErrorList {
+	var errs field.ErrorList
+
+	errs = append(errs, ValidateValueOrRef(path.Child("url"), spec.URL.ValueOrRef)...)
+	errs = append(errs, validateSQLConnection(path, spec.Database, spec.User, spec.Password, spec.MinTimeInterval)...)
+
+	if spec.CACertificate != nil {
+		errs = append(errs, ValidateValueOrRef(path.Child("ca_certificate"), *spec.CACertificate)...)
+	}
+
+	return errs
+}
+
+func validateGenericDatasource(path *field.Path, spec *v1alpha1.GenericDatasource) field.ErrorList {
+	var errs field.ErrorList
+
+	if spec.Type == "" {
+		errs = append(errs, field.Required(path.Child("type"), ""))
+	}
+	if spec.URL != nil {
+		errs = append(errs, ValidateValueOrRef(path.Child("url"), spec.URL.ValueOrRef)...)
+	}
+	if spec.Access != "" && spec.Access != "proxy" && spec.Access != "direct" {
+		errs = append(errs, field.NotSupported(path.Child("access"), spec.Access, []string{"proxy", "direct"}))
+	}
+	if spec.JSONData != nil && len(spec.JSONData.Raw) != 0 {
+		jsonData := map[string]interface{}{}
+		if err := json.Unmarshal(spec.JSONData.Raw, &jsonData); err != nil {
+			errs = append(errs, field.Invalid(path.Child("jsonData"), string(spec.JSONData.Raw), "must be an object"))
+		}
+	}
+
+	errs = append(errs, validateBasicAuth(path.Child("basic_auth"), spec.BasicAuth)...)
+
+	keys := make([]string, 0, len(spec.SecureJSONData))
+	for key := range spec.SecureJSONData {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		errs = append(errs, ValidateValueOrRef(path.Child("secureJsonData").Key(key), spec.SecureJSONData[key])...)
+	}
+
+	return errs
+}
+
+func validateSQLConnection(path *field.Path, database string, user v1alpha1.ValueOrRef, password v1alpha1.ValueOrRef, minTimeInterval string) field.ErrorList {
+	var errs field.ErrorList
+
+	if database == "" {
+		errs = append(errs, field.Required(path.Child("database"), ""))
+	}
+
+	errs = append(errs, ValidateValueOrRef(path.Child("user"), user)...)
+	errs = append(errs, ValidateValueOrRef(path.Child("password"), password)...)
+	errs = append(errs, validateDuration(path.Child("min_time_interval"), minTimeInterval)...)
+
+	return errs
+}
+
+func validateHTTPSettings(path *field.Path, timeout string, auth *v1alpha1.BasicAuth, caCertificate *v1alpha1.ValueOrRef) field.ErrorList {
+	var errs field.ErrorList
+
+	errs = append(errs, validateDuration(path.Child("timeout"), timeout)...)
+	errs = append(errs, validateBasicAuth(path.Child("basic_auth"), auth)...)
+
+	if caCertificate != nil {
+		errs = append(errs, ValidateValueOrRef(path.Child("ca_certificate"), *caCertificate)...)
+	}
+
+	return errs
+}
+
+// forbidFields reports the fields marked as set, sorted by name.
+func forbidFields(path *field.Path, detail string, fields map[string]bool) field.ErrorList {
+	names := make([]string, 0, len(fields))
+	for name, set := range fields {
+		if set {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	errs := make(field.ErrorList, 0, len(names))
+	for _, name := range names {
+		errs = append(errs, field.Forbidden(path.Child(name), detail))
+	}
+
+	return errs
+}
+
+func validateTraceToLogs(path *field.Path, spec *v1alpha1.TraceToLogs) field.ErrorList {
+	if spec == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+
+	errs = append(errs, validateDatasourceRef(path.Child("datasource"), spec.Datasource)...)
+	errs = append(errs, validateDuration(path.Child("span_start_shift"), spec.SpanStartShift)...)
+	errs = append(errs, validateDuration(path.Child("span_end_shift"), spec.SpanEndShift)...)
+
+	return errs
+}
+
+func validateBasicAuth(path *field.Path, auth *v1alpha1.BasicAuth) field.ErrorList {
+	if auth == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+
+	errs = append(errs, ValidateValueOrRef(path.Child("username"), auth.Username)...)
+	errs = append(errs, ValidateValueOrRef(path.Child("password"), auth.Password)...)
+
+	return errs
+}
+
+func validateDatasourceRef(path *field.Path, ref v1alpha1.ValueOrDatasourceRef) field.ErrorList {
+	if ref.UID == "" && ref.Name == "" {
+		return field.ErrorList{field.Required(path, ErrInvalidDatasourceRef.Error()+": uid or name required")}
+	}
+
+	return nil
+}
+
+func validateDuration(path *field.Path, duration string) field.ErrorList {
+	if duration == "" {
+		return nil
+	}
+
+	if _, err := time.ParseDuration(duration); err != nil {
+		return field.ErrorList{field.Invalid(path, duration, err.Error())}
+	}
+
+	return nil
+}
+
+// ValidateValueOrRef checks that exactly one of a value or a reference to a
+// value is given.
+func ValidateValueOrRef(path *field.Path, ref v1alpha1.ValueOrRef) field.ErrorList {
+	switch {
+	case ref.Value != "" && ref.ValueRef != nil:
+		return field.ErrorList{field.Forbidden(path, "only one of value and valueFrom may be specified")}
+	case ref.Value != "":
+		return nil
+	case ref.ValueRef == nil:
+		return field.ErrorList{field.Required(path, "value or valueFrom required")}
+	}
+
+	refPath := path.Child("valueFrom")
+
+	var refs []string
+	var errs field.ErrorList
+	if secretRef := ref.ValueRef.SecretKeyRef; secretRef != nil {
+		refs = append(refs, "secretKeyRef")
+		errs = append(errs, validateKeyRef(refPath.Child("secretKeyRef"), secretRef.Name, secretRef.Key)...)
+	}
+	if configMapRef := ref.ValueRef.ConfigMapKeyRef; configMapRef != nil {
+		refs = append(refs, "configMapKeyRef")
+		errs = append(errs, validateKeyRef(refPath.Child("configMapKeyRef"), configMapRef.Name, configMapRef.Key)...)
+	}
+	if externalRef := ref.ValueRef.ExternalKeyRef; externalRef != nil {
+		refs = append(refs, "externalKeyRef")
+		if externalRef.Backend == "" {
+			errs = append(errs, field.Required(refPath.Child("externalKeyRef", "backend"), ""))
+		}
+		if externalRef.Path == "" {
+			errs = append(errs, field.Required(refPath.Child("externalKeyRef", "path"), ""))
+		}
+	}
+
+	switch {
+	case len(refs) == 0:
+		return field.ErrorList{field.Required(refPath, "secretKeyRef, configMapKeyRef or externalKeyRef required")}
+	case len(refs) > 1:
+		return field.ErrorList{field.Forbidden(refPath, "only one of "+strings.Join(refs, ", ")+" may be specified")}
+	}
+
+	return errs
+}
+
+func validateKeyRef(path *field.Path, name string, key string) field.ErrorList {
+	switch {
+	case name == "":
+		return field.ErrorList{field.Required(path.Child("name"), "")}
+	case key == "":
+		return field.ErrorList{field.Required(path.Child("key"), "")}
+	}
+
+	return nil
+}
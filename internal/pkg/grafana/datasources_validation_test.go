@@ -0,0 +1,189 @@
+package grafana
+
+import (
+	"testing"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateDatasourceSpecAcceptsValidSpecs(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateDatasourceSpec(v1alpha1.DatasourceSpec{
+		Prometheus: &v1alpha1.PrometheusDatasource{
+			URL:            v1alpha1.NewStringOrRef("http://prometheus:9090"),
+			ScrapeInterval: "30s",
+			BasicAuth: &v1alpha1.BasicAuth{
+				Username: v1alpha1.ValueOrRef{Value: "joe"},
+				Password: v1alpha1.ValueOrRef{ValueRef: &v1alpha1.ValueRef{SecretKeyRef: secretKeyRef("prometheus", "password")}},
+			},
+		},
+	})
+
+	req.Empty(errs)
+}
+
+func TestValidateDatasourceSpecRequiresADatasourceType(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateDatasourceSpec(v1alpha1.DatasourceSpec{})
+
+	req.Len(errs, 1)
+	req.Equal(field.ErrorTypeRequired, errs[0].Type)
+	req.Equal("spec", errs[0].Field)
+}
+
+func TestValidateDatasourceSpecRejectsSeveralDatasourceTypes(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateDatasourceSpec(v1alpha1.DatasourceSpec{
+		Prometheus: &v1alpha1.PrometheusDatasource{URL: v1alpha1.NewStringOrRef("http://prometheus:9090")},
+		Loki:       &v1alpha1.LokiDatasource{URL: v1alpha1.NewStringOrRef("http://loki:3100")},
+	})
+
+	req.Len(errs, 1)
+	req.Equal(field.ErrorTypeForbidden, errs[0].Type)
+	req.Equal("spec.loki", errs[0].Field)
+}
+
+func TestValidateDatasourceSpecRejectsInvalidDurations(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateDatasourceSpec(v1alpha1.DatasourceSpec{
+		Prometheus: &v1alpha1.PrometheusDatasource{
+			URL:            v1alpha1.NewStringOrRef("http://prometheus:9090"),
+			ScrapeInterval: "10",
+			QueryTimeout:   "soon",
+		},
+	})
+
+	req.Len(errs, 2)
+	req.Equal("spec.prometheus.scrape_interval", errs[0].Field)
+	req.Equal("spec.prometheus.query_timeout", errs[1].Field)
+}
+
+func TestValidateDatasourceSpecRejectsIncompleteReferences(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateDatasourceSpec(v1alpha1.DatasourceSpec{
+		Tempo: &v1alpha1.TempoDatasource{
+			URL: v1alpha1.NewStringOrRef("http://tempo:3100"),
+			BasicAuth: &v1alpha1.BasicAuth{
+				Username: v1alpha1.ValueOrRef{Value: "joe"},
+				Password: v1alpha1.ValueOrRef{ValueRef: &v1alpha1.ValueRef{SecretKeyRef: secretKeyRef("tempo", "")}},
+			},
+			TraceToLogs: &v1alpha1.TraceToLogs{},
+		},
+	})
+
+	req.Len(errs, 2)
+	req.Equal("spec.tempo.basic_auth.password.valueFrom.secretKeyRef.key", errs[0].Field)
+	req.Equal("spec.tempo.trace_to_logs.datasource", errs[1].Field)
+}
+
+func TestValidateDatasourceSpecChecksURLReferences(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateDatasourceSpec(v1alpha1.DatasourceSpec{
+		Loki: &v1alpha1.LokiDatasource{
+			URL: v1alpha1.StringOrRef{ValueOrRef: v1alpha1.ValueOrRef{
+				ValueRef: &v1alpha1.ValueRef{
+					SecretKeyRef: secretKeyRef("loki", "url"),
+					ConfigMapKeyRef: &v1.ConfigMapKeySelector{
+						LocalObjectReference: v1.LocalObjectReference{Name: "loki"},
+						Key:                  "url",
+					},
+				},
+			}},
+		},
+	})
+
+	req.Len(errs, 1)
+	req.Equal(field.ErrorTypeForbidden, errs[0].Type)
+	req.Equal("spec.loki.url.valueFrom", errs[0].Field)
+}
+
+func TestValidateDatasourceSpecRequiresAURL(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateDatasourceSpec(v1alpha1.DatasourceSpec{
+		Jaeger: &v1alpha1.JaegerDatasource{},
+	})
+
+	req.Len(errs, 1)
+	req.Equal(field.ErrorTypeRequired, errs[0].Type)
+	req.Equal("spec.jaeger.url", errs[0].Field)
+}
+
+func TestValidateDatasourceSpecChecksInfluxDBQueryLanguageSettings(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateDatasourceSpec(v1alpha1.DatasourceSpec{
+		InfluxDB: &v1alpha1.InfluxDBDatasource{
+			URL:           v1alpha1.NewStringOrRef("http://influxdb:8086"),
+			QueryLanguage: Flux,
+			Organization:  "acme",
+			Token:         &v1alpha1.ValueOrRef{Value: "token"},
+			Database:      "metrics",
+			HTTPMethod:    "POST",
+		},
+	})
+
+	req.Len(errs, 2)
+	req.Equal(field.ErrorTypeForbidden, errs[0].Type)
+	req.Equal("spec.influxdb.database", errs[0].Field)
+	req.Equal("spec.influxdb.http_method", errs[1].Field)
+}
+
+func TestValidateDatasourceSpecRequiresSQLCredentials(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateDatasourceSpec(v1alpha1.DatasourceSpec{
+		MySQL: &v1alpha1.MySQLDatasource{
+			URL:      v1alpha1.NewStringOrRef("mysql:3306"),
+			Database: "app",
+			User:     v1alpha1.ValueOrRef{Value: "grafana"},
+		},
+	})
+
+	req.Len(errs, 1)
+	req.Equal(field.ErrorTypeRequired, errs[0].Type)
+	req.Equal("spec.mysql.password", errs[0].Field)
+}
+
+func TestValidateDatasourceSpecChecksGenericDatasources(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateDatasourceSpec(v1alpha1.DatasourceSpec{
+		Generic: &v1alpha1.GenericDatasource{
+			Type:     "grafana-clickhouse-datasource",
+			JSONData: &runtime.RawExtension{Raw: []byte(`["not", "an", "object"]`)},
+			SecureJSONData: map[string]v1alpha1.ValueOrRef{
+				"password": {},
+			},
+		},
+	})
+
+	req.Len(errs, 2)
+	req.Equal("spec.generic.jsonData", errs[0].Field)
+	req.Equal("spec.generic.secureJsonData[password]", errs[1].Field)
+}
+
+func TestValidateDatasourceSpecRejectsInvalidUIDs(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateDatasourceSpec(v1alpha1.DatasourceSpec{
+		UID: "not a valid uid",
+		Jaeger: &v1alpha1.JaegerDatasource{
+			URL: v1alpha1.NewStringOrRef("http://jaeger"),
+		},
+	})
+
+	req.Len(errs, 1)
+	req.Equal(field.ErrorTypeInvalid, errs[0].Type)
+	req.Equal("spec.uid", errs[0].Field)
+}
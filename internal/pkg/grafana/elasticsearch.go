@@ -0,0 +1,64 @@
+package grafana
+
+import (
+	"context"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/grabana/datasource"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// OpenSearchFlavor designates OpenSearch clusters, queried with the
+// OpenSearch plugin instead of the built-in Elasticsearch datasource.
+const OpenSearchFlavor = "opensearch"
+
+func (datasources *Datasources) elasticsearchSpecToModel(ctx context.Context, objectRef types.NamespacedName, spec *v1alpha1.ElasticsearchDatasource) (datasource.Datasource, error) {
+	url, err := datasources.url(ctx, objectRef.Namespace, spec.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	datasourceType := "elasticsearch"
+	if spec.Flavor == OpenSearchFlavor {
+		datasourceType = "grafana-opensearch-datasource"
+	}
+
+	ds := newSDKDatasource(objectRef.Name, datasourceType, url)
+
+	err = datasources.applyHTTPSettings(ctx, objectRef.Namespace, ds, httpSettings{
+		Default:       spec.Default,
+		SkipTLSVerify: spec.SkipTLSVerify,
+		Timeout:       spec.Timeout,
+		BasicAuth:     spec.BasicAuth,
+		CACertificate: spec.CACertificate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	timeField := spec.TimeField
+	if timeField == "" {
+		timeField = "@timestamp"
+	}
+
+	ds.builder.Database = &spec.Index
+	ds.jsonData()["index"] = spec.Index
+	ds.jsonData()["timeField"] = timeField
+	ds.setIfNotEmpty("interval", spec.IndexInterval)
+	ds.setIfNotEmpty("logMessageField", spec.LogMessageField)
+	ds.setIfNotEmpty("logLevelField", spec.LogLevelField)
+	ds.setIfNotEmpty("timeInterval", spec.MinTimeInterval)
+
+	if spec.MaxConcurrentShardRequests != nil {
+		ds.jsonData()["maxConcurrentShardRequests"] = *spec.MaxConcurrentShardRequests
+	}
+
+	if spec.Flavor == OpenSearchFlavor {
+		ds.jsonData()["flavor"] = OpenSearchFlavor
+		ds.setIfNotEmpty("version", spec.Version)
+	} else {
+		ds.setIfNotEmpty("esVersion", spec.Version)
+	}
+
+	return ds, nil
+}
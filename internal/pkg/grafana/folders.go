@@ -0,0 +1,265 @@
+package grafana
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/grabana"
+	"github.com/go-logr/logr"
+)
+
+// Folder describes a folder to create or update in Grafana.
+type Folder struct {
+	UID       string
+	Title     string
+	ParentUID string
+
+	Permissions []v1alpha1.Permission
+	// ResetPermissions resets the permissions of the folder to Grafana's
+	// defaults when Permissions is empty.
+	ResetPermissions bool
+}
+
+// defaultFolderPermissions are the permissions Grafana grants on the folders
+// it creates.
+var defaultFolderPermissions = []permission{
+	{Role: "Editor", Permission: permissionLevels["edit"]},
+	{Role: "Viewer", Permission: permissionLevels["view"]},
+}
+
+type rawFolder struct {
+	ID        uint   `json:"id"`
+	UID       string `json:"uid"`
+	Title     string `json:"title"`
+	ParentUID string `json:"parentUid,omitempty"`
+}
+
+type Folders struct {
+	logger        logr.Logger
+	grafanaClient *Client
+}
+
+func NewFolders(logger logr.Logger, grafanaClient *Client) *Folders {
+	return &Folders{
+		logger:        logger,
+		grafanaClient: grafanaClient,
+	}
+}
+
+// Upsert creates or updates the given folder, and sets its permissions.
+func (folders *Folders) Upsert(ctx context.Context, folder Folder) error {
+	folders.logger.Info("upserting folder", "uid", folder.UID)
+
+	permissions, err := folders.grafanaClient.resolvePermissions(ctx, folder.Permissions)
+	if err != nil {
+		return err
+	}
+
+	existing, err := folders.grafanaClient.folderByUID(ctx, folder.UID)
+	if err != nil && err != grabana.ErrFolderNotFound {
+		return err
+	}
+
+	if existing == nil {
+		err = folders.grafanaClient.createFolder(ctx, rawFolder{UID: folder.UID, Title: folder.Title, ParentUID: folder.ParentUID})
+	} else {
+		err = folders.grafanaClient.updateFolder(ctx, *existing, folder)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(permissions) == 0 {
+		if !folder.ResetPermissions {
+			return nil
+		}
+
+		permissions = defaultFolderPermissions
+	}
+
+	return folders.grafanaClient.setPermissions(ctx, "/api/folders/"+url.PathEscape(folder.UID)+"/permissions", permissions)
+}
+
+// Delete deletes the given folder, if it doesn't hold any dashboard or folder.
+// It returns whether the folder was deleted.
+func (folders *Folders) Delete(ctx context.Context, uid string) (bool, error) {
+	folders.logger.Info("deleting folder", "uid", uid)
+
+	return deleteFolderIfEmpty(ctx, folders.grafanaClient, uid)
+}
+
+func deleteFolderIfEmpty(ctx context.Context, client *Client, uid string) (bool, error) {
+	empty, err := client.folderIsEmpty(ctx, uid)
+	if err != nil {
+		return false, err
+	}
+	if !empty {
+		return false, nil
+	}
+
+	if err := client.deleteFolder(ctx, uid); err != nil && err != grabana.ErrFolderNotFound {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (client *Client) folderByUID(ctx context.Context, uid string) (*rawFolder, error) {
+	resp, err := client.get(ctx, "/api/folders/"+url.PathEscape(uid))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, grabana.ErrFolderNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, client.httpError(resp)
+	}
+
+	var folder rawFolder
+	if err := decodeJSON(resp.Body, &folder); err != nil {
+		return nil, err
+	}
+
+	return &folder, nil
+}
+
+func (client *Client) createFolder(ctx context.Context, folder rawFolder) error {
+	resp, err := client.sendJSON(ctx, http.MethodPost, "/api/folders", folder)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return client.httpError(resp)
+	}
+
+	return nil
+}
+
+func (client *Client) updateFolder(ctx context.Context, existing rawFolder, folder Folder) error {
+	if existing.Title != folder.Title {
+		resp, err := client.sendJSON(ctx, http.MethodPut, "/api/folders/"+url.PathEscape(folder.UID), struct {
+			Title     string `json:"title"`
+			Overwrite bool   `json:"overwrite"`
+		}{
+			Title:     folder.Title,
+			Overwrite: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode != http.StatusOK {
+			return client.httpError(resp)
+		}
+	}
+
+	if existing.ParentUID != folder.ParentUID {
+		resp, err := client.sendJSON(ctx, http.MethodPost, "/api/folders/"+url.PathEscape(folder.UID)+"/move", struct {
+			ParentUID string `json:"parentUid"`
+		}{
+			ParentUID: folder.ParentUID,
+		})
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode != http.StatusOK {
+			return client.httpError(resp)
+		}
+	}
+
+	return nil
+}
+
+// folderIsEmpty tells whether the given folder holds neither dashboards, nor
+// other folders, nor alert rules.
+func (client *Client) folderIsEmpty(ctx context.Context, uid string) (bool, error) {
+	resp, err := client.get(ctx, "/api/search?limit=1&folderUIDs="+url.QueryEscape(uid))
+	if err != nil {
+		return false, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, client.httpError(resp)
+	}
+
+	var hits []struct {
+		UID string `json:"uid"`
+	}
+	if err := decodeJSON(resp.Body, &hits); err != nil {
+		return false, err
+	}
+	if len(hits) != 0 {
+		return false, nil
+	}
+
+	holdsAlertRules, err := client.folderHoldsAlertRules(ctx, uid)
+	if err != nil {
+		return false, err
+	}
+
+	return !holdsAlertRules, nil
+}
+
+// folderHoldsAlertRules tells whether the given folder holds alert rules.
+// Deleting such a folder would delete its rules.
+func (client *Client) folderHoldsAlertRules(ctx context.Context, uid string) (bool, error) {
+	resp, err := client.get(ctx, "/api/v1/provisioning/alert-rules")
+	if err != nil {
+		return false, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, client.httpError(resp)
+	}
+
+	var rules []struct {
+		FolderUID string `json:"folderUID"`
+	}
+	if err := decodeJSON(resp.Body, &rules); err != nil {
+		return false, err
+	}
+
+	for _, rule := range rules {
+		if rule.FolderUID == uid {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (client *Client) deleteFolder(ctx context.Context, uid string) error {
+	resp, err := client.delete(ctx, "/api/folders/"+url.PathEscape(uid))
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return grabana.ErrFolderNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return client.httpError(resp)
+	}
+
+	return nil
+}
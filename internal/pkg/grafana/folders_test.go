@@ -0,0 +1,130 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+)
+
+func testFolders(t *testing.T, handler http.HandlerFunc) *Folders {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(ClientConfig{Host: server.URL})
+	require.NoError(t, err)
+
+	return NewFolders(logr.Discard(), client)
+}
+
+func TestDeleteFolderDeletesEmptyFolders(t *testing.T) {
+	req := require.New(t)
+
+	deleted := false
+	folders := testFolders(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/search":
+			req.Equal("folder-uid", r.URL.Query().Get("folderUIDs"))
+			writeJSON(t, w, []interface{}{})
+		case "GET /api/v1/provisioning/alert-rules":
+			writeJSON(t, w, []map[string]string{{"uid": "rule", "folderUID": "other-folder"}})
+		case "DELETE /api/folders/folder-uid":
+			deleted = true
+			writeJSON(t, w, map[string]string{})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	ok, err := folders.Delete(context.Background(), "folder-uid")
+	req.NoError(err)
+
+	req.True(ok)
+	req.True(deleted)
+}
+
+func TestDeleteFolderKeepsFoldersHoldingDashboards(t *testing.T) {
+	req := require.New(t)
+
+	folders := testFolders(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/search":
+			writeJSON(t, w, []map[string]string{{"uid": "dashboard"}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	ok, err := folders.Delete(context.Background(), "folder-uid")
+	req.NoError(err)
+
+	req.False(ok)
+}
+
+func TestDeleteFolderKeepsFoldersHoldingAlertRules(t *testing.T) {
+	req := require.New(t)
+
+	folders := testFolders(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/search":
+			writeJSON(t, w, []interface{}{})
+		case "GET /api/v1/provisioning/alert-rules":
+			writeJSON(t, w, []map[string]string{{"uid": "rule", "folderUID": "folder-uid"}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	ok, err := folders.Delete(context.Background(), "folder-uid")
+	req.NoError(err)
+
+	req.False(ok)
+}
+
+func TestUpsertFolderResetsPermissionsToDefaults(t *testing.T) {
+	req := require.New(t)
+
+	var items []permission
+	folders := testFolders(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/folders/folder-uid":
+			writeJSON(t, w, rawFolder{ID: 3, UID: "folder-uid", Title: "Platform"})
+		case "POST /api/folders/folder-uid/permissions":
+			body := struct {
+				Items []permission `json:"items"`
+			}{}
+			req.NoError(json.NewDecoder(r.Body).Decode(&body))
+			items = body.Items
+			writeJSON(t, w, map[string]string{})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	err := folders.Upsert(context.Background(), Folder{UID: "folder-uid", Title: "Platform", ResetPermissions: true})
+	req.NoError(err)
+
+	req.Equal(defaultFolderPermissions, items)
+}
+
+func TestUpsertFolderLeavesPermissionsItDidNotApply(t *testing.T) {
+	req := require.New(t)
+
+	folders := testFolders(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/folders/folder-uid":
+			writeJSON(t, w, rawFolder{ID: 3, UID: "folder-uid", Title: "Platform"})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	err := folders.Upsert(context.Background(), Folder{UID: "folder-uid", Title: "Platform"})
+	req.NoError(err)
+}
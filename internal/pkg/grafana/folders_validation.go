@@ -0,0 +1,21 @@
+package grafana
+
+import (
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidateFolderSpec statically checks a folder spec.
+func ValidateFolderSpec(spec v1alpha1.GrafanaFolderSpec) field.ErrorList {
+	var errs field.ErrorList
+
+	specPath := field.NewPath("spec")
+
+	if spec.Title == "" {
+		errs = append(errs, field.Required(specPath.Child("title"), "a title is required"))
+	}
+
+	errs = append(errs, ValidatePermissions(specPath.Child("permissions"), spec.Permissions)...)
+
+	return errs
+}
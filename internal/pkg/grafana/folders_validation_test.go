@@ -0,0 +1,65 @@
+package grafana
+
+import (
+	"testing"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateFolderSpecAcceptsValidSpecs(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateFolderSpec(v1alpha1.GrafanaFolderSpec{
+		Title: "Platform",
+		Permissions: []v1alpha1.Permission{
+			{Team: "sre", Permission: "admin"},
+			{Role: "Viewer", Permission: "view"},
+		},
+	})
+
+	req.Empty(errs)
+}
+
+func TestValidateFolderSpecRequiresATitle(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateFolderSpec(v1alpha1.GrafanaFolderSpec{})
+
+	req.Len(errs, 1)
+	req.Equal(field.ErrorTypeRequired, errs[0].Type)
+	req.Equal("spec.title", errs[0].Field)
+}
+
+func TestValidateFolderSpecRejectsPermissionsForSeveralTargets(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateFolderSpec(v1alpha1.GrafanaFolderSpec{
+		Title: "Platform",
+		Permissions: []v1alpha1.Permission{
+			{Team: "sre", TeamRef: &v1alpha1.TeamRef{Name: "sre"}, Permission: "edit"},
+		},
+	})
+
+	req.Len(errs, 1)
+	req.Equal(field.ErrorTypeForbidden, errs[0].Type)
+	req.Equal("spec.permissions[0]", errs[0].Field)
+}
+
+func TestValidateFolderSpecRequiresPermissionsToTargetATeamOrARole(t *testing.T) {
+	req := require.New(t)
+
+	errs := ValidateFolderSpec(v1alpha1.GrafanaFolderSpec{
+		Title: "Platform",
+		Permissions: []v1alpha1.Permission{
+			{Permission: "superpowers"},
+		},
+	})
+
+	req.Len(errs, 2)
+	req.Equal(field.ErrorTypeRequired, errs[0].Type)
+	req.Equal("spec.permissions[0]", errs[0].Field)
+	req.Equal(field.ErrorTypeNotSupported, errs[1].Type)
+	req.Equal("spec.permissions[0].permission", errs[1].Field)
+}
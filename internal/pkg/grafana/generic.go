@@ -0,0 +1,54 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/grabana/datasource"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func (datasources *Datasources) genericSpecToModel(ctx context.Context, objectRef types.NamespacedName, spec *v1alpha1.GenericDatasource) (datasource.Datasource, error) {
+	url := ""
+	if spec.URL != nil {
+		var err error
+		if url, err = datasources.url(ctx, objectRef.Namespace, *spec.URL); err != nil {
+			return nil, err
+		}
+	}
+
+	ds := newSDKDatasource(objectRef.Name, spec.Type, url)
+	if spec.Access != "" {
+		ds.builder.Access = spec.Access
+	}
+
+	if spec.JSONData != nil && len(spec.JSONData.Raw) != 0 {
+		jsonData := map[string]interface{}{}
+		if err := json.Unmarshal(spec.JSONData.Raw, &jsonData); err != nil {
+			return nil, fmt.Errorf("could not decode jsonData: %w", err)
+		}
+
+		ds.builder.JSONData = jsonData
+	}
+
+	err := datasources.applyHTTPSettings(ctx, objectRef.Namespace, ds, httpSettings{
+		Default:   spec.Default,
+		BasicAuth: spec.BasicAuth,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for key, ref := range spec.SecureJSONData {
+		value, err := datasources.refReader.RefToValue(ctx, objectRef.Namespace, ref)
+		if err != nil {
+			return nil, fmt.Errorf("could not extract secureJsonData.%s: %w", key, err)
+		}
+
+		ds.secureJSONData()[key] = value
+	}
+
+	return ds, nil
+}
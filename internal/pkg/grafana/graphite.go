@@ -0,0 +1,34 @@
+package grafana
+
+import (
+	"context"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/grabana/datasource"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func (datasources *Datasources) graphiteSpecToModel(ctx context.Context, objectRef types.NamespacedName, spec *v1alpha1.GraphiteDatasource) (datasource.Datasource, error) {
+	url, err := datasources.url(ctx, objectRef.Namespace, spec.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	ds := newSDKDatasource(objectRef.Name, "graphite", url)
+
+	err = datasources.applyHTTPSettings(ctx, objectRef.Namespace, ds, httpSettings{
+		Default:       spec.Default,
+		SkipTLSVerify: spec.SkipTLSVerify,
+		Timeout:       spec.Timeout,
+		BasicAuth:     spec.BasicAuth,
+		CACertificate: spec.CACertificate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ds.setIfNotEmpty("graphiteVersion", spec.Version)
+	ds.setIfNotEmpty("graphiteType", spec.Type)
+
+	return ds, nil
+}
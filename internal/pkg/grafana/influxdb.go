@@ -0,0 +1,151 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/grabana/datasource"
+	"github.com/K-Phoen/grabana/datasource/influxdb"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Query languages supported by InfluxDB datasources.
+const (
+	InfluxQL = "InfluxQL"
+	Flux     = "Flux"
+)
+
+func (datasources *Datasources) influxDBSpecToModel(ctx context.Context, objectRef types.NamespacedName, spec *v1alpha1.InfluxDBDatasource) (datasource.Datasource, error) {
+	url, err := datasources.url(ctx, objectRef.Namespace, spec.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	if spec.QueryLanguage == Flux {
+		return datasources.fluxSpecToModel(ctx, objectRef, url, spec)
+	}
+
+	opts, err := datasources.influxQLSpecToOptions(ctx, objectRef, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	ds, err := influxdb.New(objectRef.Name, url, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// settings not supported by grabana: Grafana reads the HTTP method and the
+	// database from these fields
+	extension := map[string]interface{}{}
+	if spec.HTTPMethod != "" {
+		extension["httpMode"] = spec.HTTPMethod
+	}
+	if spec.Database != "" {
+		extension["dbName"] = spec.Database
+	}
+
+	return extendedDatasource{Datasource: ds, jsonData: extension}, nil
+}
+
+func (datasources *Datasources) influxQLSpecToOptions(ctx context.Context, objectRef types.NamespacedName, spec *v1alpha1.InfluxDBDatasource) ([]influxdb.Option, error) {
+	opts := []influxdb.Option{}
+
+	if spec.Default != nil && *spec.Default {
+		opts = append(opts, influxdb.Default())
+	}
+	if spec.SkipTLSVerify != nil && *spec.SkipTLSVerify {
+		opts = append(opts, influxdb.SkipTLSVerify())
+	}
+	if spec.Timeout != "" {
+		timeout, err := time.ParseDuration(spec.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse timeout: %w", err)
+		}
+
+		opts = append(opts, influxdb.Timeout(timeout))
+	}
+	if spec.BasicAuth != nil {
+		username, password, err := datasources.basicAuthCredentials(ctx, objectRef.Namespace, spec.BasicAuth)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, influxdb.BasicAuth(username, password))
+	}
+	if spec.CACertificate != nil {
+		caCertificate, err := datasources.refReader.RefToValue(ctx, objectRef.Namespace, *spec.CACertificate)
+		if err != nil {
+			return nil, fmt.Errorf("could not extract CA certificate: %w", err)
+		}
+
+		opts = append(opts, influxdb.WithCACert(caCertificate))
+	}
+	if spec.MinTimeInterval != "" {
+		interval, err := time.ParseDuration(spec.MinTimeInterval)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse min time interval: %w", err)
+		}
+
+		opts = append(opts, influxdb.MinTimeInterval(interval))
+	}
+	if spec.HTTPMethod != "" {
+		opts = append(opts, influxdb.HTTPMethod(spec.HTTPMethod))
+	}
+	if spec.Database != "" {
+		opts = append(opts, influxdb.Database(spec.Database))
+	}
+	if spec.User != nil {
+		user, err := datasources.refReader.RefToValue(ctx, objectRef.Namespace, *spec.User)
+		if err != nil {
+			return nil, fmt.Errorf("could not extract user: %w", err)
+		}
+
+		opts = append(opts, influxdb.User(user))
+	}
+	if spec.Password != nil {
+		password, err := datasources.refReader.RefToValue(ctx, objectRef.Namespace, *spec.Password)
+		if err != nil {
+			return nil, fmt.Errorf("could not extract password: %w", err)
+		}
+
+		opts = append(opts, influxdb.Password(password))
+	}
+
+	return opts, nil
+}
+
+// fluxSpecToModel describes an InfluxDB datasource queried with Flux, which
+// grabana doesn't support.
+func (datasources *Datasources) fluxSpecToModel(ctx context.Context, objectRef types.NamespacedName, url string, spec *v1alpha1.InfluxDBDatasource) (datasource.Datasource, error) {
+	ds := newSDKDatasource(objectRef.Name, "influxdb", url)
+
+	err := datasources.applyHTTPSettings(ctx, objectRef.Namespace, ds, httpSettings{
+		Default:       spec.Default,
+		SkipTLSVerify: spec.SkipTLSVerify,
+		Timeout:       spec.Timeout,
+		BasicAuth:     spec.BasicAuth,
+		CACertificate: spec.CACertificate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ds.setIfNotEmpty("timeInterval", spec.MinTimeInterval)
+	ds.jsonData()["version"] = Flux
+	ds.setIfNotEmpty("organization", spec.Organization)
+	ds.setIfNotEmpty("defaultBucket", spec.DefaultBucket)
+
+	if spec.Token != nil {
+		token, err := datasources.refReader.RefToValue(ctx, objectRef.Namespace, *spec.Token)
+		if err != nil {
+			return nil, fmt.Errorf("could not extract token: %w", err)
+		}
+
+		ds.secureJSONData()["token"] = token
+	}
+
+	return ds, nil
+}
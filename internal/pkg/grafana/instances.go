@@ -0,0 +1,205 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// InstanceAnnotation can be used to select the GrafanaInstance an object
+// belongs to, as "name" or "namespace/name".
+const InstanceAnnotation = "dark/instance"
+
+var ErrInstanceNotFound = fmt.Errorf("grafana instance not found")
+var ErrInstanceNotAllowed = fmt.Errorf("grafana instance can not be referenced from this namespace")
+
+type cachedClient struct {
+	config ClientConfig
+	client *Client
+}
+
+// Instances resolves the Grafana client to use for a given object, and keeps
+// a cache of clients keyed by GrafanaInstance.
+type Instances struct {
+	logger        logr.Logger
+	k8sClient     client.Reader
+	refReader     refReader
+	defaultClient *Client
+
+	lock    sync.Mutex
+	clients map[types.NamespacedName]cachedClient
+}
+
+func NewInstances(logger logr.Logger, k8sClient client.Reader, refReader refReader, defaultClient *Client) *Instances {
+	return &Instances{
+		logger:        logger,
+		k8sClient:     k8sClient,
+		refReader:     refReader,
+		defaultClient: defaultClient,
+		clients:       make(map[types.NamespacedName]cachedClient),
+	}
+}
+
+// ClientFor returns the client for the instance referenced by the given object,
+// either via an explicit reference or via the InstanceAnnotation.
+// Objects not referencing any instance use the default client.
+func (instances *Instances) ClientFor(ctx context.Context, object metav1.Object, ref *v1alpha1.InstanceRef) (*Client, error) {
+	instanceName, found := instanceNameFor(object, ref)
+	if !found {
+		return instances.defaultClient, nil
+	}
+
+	instance := &v1alpha1.GrafanaInstance{}
+	if err := instances.k8sClient.Get(ctx, instanceName, instance); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%s: %w", instanceName, ErrInstanceNotFound)
+		}
+
+		return nil, err
+	}
+
+	if !instanceAllowedFrom(instance, object.GetNamespace()) {
+		return nil, fmt.Errorf("%s: %w", instanceName, ErrInstanceNotAllowed)
+	}
+
+	return instances.ClientForInstance(ctx, instance)
+}
+
+// ClientForInstance returns the client for the given instance, creating it if
+// needed. Cached clients are re-created whenever the instance's configuration
+// (including the values of the secrets it references) changes.
+func (instances *Instances) ClientForInstance(ctx context.Context, instance *v1alpha1.GrafanaInstance) (*Client, error) {
+	config, err := instances.clientConfig(ctx, instance)
+	if err != nil {
+		return nil, err
+	}
+
+	instanceName := types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name}
+
+	instances.lock.Lock()
+	defer instances.lock.Unlock()
+
+	if cached, ok := instances.clients[instanceName]; ok && cached.config == config {
+		return cached.client, nil
+	}
+
+	instances.logger.Info("creating grafana client", "instance", instanceName.String(), "host", config.Host)
+
+	grafanaClient, err := NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	instances.clients[instanceName] = cachedClient{config: config, client: grafanaClient}
+
+	return grafanaClient, nil
+}
+
+// Forget removes the client for the given instance from the cache.
+func (instances *Instances) Forget(instanceName types.NamespacedName) {
+	instances.lock.Lock()
+	defer instances.lock.Unlock()
+
+	delete(instances.clients, instanceName)
+}
+
+func (instances *Instances) clientConfig(ctx context.Context, instance *v1alpha1.GrafanaInstance) (ClientConfig, error) {
+	spec := instance.Spec
+	config := ClientConfig{
+		Host: strings.TrimSuffix(spec.URL, "/"),
+	}
+
+	if spec.APIKey != nil {
+		token, err := instances.refReader.RefToValue(ctx, instance.Namespace, *spec.APIKey)
+		if err != nil {
+			return config, fmt.Errorf("could not extract API key: %w", err)
+		}
+
+		config.APIToken = token
+	} else if spec.BasicAuth != nil {
+		username, err := instances.refReader.RefToValue(ctx, instance.Namespace, spec.BasicAuth.Username)
+		if err != nil {
+			return config, fmt.Errorf("could not extract username: %w", err)
+		}
+		password, err := instances.refReader.RefToValue(ctx, instance.Namespace, spec.BasicAuth.Password)
+		if err != nil {
+			return config, fmt.Errorf("could not extract password: %w", err)
+		}
+
+		config.Username = username
+		config.Password = password
+	}
+
+	if spec.TLS != nil {
+		config.SkipTLSVerify = spec.TLS.SkipTLSVerify != nil && *spec.TLS.SkipTLSVerify
+
+		if spec.TLS.CACertificate != nil {
+			caCertificate, err := instances.refReader.RefToValue(ctx, instance.Namespace, *spec.TLS.CACertificate)
+			if err != nil {
+				return config, fmt.Errorf("could not extract CA certificate: %w", err)
+			}
+
+			config.CACertificate = caCertificate
+		}
+	}
+
+	return config, nil
+}
+
+// InstanceKey identifies the Grafana instance in which the given object is
+// synchronized. The operator-wide instance is identified by an empty key.
+func InstanceKey(object metav1.Object, ref *v1alpha1.InstanceRef) string {
+	instanceName, found := instanceNameFor(object, ref)
+	if !found {
+		return ""
+	}
+
+	return instanceName.String()
+}
+
+// instanceAllowedFrom tells whether objects in the given namespace may use the
+// given instance: only objects living next to the instance can, unless its
+// spec explicitly allows other namespaces.
+func instanceAllowedFrom(instance *v1alpha1.GrafanaInstance, namespace string) bool {
+	if instance.Namespace == namespace {
+		return true
+	}
+
+	for _, allowed := range instance.Spec.AllowedNamespaces {
+		if allowed == "*" || allowed == namespace {
+			return true
+		}
+	}
+
+	return false
+}
+
+func instanceNameFor(object metav1.Object, ref *v1alpha1.InstanceRef) (types.NamespacedName, bool) {
+	if ref != nil && ref.Name != "" {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = object.GetNamespace()
+		}
+
+		return types.NamespacedName{Namespace: namespace, Name: ref.Name}, true
+	}
+
+	annotation := object.GetAnnotations()[InstanceAnnotation]
+	if annotation == "" {
+		return types.NamespacedName{}, false
+	}
+
+	if namespace, name, found := strings.Cut(annotation, "/"); found {
+		return types.NamespacedName{Namespace: namespace, Name: name}, true
+	}
+
+	return types.NamespacedName{Namespace: object.GetNamespace(), Name: annotation}, true
+}
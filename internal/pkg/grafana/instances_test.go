@@ -0,0 +1,84 @@
+package grafana
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type instancesReader struct {
+	instance v1alpha1.GrafanaInstance
+}
+
+func (reader instancesReader) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	if key != (types.NamespacedName{Namespace: reader.instance.Namespace, Name: reader.instance.Name}) {
+		return errors.New("unexpected instance " + key.String())
+	}
+
+	reader.instance.DeepCopyInto(obj.(*v1alpha1.GrafanaInstance))
+
+	return nil
+}
+
+func (reader instancesReader) List(context.Context, client.ObjectList, ...client.ListOption) error {
+	return errors.New("not implemented")
+}
+
+func TestClientForRejectsInstancesFromOtherNamespaces(t *testing.T) {
+	req := require.New(t)
+
+	reader := instancesReader{instance: v1alpha1.GrafanaInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "staging", Namespace: "monitoring"},
+		Spec:       v1alpha1.GrafanaInstanceSpec{URL: "http://grafana"},
+	}}
+	instances := NewInstances(logr.Discard(), reader, valuesOnlyRefReader{}, nil)
+	object := &metav1.ObjectMeta{Name: "datasource", Namespace: "apps"}
+
+	_, err := instances.ClientFor(context.Background(), object, &v1alpha1.InstanceRef{Name: "staging", Namespace: "monitoring"})
+
+	req.ErrorIs(err, ErrInstanceNotAllowed)
+
+	object.Annotations = map[string]string{InstanceAnnotation: "monitoring/staging"}
+	_, err = instances.ClientFor(context.Background(), object, nil)
+
+	req.ErrorIs(err, ErrInstanceNotAllowed)
+}
+
+func TestClientForAcceptsAllowedNamespaces(t *testing.T) {
+	req := require.New(t)
+
+	reader := instancesReader{instance: v1alpha1.GrafanaInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "staging", Namespace: "monitoring"},
+		Spec: v1alpha1.GrafanaInstanceSpec{
+			URL:               "http://grafana",
+			AllowedNamespaces: []string{"apps"},
+		},
+	}}
+	instances := NewInstances(logr.Discard(), reader, valuesOnlyRefReader{}, nil)
+	object := &metav1.ObjectMeta{Name: "datasource", Namespace: "apps"}
+
+	grafanaClient, err := instances.ClientFor(context.Background(), object, &v1alpha1.InstanceRef{Name: "staging", Namespace: "monitoring"})
+
+	req.NoError(err)
+	req.NotNil(grafanaClient)
+}
+
+func TestInstanceAllowedFrom(t *testing.T) {
+	req := require.New(t)
+
+	instance := &v1alpha1.GrafanaInstance{ObjectMeta: metav1.ObjectMeta{Name: "staging", Namespace: "monitoring"}}
+
+	req.True(instanceAllowedFrom(instance, "monitoring"))
+	req.False(instanceAllowedFrom(instance, "apps"))
+
+	instance.Spec.AllowedNamespaces = []string{"*"}
+
+	req.True(instanceAllowedFrom(instance, "apps"))
+}
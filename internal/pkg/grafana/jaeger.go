@@ -17,7 +17,12 @@ func (datasources *Datasources) jaegerSpecToModel(ctx context.Context, objectRef
 		return nil, err
 	}
 
-	return jaeger.New(objectRef.Name, ds.URL, opts...), nil
+	url, err := datasources.url(ctx, objectRef.Namespace, ds.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	return jaeger.New(objectRef.Name, url, opts...), nil
 }
 
 func (datasources *Datasources) jaegerSpecToOptions(ctx context.Context, objectRef types.NamespacedName, spec *v1alpha1.JaegerDatasource) ([]jaeger.Option, error) {
@@ -66,7 +71,7 @@ func (datasources *Datasources) jaegerSpecToOptions(ctx context.Context, objectR
 		opts = append(opts, jaeger.WithNodeGraph())
 	}
 	if spec.TraceToLogs != nil {
-		opt, err := datasources.jaegerTraceToLogs(ctx, spec.TraceToLogs)
+		opt, err := datasources.jaegerTraceToLogs(ctx, objectRef.Namespace, spec.TraceToLogs)
 		if err != nil {
 			return nil, err
 		}
@@ -78,10 +83,10 @@ func (datasources *Datasources) jaegerSpecToOptions(ctx context.Context, objectR
 }
 
 //nolint:dupl
-func (datasources *Datasources) jaegerTraceToLogs(ctx context.Context, spec *v1alpha1.TraceToLogs) (jaeger.Option, error) {
+func (datasources *Datasources) jaegerTraceToLogs(ctx context.Context, namespace string, spec *v1alpha1.TraceToLogs) (jaeger.Option, error) {
 	opts := []jaeger.TraceToLogsOption{}
 
-	datasourceUID, err := datasources.datasourceUIDFromRef(ctx, &spec.Datasource)
+	datasourceUID, err := datasources.datasourceUIDFromRef(ctx, namespace, &spec.Datasource)
 	if err != nil {
 		return nil, fmt.Errorf("could not infer datasource UID from reference: %w", err)
 	}
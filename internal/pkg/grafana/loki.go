@@ -17,7 +17,12 @@ func (datasources *Datasources) lokiSpecToModel(ctx context.Context, objectRef t
 		return nil, err
 	}
 
-	return loki.New(objectRef.Name, ds.URL, opts...), nil
+	url, err := datasources.url(ctx, objectRef.Namespace, ds.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	return loki.New(objectRef.Name, url, opts...), nil
 }
 
 func (datasources *Datasources) lokiSpecToOptions(ctx context.Context, objectRef types.NamespacedName, spec *v1alpha1.LokiDatasource) ([]loki.Option, error) {
@@ -66,7 +71,7 @@ func (datasources *Datasources) lokiSpecToOptions(ctx context.Context, objectRef
 		opts = append(opts, loki.MaximumLines(*spec.MaximumLines))
 	}
 	if len(spec.DerivedFields) != 0 {
-		opt, err := datasources.lokiDerivedFields(ctx, spec.DerivedFields)
+		opt, err := datasources.lokiDerivedFields(ctx, objectRef.Namespace, spec.DerivedFields)
 		if err != nil {
 			return nil, fmt.Errorf("could not parse derived fields: %w", err)
 		}
@@ -77,14 +82,14 @@ func (datasources *Datasources) lokiSpecToOptions(ctx context.Context, objectRef
 	return opts, nil
 }
 
-func (datasources *Datasources) lokiDerivedFields(ctx context.Context, specFields []v1alpha1.LokiDerivedField) (loki.Option, error) {
+func (datasources *Datasources) lokiDerivedFields(ctx context.Context, namespace string, specFields []v1alpha1.LokiDerivedField) (loki.Option, error) {
 	var err error
 	fields := make([]loki.DerivedField, 0, len(specFields))
 
 	for _, field := range specFields {
 		datasourceUID := ""
 		if field.Datasource != nil {
-			datasourceUID, err = datasources.datasourceUIDFromRef(ctx, field.Datasource)
+			datasourceUID, err = datasources.datasourceUIDFromRef(ctx, namespace, field.Datasource)
 			if err != nil {
 				return nil, fmt.Errorf("could not infer datasource UID from reference: %w", err)
 			}
@@ -0,0 +1,124 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ownershipTag tags the organization annotations recording the users and
+// teams created by DARK. Grafana has no other place to store such metadata,
+// and annotations not attached to a dashboard are only displayed by queries
+// explicitly filtering on their tags.
+const ownershipTag = "dark-managed"
+
+// Kinds of objects whose ownership is recorded.
+const (
+	ownedUser = "user"
+	ownedTeam = "team"
+)
+
+type ownershipAnnotation struct {
+	ID   int64    `json:"id"`
+	Tags []string `json:"tags"`
+	Text string   `json:"text"`
+}
+
+func ownershipTags(kind string, id int64) []string {
+	return []string{ownershipTag, kind + ":" + strconv.FormatInt(id, 10)}
+}
+
+// ensureOwned records that DARK created the object of the given kind and ID,
+// unless it already did.
+func (client *Client) ensureOwned(ctx context.Context, kind string, id int64, name string) error {
+	owned, err := client.isOwned(ctx, kind, id)
+	if err != nil || owned {
+		return err
+	}
+
+	resp, err := client.sendJSON(ctx, http.MethodPost, "/api/annotations", ownershipAnnotation{
+		Tags: ownershipTags(kind, id),
+		Text: fmt.Sprintf("%s %s is managed by DARK", kind, name),
+	})
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return client.httpError(resp)
+	}
+
+	return nil
+}
+
+// isOwned tells whether DARK created the object of the given kind and ID.
+func (client *Client) isOwned(ctx context.Context, kind string, id int64) (bool, error) {
+	annotations, err := client.ownershipAnnotations(ctx, kind, id)
+	if err != nil {
+		return false, err
+	}
+
+	return len(annotations) != 0, nil
+}
+
+// forgetOwned removes the records of DARK having created the object of the
+// given kind and ID.
+func (client *Client) forgetOwned(ctx context.Context, kind string, id int64) error {
+	annotations, err := client.ownershipAnnotations(ctx, kind, id)
+	if err != nil {
+		return err
+	}
+
+	for _, annotation := range annotations {
+		if err := client.deleteAnnotation(ctx, annotation.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (client *Client) deleteAnnotation(ctx context.Context, id int64) error {
+	resp, err := client.delete(ctx, "/api/annotations/"+strconv.FormatInt(id, 10))
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return client.httpError(resp)
+	}
+
+	return nil
+}
+
+func (client *Client) ownershipAnnotations(ctx context.Context, kind string, id int64) ([]ownershipAnnotation, error) {
+	query := url.Values{
+		"type":     []string{"annotation"},
+		"matchAny": []string{"false"},
+		"tags":     ownershipTags(kind, id),
+	}
+
+	resp, err := client.get(ctx, "/api/annotations?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, client.httpError(resp)
+	}
+
+	var annotations []ownershipAnnotation
+	if err := decodeJSON(resp.Body, &annotations); err != nil {
+		return nil, err
+	}
+
+	return annotations, nil
+}
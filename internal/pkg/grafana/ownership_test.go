@@ -0,0 +1,108 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOwnership serves the annotations recording ownership.
+type fakeOwnership struct {
+	t *testing.T
+
+	annotations map[int64]ownershipAnnotation
+	nextID      int64
+}
+
+func newFakeOwnership(t *testing.T, owned ...string) *fakeOwnership {
+	t.Helper()
+
+	ownership := &fakeOwnership{t: t, annotations: map[int64]ownershipAnnotation{}}
+	for _, tag := range owned {
+		ownership.record([]string{ownershipTag, tag})
+	}
+
+	return ownership
+}
+
+func (ownership *fakeOwnership) record(tags []string) {
+	ownership.nextID++
+	ownership.annotations[ownership.nextID] = ownershipAnnotation{ID: ownership.nextID, Tags: tags}
+}
+
+func (ownership *fakeOwnership) owns(tag string) bool {
+	for _, annotation := range ownership.annotations {
+		if annotation.Tags[1] == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// serve handles the request if it targets annotations.
+func (ownership *fakeOwnership) serve(w http.ResponseWriter, r *http.Request) bool {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/annotations":
+		require.Equal(ownership.t, "false", r.URL.Query().Get("matchAny"))
+
+		wanted := r.URL.Query()["tags"]
+		matching := []ownershipAnnotation{}
+		for _, annotation := range ownership.annotations {
+			if strings.Join(annotation.Tags, ",") == strings.Join(wanted, ",") {
+				matching = append(matching, annotation)
+			}
+		}
+		writeJSON(ownership.t, w, matching)
+	case r.Method == http.MethodPost && r.URL.Path == "/api/annotations":
+		var annotation ownershipAnnotation
+		require.NoError(ownership.t, json.NewDecoder(r.Body).Decode(&annotation))
+		ownership.record(annotation.Tags)
+		writeJSON(ownership.t, w, map[string]interface{}{"id": ownership.nextID})
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/annotations/"):
+		id, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/api/annotations/"), 10, 64)
+		require.NoError(ownership.t, err)
+		delete(ownership.annotations, id)
+		writeJSON(ownership.t, w, map[string]string{})
+	default:
+		return false
+	}
+
+	return true
+}
+
+func TestOwnershipIsRecordedOnce(t *testing.T) {
+	req := require.New(t)
+
+	ownership := newFakeOwnership(t)
+	teams := testTeams(t, func(w http.ResponseWriter, r *http.Request) {
+		if !ownership.serve(w, r) {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	client := teams.grafanaClient
+
+	owned, err := client.isOwned(context.Background(), ownedTeam, 4)
+	req.NoError(err)
+	req.False(owned)
+
+	req.NoError(client.ensureOwned(context.Background(), ownedTeam, 4, "SRE"))
+	req.NoError(client.ensureOwned(context.Background(), ownedTeam, 4, "SRE"))
+	req.Len(ownership.annotations, 1)
+
+	owned, err = client.isOwned(context.Background(), ownedTeam, 4)
+	req.NoError(err)
+	req.True(owned)
+
+	owned, err = client.isOwned(context.Background(), ownedUser, 4)
+	req.NoError(err)
+	req.False(owned)
+
+	req.NoError(client.forgetOwned(context.Background(), ownedTeam, 4))
+	req.Empty(ownership.annotations)
+}
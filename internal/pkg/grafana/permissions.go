@@ -0,0 +1,81 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+)
+
+var ErrInvalidPermission = fmt.Errorf("invalid permission")
+
+type permission struct {
+	Role       string `json:"role,omitempty"`
+	TeamID     int64  `json:"teamId,omitempty"`
+	Permission int    `json:"permission"`
+}
+
+var permissionLevels = map[string]int{
+	"view":  1,
+	"edit":  2,
+	"admin": 4,
+}
+
+// resolvePermissions converts permissions, as described in manifests, into
+// their Grafana representation.
+// Team references are expected to be resolved into team names beforehand.
+func (client *Client) resolvePermissions(ctx context.Context, specPermissions []v1alpha1.Permission) ([]permission, error) {
+	permissions := make([]permission, 0, len(specPermissions))
+
+	for _, spec := range specPermissions {
+		level, ok := permissionLevels[spec.Permission]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown permission '%s'", ErrInvalidPermission, spec.Permission)
+		}
+
+		resolved := permission{Permission: level}
+
+		switch {
+		case spec.TeamRef != nil:
+			return nil, fmt.Errorf("%w: unresolved reference to team %s", ErrInvalidPermission, spec.TeamRef.Name)
+		case spec.Team != "" && spec.Role != "":
+			return nil, fmt.Errorf("%w: only one of team and role may be specified", ErrInvalidPermission)
+		case spec.Team != "":
+			team, err := client.teamByName(ctx, spec.Team)
+			if err != nil {
+				return nil, err
+			}
+
+			resolved.TeamID = team.ID
+		case spec.Role != "":
+			resolved.Role = spec.Role
+		default:
+			return nil, fmt.Errorf("%w: team or role required", ErrInvalidPermission)
+		}
+
+		permissions = append(permissions, resolved)
+	}
+
+	return permissions, nil
+}
+
+// setPermissions replaces the permissions of a folder or a dashboard.
+func (client *Client) setPermissions(ctx context.Context, path string, permissions []permission) error {
+	resp, err := client.sendJSON(ctx, http.MethodPost, path, struct {
+		Items []permission `json:"items"`
+	}{
+		Items: permissions,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return client.httpError(resp)
+	}
+
+	return nil
+}
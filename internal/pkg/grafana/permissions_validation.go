@@ -0,0 +1,44 @@
+package grafana
+
+import (
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidatePermissions statically checks permissions on a folder or a
+// dashboard.
+func ValidatePermissions(path *field.Path, permissions []v1alpha1.Permission) field.ErrorList {
+	var errs field.ErrorList
+
+	for i, permission := range permissions {
+		permissionPath := path.Index(i)
+
+		targets := 0
+		if permission.Team != "" {
+			targets++
+		}
+		if permission.TeamRef != nil {
+			targets++
+
+			if permission.TeamRef.Name == "" {
+				errs = append(errs, field.Required(permissionPath.Child("teamRef", "name"), "a team name is required"))
+			}
+		}
+		if permission.Role != "" {
+			targets++
+		}
+
+		switch {
+		case targets > 1:
+			errs = append(errs, field.Forbidden(permissionPath, "only one of team, teamRef and role may be specified"))
+		case targets == 0:
+			errs = append(errs, field.Required(permissionPath, "one of team, teamRef or role is required"))
+		}
+
+		if _, ok := permissionLevels[permission.Permission]; !ok {
+			errs = append(errs, field.NotSupported(permissionPath.Child("permission"), permission.Permission, []string{"view", "edit", "admin"}))
+		}
+	}
+
+	return errs
+}
@@ -19,7 +19,12 @@ func (datasources *Datasources) prometheusSpecToModel(ctx context.Context, objec
 		return nil, err
 	}
 
-	return prometheus.New(objectRef.Name, ds.URL, opts...)
+	url, err := datasources.url(ctx, objectRef.Namespace, ds.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	return prometheus.New(objectRef.Name, url, opts...)
 }
 
 func (datasources *Datasources) prometheusSpecToOptions(ctx context.Context, objectRef types.NamespacedName, spec *v1alpha1.PrometheusDatasource) ([]prometheus.Option, error) {
@@ -83,7 +88,7 @@ func (datasources *Datasources) prometheusSpecToOptions(ctx context.Context, obj
 		opts = append(opts, prometheus.WithCertificate(caCertificate))
 	}
 	if len(spec.Exemplars) != 0 {
-		exemplars, err := datasources.prometheusExemplars(ctx, spec.Exemplars)
+		exemplars, err := datasources.prometheusExemplars(ctx, objectRef.Namespace, spec.Exemplars)
 		if err != nil {
 			return nil, fmt.Errorf("could not extract prometheus exemplars: %w", err)
 		}
@@ -94,7 +99,7 @@ func (datasources *Datasources) prometheusSpecToOptions(ctx context.Context, obj
 	return opts, nil
 }
 
-func (datasources *Datasources) prometheusExemplars(ctx context.Context, specExemplars []v1alpha1.PrometheusExemplar) ([]prometheus.Exemplar, error) {
+func (datasources *Datasources) prometheusExemplars(ctx context.Context, namespace string, specExemplars []v1alpha1.PrometheusExemplar) ([]prometheus.Exemplar, error) {
 	exemplars := make([]prometheus.Exemplar, 0, len(specExemplars))
 
 	for _, specExemplar := range specExemplars {
@@ -106,7 +111,7 @@ func (datasources *Datasources) prometheusExemplars(ctx context.Context, specExe
 		if exemplar.URL != "" {
 			exemplar.URL = specExemplar.URL
 		} else if exemplar.DatasourceUID != "" {
-			datasourceUID, err := datasources.datasourceUIDFromRef(ctx, specExemplar.Datasource)
+			datasourceUID, err := datasources.datasourceUIDFromRef(ctx, namespace, specExemplar.Datasource)
 			if err != nil {
 				return nil, fmt.Errorf("could not infer datasource UID from reference: %w", err)
 			}
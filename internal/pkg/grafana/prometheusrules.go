@@ -0,0 +1,155 @@
+package grafana
+
+import (
+	"fmt"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// PrometheusRuleGVK identifies the PrometheusRule resource of the
+// prometheus-operator.
+var PrometheusRuleGVK = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "PrometheusRule",
+}
+
+// PrometheusRulesImport configures how PrometheusRule objects are imported as
+// Grafana alert rule groups.
+type PrometheusRulesImport struct {
+	// Datasource designates the Prometheus Datasource manifest queried by the
+	// imported rules.
+	Datasource types.NamespacedName
+	// Folder holding the imported rule groups. Defaults to the namespace of
+	// the PrometheusRule.
+	Folder string
+}
+
+// prometheusRuleSpec is the subset of the spec of a PrometheusRule relevant
+// to alerting rules.
+type prometheusRuleSpec struct {
+	Groups []prometheusRuleGroup `json:"groups"`
+}
+
+type prometheusRuleGroup struct {
+	Name     string           `json:"name"`
+	Interval string           `json:"interval,omitempty"`
+	Rules    []prometheusRule `json:"rules"`
+}
+
+type prometheusRule struct {
+	Alert       string             `json:"alert,omitempty"`
+	Expr        intstr.IntOrString `json:"expr"`
+	For         string             `json:"for,omitempty"`
+	Labels      map[string]string  `json:"labels,omitempty"`
+	Annotations map[string]string  `json:"annotations,omitempty"`
+}
+
+// PrometheusRuleGroupName returns the name of the Grafana rule group imported
+// from a group of a PrometheusRule. The namespace of the PrometheusRule is
+// part of it, so that PrometheusRules with the same name don't replace each
+// other's groups when imported in the same folder.
+func PrometheusRuleGroupName(object metav1.Object, groupName string) string {
+	return fmt.Sprintf("%s - %s - %s", object.GetNamespace(), object.GetName(), groupName)
+}
+
+// PrometheusRuleToAlertRuleGroups converts the alerting rules of a
+// PrometheusRule into Grafana alert rule groups. Recording rules are ignored.
+//
+// An imported rule fires for each series its expression currently returns.
+func PrometheusRuleToAlertRuleGroups(object *unstructured.Unstructured, options PrometheusRulesImport) ([]AlertRuleGroup, error) {
+	rawSpec, _, err := unstructured.NestedMap(object.Object, "spec")
+	if err != nil {
+		return nil, err
+	}
+
+	spec := prometheusRuleSpec{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawSpec, &spec); err != nil {
+		return nil, fmt.Errorf("could not decode PrometheusRule spec: %w", err)
+	}
+
+	folder := options.Folder
+	if folder == "" {
+		folder = object.GetNamespace()
+	}
+
+	groups := make([]AlertRuleGroup, 0, len(spec.Groups))
+	for _, group := range spec.Groups {
+		interval := group.Interval
+		if interval == "" {
+			interval = defaultAlertRuleGroupInterval
+		}
+
+		rules := prometheusAlertingRules(group.Rules)
+		if len(rules) == 0 {
+			continue
+		}
+
+		groups = append(groups, AlertRuleGroup{
+			Name:      PrometheusRuleGroupName(object, group.Name),
+			Namespace: options.Datasource.Namespace,
+			Folder:    DashboardFolder{Title: folder},
+			Spec: v1alpha1.GrafanaAlertRuleGroupSpec{
+				Folder:     folder,
+				Interval:   interval,
+				Datasource: v1alpha1.ValueOrDatasourceRef{Name: options.Datasource.Name},
+				Rules:      rules,
+			},
+			PerSeries: true,
+		})
+	}
+
+	return groups, nil
+}
+
+// prometheusRuleLookback is the time range queried by imported rules: a single
+// step of the query. Like Prometheus evaluating the expression at a single
+// instant, only the series it currently returns are counted, not the ones it
+// returned earlier in the evaluation interval.
+const prometheusRuleLookback = "15s"
+
+func prometheusAlertingRules(rules []prometheusRule) []v1alpha1.AlertRule {
+	zero := v1alpha1.Number("0")
+
+	var alertRules []v1alpha1.AlertRule
+	titles := make(map[string]int)
+	for _, rule := range rules {
+		if rule.Alert == "" {
+			continue
+		}
+
+		// Prometheus allows several rules with the same name, Grafana doesn't
+		title := rule.Alert
+		titles[rule.Alert]++
+		if titles[rule.Alert] > 1 {
+			title = fmt.Sprintf("%s (%d)", rule.Alert, titles[rule.Alert])
+		}
+
+		forDuration := rule.For
+		if forDuration == "" {
+			forDuration = "0s"
+		}
+
+		alertRules = append(alertRules, v1alpha1.AlertRule{
+			Title:       title,
+			For:         forDuration,
+			OnNoData:    "ok",
+			Labels:      rule.Labels,
+			Annotations: rule.Annotations,
+			If: []v1alpha1.AlertCondition{
+				{Count: "A", Above: &zero},
+			},
+			Targets: []v1alpha1.AlertTarget{
+				{Prometheus: &v1alpha1.AlertQuery{Ref: "A", Query: rule.Expr.String(), Lookback: prometheusRuleLookback}},
+			},
+		})
+	}
+
+	return alertRules
+}
@@ -0,0 +1,117 @@
+package grafana
+
+import (
+	"testing"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func prometheusRuleObject(spec map[string]interface{}) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	object.SetGroupVersionKind(PrometheusRuleGVK)
+	object.SetNamespace("api")
+	object.SetName("api-rules")
+
+	return object
+}
+
+func TestPrometheusRuleToAlertRuleGroups(t *testing.T) {
+	req := require.New(t)
+
+	object := prometheusRuleObject(map[string]interface{}{
+		"groups": []interface{}{
+			map[string]interface{}{
+				"name":     "api.rules",
+				"interval": "30s",
+				"rules": []interface{}{
+					map[string]interface{}{
+						"record": "job:http_requests:rate5m",
+						"expr":   "sum by (job) (rate(http_requests_total[5m]))",
+					},
+					map[string]interface{}{
+						"alert":       "APIDown",
+						"expr":        `up{job="api"} == 0`,
+						"for":         "5m",
+						"labels":      map[string]interface{}{"severity": "critical"},
+						"annotations": map[string]interface{}{"summary": "The API is down"},
+					},
+					map[string]interface{}{
+						"alert": "APIDown",
+						"expr":  `absent(up{job="api"})`,
+					},
+				},
+			},
+			map[string]interface{}{
+				"name": "recording.rules",
+				"rules": []interface{}{
+					map[string]interface{}{"record": "job:up:sum", "expr": "sum by (job) (up)"},
+				},
+			},
+		},
+	})
+
+	groups, err := PrometheusRuleToAlertRuleGroups(object, PrometheusRulesImport{
+		Datasource: types.NamespacedName{Namespace: "monitoring", Name: "prometheus"},
+	})
+	req.NoError(err)
+
+	// groups holding only recording rules are ignored
+	req.Len(groups, 1)
+
+	group := groups[0]
+	req.Equal("api - api-rules - api.rules", group.Name)
+	req.Equal("monitoring", group.Namespace)
+	req.Equal(DashboardFolder{Title: "api"}, group.Folder)
+	req.Equal("30s", group.Spec.Interval)
+	req.Equal(v1alpha1.ValueOrDatasourceRef{Name: "prometheus"}, group.Spec.Datasource)
+
+	req.Len(group.Spec.Rules, 2)
+	req.Equal("APIDown", group.Spec.Rules[0].Title)
+	req.Equal("5m", group.Spec.Rules[0].For)
+	req.Equal(map[string]string{"severity": "critical"}, group.Spec.Rules[0].Labels)
+	req.Equal(map[string]string{"summary": "The API is down"}, group.Spec.Rules[0].Annotations)
+	req.Equal(`up{job="api"} == 0`, group.Spec.Rules[0].Targets[0].Prometheus.Query)
+	// only the series currently returned by the expression fire, not the ones
+	// returned earlier in the evaluation interval
+	req.Equal("15s", group.Spec.Rules[0].Targets[0].Prometheus.Lookback)
+
+	req.Equal("APIDown (2)", group.Spec.Rules[1].Title)
+	req.Equal("0s", group.Spec.Rules[1].For)
+
+	// the rules must be accepted by Grafana's model, and evaluated per series
+	req.True(group.PerSeries)
+
+	model, err := SpecToAlertRuleGroup(group.Name, group.Spec)
+	req.NoError(err)
+
+	_, err = toPerSeriesRuleGroup(*model.Builder)
+	req.NoError(err)
+}
+
+func TestPrometheusRuleToAlertRuleGroupsUsesTheConfiguredFolder(t *testing.T) {
+	req := require.New(t)
+
+	object := prometheusRuleObject(map[string]interface{}{
+		"groups": []interface{}{
+			map[string]interface{}{
+				"name": "api.rules",
+				"rules": []interface{}{
+					map[string]interface{}{"alert": "APIDown", "expr": "up == 0"},
+				},
+			},
+		},
+	})
+
+	groups, err := PrometheusRuleToAlertRuleGroups(object, PrometheusRulesImport{
+		Datasource: types.NamespacedName{Namespace: "monitoring", Name: "prometheus"},
+		Folder:     "Imported rules",
+	})
+	req.NoError(err)
+
+	req.Len(groups, 1)
+	req.Equal(DashboardFolder{Title: "Imported rules"}, groups[0].Folder)
+	req.Equal(defaultAlertRuleGroupInterval, groups[0].Spec.Interval)
+}
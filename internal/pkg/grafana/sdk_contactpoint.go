@@ -0,0 +1,51 @@
+package grafana
+
+import (
+	"github.com/K-Phoen/grabana/alertmanager"
+	"github.com/K-Phoen/sdk"
+)
+
+// sdkContactPointType describes a contact point type not supported by
+// grabana, as represented by Grafana's API.
+type sdkContactPointType struct {
+	builder *sdk.ContactPointType
+}
+
+func newSDKContactPointType(contactPointType string) sdkContactPointType {
+	return sdkContactPointType{
+		builder: &sdk.ContactPointType{
+			Type:           contactPointType,
+			Settings:       map[string]interface{}{},
+			SecureSettings: map[string]interface{}{},
+		},
+	}
+}
+
+// setIfNotEmpty sets a setting, unless the given value is empty.
+func (contactType sdkContactPointType) setIfNotEmpty(field string, value string) {
+	if value != "" {
+		contactType.builder.Settings[field] = value
+	}
+}
+
+func (contactType sdkContactPointType) option() alertmanager.ContactPointOption {
+	return func(contact *alertmanager.Contact) {
+		contact.Builder.GrafanaManagedReceivers = append(contact.Builder.GrafanaManagedReceivers, *contactType.builder)
+	}
+}
+
+// extend returns an option applying the given contact point type, built by
+// grabana, completed with the settings of this one.
+func (contactType sdkContactPointType) extend(opt alertmanager.ContactPointOption) alertmanager.ContactPointOption {
+	return func(contact *alertmanager.Contact) {
+		opt(contact)
+
+		receiver := contact.Builder.GrafanaManagedReceivers[len(contact.Builder.GrafanaManagedReceivers)-1]
+		for field, value := range contactType.builder.Settings {
+			receiver.Settings[field] = value
+		}
+		for field, value := range contactType.builder.SecureSettings {
+			receiver.SecureSettings[field] = value
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/grabana/datasource"
+	"github.com/K-Phoen/sdk"
+)
+
+var _ datasource.Datasource = sdkDatasource{}
+
+// sdkDatasource describes a datasource not supported by grabana, as
+// represented by Grafana's API.
+type sdkDatasource struct {
+	builder *sdk.Datasource
+}
+
+func newSDKDatasource(name string, datasourceType string, url string) sdkDatasource {
+	return sdkDatasource{
+		builder: &sdk.Datasource{
+			Name:           name,
+			Type:           datasourceType,
+			Access:         "proxy",
+			URL:            url,
+			JSONData:       map[string]interface{}{},
+			SecureJSONData: map[string]interface{}{},
+		},
+	}
+}
+
+func (ds sdkDatasource) Name() string {
+	return ds.builder.Name
+}
+
+func (ds sdkDatasource) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ds.builder)
+}
+
+func (ds sdkDatasource) jsonData() map[string]interface{} {
+	return ds.builder.JSONData.(map[string]interface{})
+}
+
+func (ds sdkDatasource) secureJSONData() map[string]interface{} {
+	return ds.builder.SecureJSONData.(map[string]interface{})
+}
+
+// setIfNotEmpty sets a jsonData field, unless the given value is empty.
+func (ds sdkDatasource) setIfNotEmpty(field string, value string) {
+	if value != "" {
+		ds.jsonData()[field] = value
+	}
+}
+
+// extendedDatasource completes a datasource built by grabana with the jsonData
+// fields it doesn't support.
+type extendedDatasource struct {
+	datasource.Datasource
+
+	jsonData map[string]interface{}
+}
+
+func (ds extendedDatasource) MarshalJSON() ([]byte, error) {
+	buf, err := ds.Datasource.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	model := sdk.Datasource{}
+	if err := json.Unmarshal(buf, &model); err != nil {
+		return nil, err
+	}
+
+	jsonData, ok := model.JSONData.(map[string]interface{})
+	if !ok {
+		jsonData = map[string]interface{}{}
+	}
+	for field, value := range ds.jsonData {
+		jsonData[field] = value
+	}
+	model.JSONData = jsonData
+
+	return json.Marshal(model)
+}
+
+// httpSettings holds the settings shared by HTTP-based datasources.
+type httpSettings struct {
+	Default       *bool
+	SkipTLSVerify *bool
+	Timeout       string
+	BasicAuth     *v1alpha1.BasicAuth
+	CACertificate *v1alpha1.ValueOrRef
+}
+
+func (datasources *Datasources) applyHTTPSettings(ctx context.Context, namespace string, ds sdkDatasource, settings httpSettings) error {
+	if settings.Default != nil && *settings.Default {
+		ds.builder.IsDefault = true
+	}
+	if settings.SkipTLSVerify != nil && *settings.SkipTLSVerify {
+		ds.jsonData()["tlsSkipVerify"] = true
+	}
+	if settings.Timeout != "" {
+		timeout, err := time.ParseDuration(settings.Timeout)
+		if err != nil {
+			return fmt.Errorf("could not parse timeout: %w", err)
+		}
+
+		ds.jsonData()["timeout"] = int(timeout.Seconds())
+	}
+	if settings.BasicAuth != nil {
+		username, password, err := datasources.basicAuthCredentials(ctx, namespace, settings.BasicAuth)
+		if err != nil {
+			return err
+		}
+
+		yep := true
+		ds.builder.BasicAuth = &yep
+		ds.builder.BasicAuthUser = &username
+		ds.secureJSONData()["basicAuthPassword"] = password
+	}
+
+	return datasources.applyCACertificate(ctx, namespace, ds, settings.CACertificate)
+}
+
+func (datasources *Datasources) applyCACertificate(ctx context.Context, namespace string, ds sdkDatasource, ref *v1alpha1.ValueOrRef) error {
+	if ref == nil {
+		return nil
+	}
+
+	caCertificate, err := datasources.refReader.RefToValue(ctx, namespace, *ref)
+	if err != nil {
+		return fmt.Errorf("could not extract CA certificate: %w", err)
+	}
+
+	ds.jsonData()["tlsSkipVerify"] = false
+	ds.jsonData()["tlsAuthWithCACert"] = true
+	ds.secureJSONData()["tlsCACert"] = caCertificate
+
+	return nil
+}
@@ -0,0 +1,335 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var ErrServiceAccountNotFound = fmt.Errorf("service account not found")
+
+// migratedServiceAccountPrefix prefixes the name of the service accounts
+// created by Grafana when migrating legacy API keys.
+const migratedServiceAccountPrefix = "sa-autogen-"
+
+type serviceAccount struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+type serviceAccountToken struct {
+	ID            int64  `json:"id,omitempty"`
+	Name          string `json:"name"`
+	Key           string `json:"key,omitempty"`
+	SecondsToLive int64  `json:"secondsToLive,omitempty"`
+	Expired       bool   `json:"hasExpired,omitempty"`
+}
+
+// serviceAccountStore manages the tokens of a service account.
+type serviceAccountStore struct {
+	grafanaClient *Client
+	accountID     int64
+}
+
+// serviceAccountStore makes sure that a service account named after the key
+// exists with the right role, and returns a store for its tokens.
+// The legacy API key currently in use, if any, is migrated to a service account.
+func (keys *APIKeys) serviceAccountStore(ctx context.Context, key APIKey, state APIKeyState) (*serviceAccountStore, error) {
+	role, err := key.ServiceAccountRole()
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := keys.findOrMigrateServiceAccount(ctx, key, state)
+	if err != nil && err != ErrServiceAccountNotFound {
+		return nil, err
+	}
+
+	if account == nil {
+		keys.logger.Info("creating service account", "key", key.Name)
+
+		account, err = keys.grafanaClient.createServiceAccount(ctx, serviceAccount{Name: key.Name, Role: role})
+		if err != nil {
+			return nil, err
+		}
+	} else if account.Name != key.Name || account.Role != role {
+		if err := keys.grafanaClient.updateServiceAccount(ctx, serviceAccount{ID: account.ID, Name: key.Name, Role: role}); err != nil {
+			return nil, err
+		}
+	}
+
+	return &serviceAccountStore{grafanaClient: keys.grafanaClient, accountID: account.ID}, nil
+}
+
+func (store *serviceAccountStore) exists(ctx context.Context, name string) (bool, error) {
+	token, err := store.tokenByName(ctx, name)
+	if err != nil {
+		return false, err
+	}
+
+	return token != nil, nil
+}
+
+func (store *serviceAccountStore) create(ctx context.Context, name string, secondsToLive int64) (string, error) {
+	token, err := store.grafanaClient.createServiceAccountToken(ctx, store.accountID, serviceAccountToken{
+		Name:          name,
+		SecondsToLive: secondsToLive,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token.Key, nil
+}
+
+// revoke deletes the token with the given name. Keys issued before switching
+// to service accounts, and not migrated, are revoked as legacy API keys.
+func (store *serviceAccountStore) revoke(ctx context.Context, name string) error {
+	token, err := store.tokenByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		legacyKeys := &legacyKeyStore{grafanaClient: store.grafanaClient}
+
+		return legacyKeys.revoke(ctx, name)
+	}
+
+	return store.grafanaClient.deleteServiceAccountToken(ctx, store.accountID, token.ID)
+}
+
+func (store *serviceAccountStore) tokenByName(ctx context.Context, name string) (*serviceAccountToken, error) {
+	tokens, err := store.grafanaClient.serviceAccountTokens(ctx, store.accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, token := range tokens {
+		if token.Name == name && !token.Expired {
+			return &token, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// findOrMigrateServiceAccount looks for the service account of the given key.
+// If it doesn't exist but the legacy API key currently in use does, the API
+// key is migrated to a service account: its token remains valid. Rotated keys
+// are named after the rotation time, and recorded in the state.
+func (keys *APIKeys) findOrMigrateServiceAccount(ctx context.Context, key APIKey, state APIKeyState) (*serviceAccount, error) {
+	account, err := keys.grafanaClient.serviceAccountByName(ctx, key.Name)
+	if err != ErrServiceAccountNotFound {
+		return account, err
+	}
+
+	legacyKeys, err := keys.grafanaClient.APIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// keys created before their state was tracked are named after the APIKey
+	current := state.Current
+	if current == "" {
+		current = key.Name
+	}
+
+	legacyKey, ok := legacyKeys[current]
+	if !ok {
+		return nil, ErrServiceAccountNotFound
+	}
+
+	keys.logger.Info("migrating API key to service account", "key", current)
+
+	if err := keys.grafanaClient.migrateAPIKey(ctx, int64(legacyKey.ID)); err != nil {
+		return nil, err
+	}
+
+	return keys.grafanaClient.migratedServiceAccount(ctx, current)
+}
+
+func (keys *APIKeys) deleteServiceAccount(ctx context.Context, name string) error {
+	account, err := keys.grafanaClient.serviceAccountByName(ctx, name)
+	if err == ErrServiceAccountNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	resp, err := keys.grafanaClient.delete(ctx, "/api/serviceaccounts/"+strconv.FormatInt(account.ID, 10))
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return keys.grafanaClient.httpError(resp)
+	}
+
+	return nil
+}
+
+func (client *Client) searchServiceAccounts(ctx context.Context, query string) ([]serviceAccount, error) {
+	resp, err := client.get(ctx, "/api/serviceaccounts/search?perpage=100&query="+url.QueryEscape(query))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, client.httpError(resp)
+	}
+
+	var response struct {
+		ServiceAccounts []serviceAccount `json:"serviceAccounts"`
+	}
+	if err := decodeJSON(resp.Body, &response); err != nil {
+		return nil, err
+	}
+
+	return response.ServiceAccounts, nil
+}
+
+func (client *Client) serviceAccountByName(ctx context.Context, name string) (*serviceAccount, error) {
+	accounts, err := client.searchServiceAccounts(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, account := range accounts {
+		if account.Name == name {
+			return &account, nil
+		}
+	}
+
+	return nil, ErrServiceAccountNotFound
+}
+
+// migratedServiceAccount finds the service account created by Grafana when
+// migrating the API key with the given name.
+func (client *Client) migratedServiceAccount(ctx context.Context, keyName string) (*serviceAccount, error) {
+	accounts, err := client.searchServiceAccounts(ctx, keyName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, account := range accounts {
+		if strings.HasPrefix(account.Name, migratedServiceAccountPrefix) && strings.HasSuffix(account.Name, "-"+keyName) {
+			return &account, nil
+		}
+	}
+
+	return nil, ErrServiceAccountNotFound
+}
+
+func (client *Client) createServiceAccount(ctx context.Context, account serviceAccount) (*serviceAccount, error) {
+	resp, err := client.sendJSON(ctx, http.MethodPost, "/api/serviceaccounts", account)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, client.httpError(resp)
+	}
+
+	var created serviceAccount
+	if err := decodeJSON(resp.Body, &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (client *Client) updateServiceAccount(ctx context.Context, account serviceAccount) error {
+	resp, err := client.sendJSON(ctx, http.MethodPatch, "/api/serviceaccounts/"+strconv.FormatInt(account.ID, 10), account)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return client.httpError(resp)
+	}
+
+	return nil
+}
+
+func (client *Client) serviceAccountTokens(ctx context.Context, accountID int64) ([]serviceAccountToken, error) {
+	resp, err := client.get(ctx, "/api/serviceaccounts/"+strconv.FormatInt(accountID, 10)+"/tokens")
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, client.httpError(resp)
+	}
+
+	var tokens []serviceAccountToken
+	if err := decodeJSON(resp.Body, &tokens); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (client *Client) createServiceAccountToken(ctx context.Context, accountID int64, token serviceAccountToken) (*serviceAccountToken, error) {
+	resp, err := client.sendJSON(ctx, http.MethodPost, "/api/serviceaccounts/"+strconv.FormatInt(accountID, 10)+"/tokens", token)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, client.httpError(resp)
+	}
+
+	var created serviceAccountToken
+	if err := decodeJSON(resp.Body, &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (client *Client) deleteServiceAccountToken(ctx context.Context, accountID int64, tokenID int64) error {
+	resp, err := client.delete(ctx, "/api/serviceaccounts/"+strconv.FormatInt(accountID, 10)+"/tokens/"+strconv.FormatInt(tokenID, 10))
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return client.httpError(resp)
+	}
+
+	return nil
+}
+
+func (client *Client) migrateAPIKey(ctx context.Context, keyID int64) error {
+	resp, err := client.sendJSON(ctx, http.MethodPost, "/api/serviceaccounts/migrate/"+strconv.FormatInt(keyID, 10), struct{}{})
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return client.httpError(resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,230 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/K-Phoen/dark/internal/pkg/kubernetes"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+)
+
+type inMemorySecrets struct {
+	secrets     map[string]map[string][]byte
+	annotations map[string]map[string]string
+}
+
+func (secrets *inMemorySecrets) Read(_ context.Context, namespace string, ref v1.SecretKeySelector) (string, error) {
+	secret, ok := secrets.secrets[namespace+"/"+ref.Name]
+	if !ok {
+		return "", kubernetes.ErrSecretNotFound
+	}
+
+	value, ok := secret[ref.Key]
+	if !ok {
+		return "", kubernetes.ErrKeyNotFoundInSecret
+	}
+
+	return string(value), nil
+}
+
+func (secrets *inMemorySecrets) Annotations(_ context.Context, namespace string, name string) (map[string]string, error) {
+	if _, ok := secrets.secrets[namespace+"/"+name]; !ok {
+		return nil, kubernetes.ErrSecretNotFound
+	}
+
+	return secrets.annotations[namespace+"/"+name], nil
+}
+
+func (secrets *inMemorySecrets) Upsert(_ context.Context, request kubernetes.SecretUpsertRequest) error {
+	secrets.secrets[request.Namespace+"/"+request.Name] = request.Data
+	if secrets.annotations == nil {
+		secrets.annotations = map[string]map[string]string{}
+	}
+	secrets.annotations[request.Namespace+"/"+request.Name] = request.Annotations
+
+	return nil
+}
+
+func testServiceAccountKey() APIKey {
+	return APIKey{
+		Name:            "ci",
+		Role:            "editor",
+		Mode:            ServiceAccountMode,
+		SecretName:      "ci",
+		SecretNamespace: "apps",
+		TokenKey:        "token",
+	}
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, body interface{}) {
+	t.Helper()
+
+	w.Header().Set("Content-Type", "application/json")
+	require.NoError(t, json.NewEncoder(w).Encode(body))
+}
+
+func TestReconcileServiceAccountCreatesAccountAndToken(t *testing.T) {
+	req := require.New(t)
+
+	var created []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/serviceaccounts/search":
+			writeJSON(t, w, map[string]interface{}{"serviceAccounts": []serviceAccount{}})
+		case "GET /api/auth/keys":
+			writeJSON(t, w, []interface{}{})
+		case "POST /api/serviceaccounts":
+			created = append(created, "account")
+			writeJSON(t, w, serviceAccount{ID: 3, Name: "ci", Role: "Editor"})
+		case "GET /api/serviceaccounts/3/tokens":
+			writeJSON(t, w, []serviceAccountToken{})
+		case "POST /api/serviceaccounts/3/tokens":
+			created = append(created, "token")
+			writeJSON(t, w, serviceAccountToken{ID: 7, Name: "ci", Key: "glsa_secret"})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{Host: server.URL})
+	req.NoError(err)
+
+	secrets := &inMemorySecrets{secrets: map[string]map[string][]byte{}}
+	keys := NewAPIKeys(logr.Discard(), client, secrets)
+
+	state, err := keys.Reconcile(context.Background(), testServiceAccountKey(), APIKeyState{})
+	req.NoError(err)
+
+	req.Equal([]string{"account", "token"}, created)
+	req.Equal("ci", state.Current)
+	req.Equal("glsa_secret", string(secrets.secrets["apps/ci"]["token"]))
+}
+
+func TestReconcileServiceAccountMigratesLegacyKeys(t *testing.T) {
+	req := require.New(t)
+
+	migrated := false
+	renamed := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/serviceaccounts/search":
+			accounts := []serviceAccount{}
+			if migrated {
+				accounts = append(accounts, serviceAccount{ID: 4, Name: "sa-autogen-1-ci", Role: "Editor"})
+			}
+			writeJSON(t, w, map[string]interface{}{"serviceAccounts": accounts})
+		case "GET /api/auth/keys":
+			writeJSON(t, w, []map[string]interface{}{{"id": 12, "name": "ci"}})
+		case "POST /api/serviceaccounts/migrate/12":
+			migrated = true
+			writeJSON(t, w, map[string]string{"message": "API Key migrated to service account"})
+		case "PATCH /api/serviceaccounts/4":
+			renamed = true
+			writeJSON(t, w, map[string]string{"message": "Service account updated"})
+		case "GET /api/serviceaccounts/4/tokens":
+			writeJSON(t, w, []serviceAccountToken{{ID: 12, Name: "ci"}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{Host: server.URL})
+	req.NoError(err)
+
+	secrets := &inMemorySecrets{secrets: map[string]map[string][]byte{
+		"apps/ci": {"token": []byte("eyJrIjoi")},
+	}}
+	keys := NewAPIKeys(logr.Discard(), client, secrets)
+
+	_, err = keys.Reconcile(context.Background(), testServiceAccountKey(), APIKeyState{})
+	req.NoError(err)
+
+	req.True(migrated)
+	req.True(renamed)
+	// the migrated key is still valid: the secret is left untouched
+	req.Equal("eyJrIjoi", string(secrets.secrets["apps/ci"]["token"]))
+}
+
+func TestReconcileServiceAccountMigratesRotatedLegacyKeys(t *testing.T) {
+	req := require.New(t)
+
+	legacyKeys := newFakeLegacyKeys("ci-20230201000000", "ci-20230101000000")
+	migrated := false
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/serviceaccounts/search":
+			accounts := []serviceAccount{}
+			if migrated {
+				accounts = append(accounts, serviceAccount{ID: 4, Name: "sa-autogen-1-ci-20230201000000", Role: "Editor"})
+			}
+			writeJSON(t, w, map[string]interface{}{"serviceAccounts": accounts})
+		case "POST /api/serviceaccounts/migrate/1":
+			migrated = true
+			writeJSON(t, w, map[string]string{"message": "API Key migrated to service account"})
+		case "PATCH /api/serviceaccounts/4":
+			writeJSON(t, w, map[string]string{"message": "Service account updated"})
+		case "GET /api/serviceaccounts/4/tokens":
+			writeJSON(t, w, []serviceAccountToken{{ID: 1, Name: "ci-20230201000000"}})
+		default:
+			legacyKeys.ServeHTTP(w, r)
+		}
+	})
+
+	secrets := &inMemorySecrets{secrets: map[string]map[string][]byte{
+		"apps/ci": {"token": []byte("token-ci-20230201000000")},
+	}}
+	now := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	keys := testAPIKeys(t, handler, secrets, now)
+
+	state, err := keys.Reconcile(context.Background(), testServiceAccountKey(), APIKeyState{
+		Current:          "ci-20230201000000",
+		Previous:         "ci-20230101000000",
+		RevokePreviousAt: now.Add(-time.Hour),
+		TokenHash:        tokenHash("token-ci-20230201000000"),
+	})
+	req.NoError(err)
+
+	req.True(migrated)
+	req.Equal("ci-20230201000000", state.Current)
+	req.Empty(state.Previous)
+	// the previous key was not migrated: it is revoked as a legacy key
+	req.NotContains(legacyKeys.names(), "ci-20230101000000")
+	req.Equal("token-ci-20230201000000", string(secrets.secrets["apps/ci"]["token"]))
+}
+
+func TestDeleteServiceAccountRevokesLegacyKeys(t *testing.T) {
+	req := require.New(t)
+
+	legacyKeys := newFakeLegacyKeys("ci-20230201000000", "ci-20230101000000", "unrelated")
+	deleted := false
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/serviceaccounts/search":
+			writeJSON(t, w, map[string]interface{}{"serviceAccounts": []serviceAccount{{ID: 4, Name: "ci", Role: "Editor"}}})
+		case "DELETE /api/serviceaccounts/4":
+			deleted = true
+			writeJSON(t, w, map[string]string{"message": "Service account deleted"})
+		default:
+			legacyKeys.ServeHTTP(w, r)
+		}
+	})
+
+	keys := testAPIKeys(t, handler, &inMemorySecrets{secrets: map[string]map[string][]byte{}}, time.Now())
+
+	err := keys.Delete(context.Background(), testServiceAccountKey(), APIKeyState{
+		Current:  "ci-20230201000000",
+		Previous: "ci-20230101000000",
+	})
+	req.NoError(err)
+
+	req.True(deleted)
+	req.ElementsMatch([]string{"unrelated"}, legacyKeys.names())
+}
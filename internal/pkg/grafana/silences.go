@@ -0,0 +1,222 @@
+package grafana
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/go-logr/logr"
+)
+
+var ErrSilenceNotFound = fmt.Errorf("silence not found")
+
+const defaultSilenceAuthor = "DARK"
+
+// silence describes a silence, as represented by Grafana's alert manager API.
+type silence struct {
+	ID        string           `json:"id,omitempty"`
+	Matchers  []silenceMatcher `json:"matchers"`
+	StartsAt  time.Time        `json:"startsAt"`
+	EndsAt    time.Time        `json:"endsAt"`
+	CreatedBy string           `json:"createdBy"`
+	Comment   string           `json:"comment"`
+	Status    *silenceStatus   `json:"status,omitempty"`
+}
+
+type silenceMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+type silenceStatus struct {
+	State string `json:"state"`
+}
+
+func (s *silence) expired() bool {
+	return s.Status != nil && s.Status.State == "expired"
+}
+
+type Silences struct {
+	logger        logr.Logger
+	grafanaClient *Client
+	now           func() time.Time
+}
+
+func NewSilences(logger logr.Logger, grafanaClient *Client) *Silences {
+	return &Silences{
+		logger:        logger,
+		grafanaClient: grafanaClient,
+		now:           time.Now,
+	}
+}
+
+// Upsert creates or updates a silence, given the ID it had in Grafana if it
+// was already created. It returns the ID of the silence, which changes
+// whenever Grafana can't update the silence in place.
+// Silences that are over are expired, and an empty ID is returned.
+func (silences *Silences) Upsert(ctx context.Context, id string, spec v1alpha1.GrafanaSilenceSpec) (string, error) {
+	silences.logger.Info("upserting silence", "id", id)
+
+	var existing *silence
+	if id != "" {
+		var err error
+		existing, err = silences.grafanaClient.silenceByID(ctx, id)
+		if err != nil && !errors.Is(err, ErrSilenceNotFound) {
+			return "", err
+		}
+	}
+	// expired silences can't be updated: a new one is needed
+	if existing != nil && existing.expired() {
+		existing = nil
+	}
+
+	if !spec.EndsAt.Time.After(silences.now()) {
+		if existing != nil {
+			return "", silences.grafanaClient.expireSilence(ctx, existing.ID)
+		}
+
+		return "", nil
+	}
+
+	model, err := SpecToSilence(spec)
+	if err != nil {
+		return "", err
+	}
+
+	if existing != nil {
+		model.ID = existing.ID
+
+		// changing the start of an active silence would replace it
+		if spec.StartsAt == nil {
+			model.StartsAt = existing.StartsAt
+		}
+	}
+	if model.StartsAt.IsZero() {
+		model.StartsAt = silences.now()
+	}
+
+	return silences.grafanaClient.saveSilence(ctx, *model)
+}
+
+// Delete expires the given silence. Silences that don't exist or already
+// expired are ignored.
+func (silences *Silences) Delete(ctx context.Context, id string) error {
+	silences.logger.Info("expiring silence", "id", id)
+
+	existing, err := silences.grafanaClient.silenceByID(ctx, id)
+	if errors.Is(err, ErrSilenceNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if existing.expired() {
+		return nil
+	}
+
+	return silences.grafanaClient.expireSilence(ctx, id)
+}
+
+// SpecToSilence converts a silence spec into its representation in Grafana's
+// API. The start of the silence is left empty if the spec doesn't define it.
+func SpecToSilence(spec v1alpha1.GrafanaSilenceSpec) (*silence, error) {
+	model := &silence{
+		EndsAt:    spec.EndsAt.Time,
+		CreatedBy: spec.CreatedBy,
+		Comment:   spec.Comment,
+	}
+
+	if spec.StartsAt != nil {
+		model.StartsAt = spec.StartsAt.Time
+	}
+	if model.CreatedBy == "" {
+		model.CreatedBy = defaultSilenceAuthor
+	}
+
+	for _, rule := range spec.Matchers {
+		matchers, err := labelsMatchers(rule)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, matcher := range matchers {
+			operator := matcher[1]
+
+			model.Matchers = append(model.Matchers, silenceMatcher{
+				Name:    matcher[0],
+				Value:   matcher[2],
+				IsRegex: operator == "=~" || operator == "!~",
+				IsEqual: operator == "=" || operator == "=~",
+			})
+		}
+	}
+
+	return model, nil
+}
+
+func (client *Client) silenceByID(ctx context.Context, id string) (*silence, error) {
+	resp, err := client.get(ctx, "/api/alertmanager/grafana/api/v2/silence/"+url.PathEscape(id))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrSilenceNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, client.httpError(resp)
+	}
+
+	existing := &silence{}
+	if err := decodeJSON(resp.Body, existing); err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+func (client *Client) saveSilence(ctx context.Context, model silence) (string, error) {
+	resp, err := client.sendJSON(ctx, http.MethodPost, "/api/alertmanager/grafana/api/v2/silences", model)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return "", client.httpError(resp)
+	}
+
+	saved := struct {
+		ID string `json:"silenceID"`
+	}{}
+	if err := decodeJSON(resp.Body, &saved); err != nil {
+		return "", err
+	}
+
+	return saved.ID, nil
+}
+
+func (client *Client) expireSilence(ctx context.Context, id string) error {
+	resp, err := client.delete(ctx, "/api/alertmanager/grafana/api/v2/silence/"+url.PathEscape(id))
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return client.httpError(resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testSilences(t *testing.T, handler http.Handler, now time.Time) *Silences {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(ClientConfig{Host: server.URL})
+	require.NoError(t, err)
+
+	silences := NewSilences(logr.Discard(), client)
+	silences.now = func() time.Time { return now }
+
+	return silences
+}
+
+func TestSpecToSilenceConvertsMatchers(t *testing.T) {
+	req := require.New(t)
+
+	model, err := SpecToSilence(v1alpha1.GrafanaSilenceSpec{
+		Matchers: []v1alpha1.LabelsMatchingRule{
+			{Eq: map[string]string{"service": "cart"}},
+			{NotMatches: map[string]string{"severity": "critical|error"}},
+		},
+		EndsAt:  metav1.NewTime(time.Date(2026, 10, 20, 6, 0, 0, 0, time.UTC)),
+		Comment: "maintenance",
+	})
+	req.NoError(err)
+
+	req.Equal("DARK", model.CreatedBy)
+	req.Equal("maintenance", model.Comment)
+	req.True(model.StartsAt.IsZero())
+	req.Equal([]silenceMatcher{
+		{Name: "service", Value: "cart", IsRegex: false, IsEqual: true},
+		{Name: "severity", Value: "critical|error", IsRegex: true, IsEqual: false},
+	}, model.Matchers)
+}
+
+func TestUpsertSilenceKeepsTheStartOfActiveSilences(t *testing.T) {
+	req := require.New(t)
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	startedAt := now.Add(-time.Hour)
+
+	var saved silence
+	silences := testSilences(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/alertmanager/grafana/api/v2/silence/silence-id":
+			writeJSON(t, w, silence{ID: "silence-id", StartsAt: startedAt, Status: &silenceStatus{State: "active"}})
+		case "POST /api/alertmanager/grafana/api/v2/silences":
+			req.NoError(json.NewDecoder(r.Body).Decode(&saved))
+			writeJSON(t, w, map[string]string{"silenceID": "silence-id"})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}), now)
+
+	id, err := silences.Upsert(context.Background(), "silence-id", v1alpha1.GrafanaSilenceSpec{
+		Matchers: []v1alpha1.LabelsMatchingRule{{Eq: map[string]string{"service": "cart"}}},
+		EndsAt:   metav1.NewTime(now.Add(time.Hour)),
+		Comment:  "maintenance",
+	})
+	req.NoError(err)
+
+	req.Equal("silence-id", id)
+	req.Equal("silence-id", saved.ID)
+	req.True(startedAt.Equal(saved.StartsAt))
+}
+
+func TestUpsertSilenceRecreatesExpiredSilences(t *testing.T) {
+	req := require.New(t)
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	var saved silence
+	silences := testSilences(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/alertmanager/grafana/api/v2/silence/old-id":
+			writeJSON(t, w, silence{ID: "old-id", Status: &silenceStatus{State: "expired"}})
+		case "POST /api/alertmanager/grafana/api/v2/silences":
+			req.NoError(json.NewDecoder(r.Body).Decode(&saved))
+			writeJSON(t, w, map[string]string{"silenceID": "new-id"})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}), now)
+
+	id, err := silences.Upsert(context.Background(), "old-id", v1alpha1.GrafanaSilenceSpec{
+		Matchers: []v1alpha1.LabelsMatchingRule{{Eq: map[string]string{"service": "cart"}}},
+		EndsAt:   metav1.NewTime(now.Add(time.Hour)),
+		Comment:  "maintenance",
+	})
+	req.NoError(err)
+
+	req.Equal("new-id", id)
+	req.Empty(saved.ID)
+	req.True(now.Equal(saved.StartsAt))
+}
+
+func TestUpsertSilenceExpiresSilencesThatAreOver(t *testing.T) {
+	req := require.New(t)
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	expired := false
+	silences := testSilences(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/alertmanager/grafana/api/v2/silence/silence-id":
+			writeJSON(t, w, silence{ID: "silence-id", Status: &silenceStatus{State: "active"}})
+		case "DELETE /api/alertmanager/grafana/api/v2/silence/silence-id":
+			expired = true
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}), now)
+
+	id, err := silences.Upsert(context.Background(), "silence-id", v1alpha1.GrafanaSilenceSpec{
+		Matchers: []v1alpha1.LabelsMatchingRule{{Eq: map[string]string{"service": "cart"}}},
+		EndsAt:   metav1.NewTime(now.Add(-time.Minute)),
+		Comment:  "maintenance",
+	})
+	req.NoError(err)
+
+	req.Empty(id)
+	req.True(expired)
+}
+
+func TestDeleteSilenceIgnoresUnknownSilences(t *testing.T) {
+	req := require.New(t)
+
+	silences := testSilences(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/alertmanager/grafana/api/v2/silence/silence-id":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}), time.Now())
+
+	req.NoError(silences.Delete(context.Background(), "silence-id"))
+}
@@ -0,0 +1,31 @@
+package grafana
+
+import (
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidateSilenceSpec statically checks a silence spec.
+func ValidateSilenceSpec(spec v1alpha1.GrafanaSilenceSpec) field.ErrorList {
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if len(spec.Matchers) == 0 {
+		errs = append(errs, field.Required(specPath.Child("matchers"), "at least one matcher is required"))
+	}
+	for i, rule := range spec.Matchers {
+		errs = append(errs, validateLabelsMatchingRule(specPath.Child("matchers").Index(i), rule)...)
+	}
+
+	if spec.EndsAt.IsZero() {
+		errs = append(errs, field.Required(specPath.Child("ends_at"), ""))
+	} else if spec.StartsAt != nil && !spec.EndsAt.After(spec.StartsAt.Time) {
+		errs = append(errs, field.Invalid(specPath.Child("ends_at"), spec.EndsAt.String(), "must be after starts_at"))
+	}
+
+	if spec.Comment == "" {
+		errs = append(errs, field.Required(specPath.Child("comment"), ""))
+	}
+
+	return errs
+}
@@ -0,0 +1,44 @@
+package grafana
+
+import (
+	"testing"
+	"time"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateSilenceSpecAcceptsValidSpecs(t *testing.T) {
+	req := require.New(t)
+
+	startsAt := metav1.NewTime(time.Date(2026, 10, 20, 2, 0, 0, 0, time.UTC))
+	errs := ValidateSilenceSpec(v1alpha1.GrafanaSilenceSpec{
+		Matchers: []v1alpha1.LabelsMatchingRule{{Eq: map[string]string{"service": "cart"}}},
+		StartsAt: &startsAt,
+		EndsAt:   metav1.NewTime(startsAt.Add(4 * time.Hour)),
+		Comment:  "database maintenance",
+	})
+
+	req.Empty(errs)
+}
+
+func TestValidateSilenceSpecRejectsInvalidSpecs(t *testing.T) {
+	req := require.New(t)
+
+	startsAt := metav1.NewTime(time.Date(2026, 10, 20, 2, 0, 0, 0, time.UTC))
+	errs := ValidateSilenceSpec(v1alpha1.GrafanaSilenceSpec{
+		Matchers: []v1alpha1.LabelsMatchingRule{{}},
+		StartsAt: &startsAt,
+		EndsAt:   metav1.NewTime(startsAt.Add(-time.Hour)),
+	})
+
+	req.Len(errs, 3)
+	req.Equal(field.ErrorTypeRequired, errs[0].Type)
+	req.Equal("spec.matchers[0]", errs[0].Field)
+	req.Equal(field.ErrorTypeInvalid, errs[1].Type)
+	req.Equal("spec.ends_at", errs[1].Field)
+	req.Equal(field.ErrorTypeRequired, errs[2].Type)
+	req.Equal("spec.comment", errs[2].Field)
+}
@@ -0,0 +1,120 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/grabana/datasource"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// sqlConnection holds the settings shared by SQL datasources.
+type sqlConnection struct {
+	URL      v1alpha1.StringOrRef
+	Default  *bool
+	Database string
+	User     v1alpha1.ValueOrRef
+	Password v1alpha1.ValueOrRef
+
+	MaxOpenConnections    *int
+	MaxIdleConnections    *int
+	ConnectionMaxLifetime *int
+	MinTimeInterval       string
+}
+
+func (datasources *Datasources) postgreSQLSpecToModel(ctx context.Context, objectRef types.NamespacedName, spec *v1alpha1.PostgreSQLDatasource) (datasource.Datasource, error) {
+	ds, err := datasources.sqlDatasource(ctx, objectRef, "postgres", sqlConnection{
+		URL:                   spec.URL,
+		Default:               spec.Default,
+		Database:              spec.Database,
+		User:                  spec.User,
+		Password:              spec.Password,
+		MaxOpenConnections:    spec.MaxOpenConnections,
+		MaxIdleConnections:    spec.MaxIdleConnections,
+		ConnectionMaxLifetime: spec.ConnectionMaxLifetime,
+		MinTimeInterval:       spec.MinTimeInterval,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sslMode := spec.SSLMode
+	if sslMode == "" {
+		sslMode = "require"
+	}
+	ds.jsonData()["sslmode"] = sslMode
+
+	if spec.Version != nil {
+		ds.jsonData()["postgresVersion"] = *spec.Version
+	}
+	if spec.TimescaleDB != nil && *spec.TimescaleDB {
+		ds.jsonData()["timescaledb"] = true
+	}
+
+	return ds, nil
+}
+
+func (datasources *Datasources) mySQLSpecToModel(ctx context.Context, objectRef types.NamespacedName, spec *v1alpha1.MySQLDatasource) (datasource.Datasource, error) {
+	ds, err := datasources.sqlDatasource(ctx, objectRef, "mysql", sqlConnection{
+		URL:                   spec.URL,
+		Default:               spec.Default,
+		Database:              spec.Database,
+		User:                  spec.User,
+		Password:              spec.Password,
+		MaxOpenConnections:    spec.MaxOpenConnections,
+		MaxIdleConnections:    spec.MaxIdleConnections,
+		ConnectionMaxLifetime: spec.ConnectionMaxLifetime,
+		MinTimeInterval:       spec.MinTimeInterval,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ds.setIfNotEmpty("timezone", spec.Timezone)
+
+	if spec.SkipTLSVerify != nil && *spec.SkipTLSVerify {
+		ds.jsonData()["tlsSkipVerify"] = true
+	}
+	if err := datasources.applyCACertificate(ctx, objectRef.Namespace, ds, spec.CACertificate); err != nil {
+		return nil, err
+	}
+
+	return ds, nil
+}
+
+func (datasources *Datasources) sqlDatasource(ctx context.Context, objectRef types.NamespacedName, datasourceType string, conn sqlConnection) (sdkDatasource, error) {
+	url, err := datasources.url(ctx, objectRef.Namespace, conn.URL)
+	if err != nil {
+		return sdkDatasource{}, err
+	}
+
+	user, err := datasources.refReader.RefToValue(ctx, objectRef.Namespace, conn.User)
+	if err != nil {
+		return sdkDatasource{}, fmt.Errorf("could not extract user: %w", err)
+	}
+	password, err := datasources.refReader.RefToValue(ctx, objectRef.Namespace, conn.Password)
+	if err != nil {
+		return sdkDatasource{}, fmt.Errorf("could not extract password: %w", err)
+	}
+
+	ds := newSDKDatasource(objectRef.Name, datasourceType, url)
+	ds.builder.IsDefault = conn.Default != nil && *conn.Default
+	ds.builder.Database = &conn.Database
+	ds.builder.User = &user
+	ds.jsonData()["database"] = conn.Database
+	ds.secureJSONData()["password"] = password
+	ds.setIfNotEmpty("timeInterval", conn.MinTimeInterval)
+
+	if conn.MaxOpenConnections != nil {
+		ds.jsonData()["maxOpenConns"] = *conn.MaxOpenConnections
+	}
+	if conn.MaxIdleConnections != nil {
+		ds.jsonData()["maxIdleConns"] = *conn.MaxIdleConnections
+	}
+	if conn.ConnectionMaxLifetime != nil {
+		ds.jsonData()["connMaxLifetime"] = *conn.ConnectionMaxLifetime
+	}
+
+	return ds, nil
+}
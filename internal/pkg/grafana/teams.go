@@ -0,0 +1,355 @@
+package grafana
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/go-logr/logr"
+)
+
+var ErrTeamNotFound = fmt.Errorf("team not found")
+var ErrTeamNotManaged = fmt.Errorf("team already exists and is not managed by DARK")
+
+// Team describes a team to create or update in Grafana.
+type Team struct {
+	// ID of the team in Grafana, if it is already known.
+	ID    int64
+	Name  string
+	Email string
+
+	// Logins or emails of the members of the team.
+	Members []string
+
+	Preferences *v1alpha1.TeamPreferences
+}
+
+type rawTeam struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type teamMember struct {
+	UserID int64  `json:"userId"`
+	Login  string `json:"login"`
+	Email  string `json:"email"`
+}
+
+type Teams struct {
+	logger        logr.Logger
+	grafanaClient *Client
+}
+
+func NewTeams(logger logr.Logger, grafanaClient *Client) *Teams {
+	return &Teams{
+		logger:        logger,
+		grafanaClient: grafanaClient,
+	}
+}
+
+// Upsert creates or updates the given team, its members and its preferences.
+// It returns the ID of the team in Grafana.
+func (teams *Teams) Upsert(ctx context.Context, team Team) (int64, error) {
+	teams.logger.Info("upserting team", "name", team.Name)
+
+	existing, err := teams.find(ctx, team)
+	if err != nil && !errors.Is(err, ErrTeamNotFound) {
+		return 0, err
+	}
+
+	if existing == nil {
+		existing, err = teams.grafanaClient.createTeam(ctx, rawTeam{Name: team.Name, Email: team.Email})
+		if err == nil {
+			err = teams.grafanaClient.ensureOwned(ctx, ownedTeam, existing.ID, existing.Name)
+		}
+	} else if existing.Name != team.Name || existing.Email != team.Email {
+		err = teams.grafanaClient.updateTeam(ctx, rawTeam{ID: existing.ID, Name: team.Name, Email: team.Email})
+	}
+	if err != nil {
+		if existing != nil {
+			return existing.ID, err
+		}
+		return 0, err
+	}
+
+	if err := teams.syncMembers(ctx, existing.ID, team.Members); err != nil {
+		return existing.ID, err
+	}
+
+	if team.Preferences != nil {
+		if err := teams.grafanaClient.updateTeamPreferences(ctx, existing.ID, *team.Preferences); err != nil {
+			return existing.ID, err
+		}
+	}
+
+	return existing.ID, nil
+}
+
+// Delete deletes the given team, and the record of DARK having created it.
+func (teams *Teams) Delete(ctx context.Context, id int64) error {
+	teams.logger.Info("deleting team", "id", id)
+
+	resp, err := teams.grafanaClient.delete(ctx, "/api/teams/"+strconv.FormatInt(id, 10))
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return teams.grafanaClient.httpError(resp)
+	}
+
+	return teams.grafanaClient.forgetOwned(ctx, ownedTeam, id)
+}
+
+// find looks for the team by the ID it was given when DARK created it, or
+// by its name if DARK recorded creating it.
+// Teams created outside of DARK are never adopted, since deleting the
+// manifest would delete them.
+func (teams *Teams) find(ctx context.Context, team Team) (*rawTeam, error) {
+	if team.ID != 0 {
+		existing, err := teams.grafanaClient.teamByID(ctx, team.ID)
+		if err == nil {
+			// teams created before DARK recorded ownership are only known by ID
+			return existing, teams.grafanaClient.ensureOwned(ctx, ownedTeam, existing.ID, existing.Name)
+		}
+		if err != ErrTeamNotFound {
+			return nil, err
+		}
+	}
+
+	existing, err := teams.grafanaClient.teamByName(ctx, team.Name)
+	if errors.Is(err, ErrTeamNotFound) {
+		return nil, ErrTeamNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	owned, err := teams.grafanaClient.isOwned(ctx, ownedTeam, existing.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !owned {
+		return nil, fmt.Errorf("%s: %w", team.Name, ErrTeamNotManaged)
+	}
+
+	return existing, nil
+}
+
+func (teams *Teams) syncMembers(ctx context.Context, teamID int64, members []string) error {
+	currentMembers, err := teams.grafanaClient.teamMembers(ctx, teamID)
+	if err != nil {
+		return err
+	}
+
+	current := make(map[int64]bool, len(currentMembers))
+	for _, member := range currentMembers {
+		current[member.UserID] = true
+	}
+
+	desired := make(map[int64]bool, len(members))
+	for _, loginOrEmail := range members {
+		user, err := teams.grafanaClient.userByLoginOrEmail(ctx, loginOrEmail)
+		if err != nil {
+			return err
+		}
+
+		desired[user.ID] = true
+
+		if current[user.ID] {
+			continue
+		}
+
+		if err := teams.grafanaClient.addTeamMember(ctx, teamID, user.ID); err != nil {
+			return err
+		}
+	}
+
+	for userID := range current {
+		if desired[userID] {
+			continue
+		}
+
+		if err := teams.grafanaClient.removeTeamMember(ctx, teamID, userID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (client *Client) teamByID(ctx context.Context, id int64) (*rawTeam, error) {
+	resp, err := client.get(ctx, "/api/teams/"+strconv.FormatInt(id, 10))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrTeamNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, client.httpError(resp)
+	}
+
+	var team rawTeam
+	if err := decodeJSON(resp.Body, &team); err != nil {
+		return nil, err
+	}
+
+	return &team, nil
+}
+
+func (client *Client) teamByName(ctx context.Context, name string) (*rawTeam, error) {
+	resp, err := client.get(ctx, "/api/teams/search?name="+url.QueryEscape(name))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, client.httpError(resp)
+	}
+
+	var response struct {
+		Teams []rawTeam `json:"teams"`
+	}
+	if err := decodeJSON(resp.Body, &response); err != nil {
+		return nil, err
+	}
+
+	for _, team := range response.Teams {
+		if team.Name == name {
+			return &team, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s: %w", name, ErrTeamNotFound)
+}
+
+func (client *Client) createTeam(ctx context.Context, team rawTeam) (*rawTeam, error) {
+	resp, err := client.sendJSON(ctx, http.MethodPost, "/api/teams", team)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, client.httpError(resp)
+	}
+
+	var response struct {
+		TeamID int64 `json:"teamId"`
+	}
+	if err := decodeJSON(resp.Body, &response); err != nil {
+		return nil, err
+	}
+
+	team.ID = response.TeamID
+
+	return &team, nil
+}
+
+func (client *Client) updateTeam(ctx context.Context, team rawTeam) error {
+	resp, err := client.sendJSON(ctx, http.MethodPut, "/api/teams/"+strconv.FormatInt(team.ID, 10), team)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return client.httpError(resp)
+	}
+
+	return nil
+}
+
+func (client *Client) updateTeamPreferences(ctx context.Context, teamID int64, preferences v1alpha1.TeamPreferences) error {
+	resp, err := client.sendJSON(ctx, http.MethodPut, "/api/teams/"+strconv.FormatInt(teamID, 10)+"/preferences", struct {
+		Theme            string `json:"theme,omitempty"`
+		HomeDashboardUID string `json:"homeDashboardUID,omitempty"`
+		Timezone         string `json:"timezone,omitempty"`
+		WeekStart        string `json:"weekStart,omitempty"`
+	}{
+		Theme:            preferences.Theme,
+		HomeDashboardUID: preferences.HomeDashboardUID,
+		Timezone:         preferences.Timezone,
+		WeekStart:        preferences.WeekStart,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return client.httpError(resp)
+	}
+
+	return nil
+}
+
+func (client *Client) teamMembers(ctx context.Context, teamID int64) ([]teamMember, error) {
+	resp, err := client.get(ctx, "/api/teams/"+strconv.FormatInt(teamID, 10)+"/members")
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, client.httpError(resp)
+	}
+
+	var members []teamMember
+	if err := decodeJSON(resp.Body, &members); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (client *Client) addTeamMember(ctx context.Context, teamID int64, userID int64) error {
+	resp, err := client.sendJSON(ctx, http.MethodPost, "/api/teams/"+strconv.FormatInt(teamID, 10)+"/members", struct {
+		UserID int64 `json:"userId"`
+	}{
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return client.httpError(resp)
+	}
+
+	return nil
+}
+
+func (client *Client) removeTeamMember(ctx context.Context, teamID int64, userID int64) error {
+	resp, err := client.delete(ctx, "/api/teams/"+strconv.FormatInt(teamID, 10)+"/members/"+strconv.FormatInt(userID, 10))
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return client.httpError(resp)
+	}
+
+	return nil
+}
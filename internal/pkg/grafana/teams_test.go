@@ -0,0 +1,203 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+)
+
+func testTeams(t *testing.T, handler http.HandlerFunc) *Teams {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(ClientConfig{Host: server.URL})
+	require.NoError(t, err)
+
+	return NewTeams(logr.Discard(), client)
+}
+
+func TestUpsertTeamCreatesTeamMembersAndPreferences(t *testing.T) {
+	req := require.New(t)
+
+	var calls []string
+	var preferences map[string]string
+	ownership := newFakeOwnership(t)
+	teams := testTeams(t, func(w http.ResponseWriter, r *http.Request) {
+		if ownership.serve(w, r) {
+			return
+		}
+
+		calls = append(calls, r.Method+" "+r.URL.Path)
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/teams/search":
+			req.Equal("SRE", r.URL.Query().Get("name"))
+			writeJSON(t, w, map[string]interface{}{"teams": []rawTeam{}})
+		case "POST /api/teams":
+			writeJSON(t, w, map[string]int64{"teamId": 4})
+		case "GET /api/teams/4/members":
+			writeJSON(t, w, []teamMember{})
+		case "GET /api/users/lookup":
+			writeJSON(t, w, rawUser{ID: 12, Login: r.URL.Query().Get("loginOrEmail")})
+		case "POST /api/teams/4/members":
+			writeJSON(t, w, map[string]string{})
+		case "PUT /api/teams/4/preferences":
+			req.NoError(json.NewDecoder(r.Body).Decode(&preferences))
+			writeJSON(t, w, map[string]string{})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	id, err := teams.Upsert(context.Background(), Team{
+		Name:        "SRE",
+		Members:     []string{"jane"},
+		Preferences: &v1alpha1.TeamPreferences{Theme: "dark", Timezone: "utc"},
+	})
+	req.NoError(err)
+
+	req.Equal(int64(4), id)
+	req.Contains(calls, "POST /api/teams/4/members")
+	req.Equal(map[string]string{"theme": "dark", "timezone": "utc"}, preferences)
+	req.True(ownership.owns("team:4"))
+}
+
+func TestUpsertTeamUpdatesKnownTeamAndSyncsMembers(t *testing.T) {
+	req := require.New(t)
+
+	var updated rawTeam
+	var added, removed []string
+	ownership := newFakeOwnership(t, "team:4")
+	teams := testTeams(t, func(w http.ResponseWriter, r *http.Request) {
+		if ownership.serve(w, r) {
+			return
+		}
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/teams/4":
+			writeJSON(t, w, rawTeam{ID: 4, Name: "SRE", Email: "old@example.com"})
+		case "PUT /api/teams/4":
+			req.NoError(json.NewDecoder(r.Body).Decode(&updated))
+			writeJSON(t, w, map[string]string{})
+		case "GET /api/teams/4/members":
+			writeJSON(t, w, []teamMember{{UserID: 12, Login: "jane"}, {UserID: 13, Login: "john"}})
+		case "GET /api/users/lookup":
+			ids := map[string]int64{"jane": 12, "ada": 14}
+			writeJSON(t, w, rawUser{ID: ids[r.URL.Query().Get("loginOrEmail")]})
+		case "POST /api/teams/4/members":
+			var member struct {
+				UserID int64 `json:"userId"`
+			}
+			req.NoError(json.NewDecoder(r.Body).Decode(&member))
+			added = append(added, r.URL.Path)
+			req.Equal(int64(14), member.UserID)
+			writeJSON(t, w, map[string]string{})
+		case "DELETE /api/teams/4/members/13":
+			removed = append(removed, r.URL.Path)
+			writeJSON(t, w, map[string]string{})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	id, err := teams.Upsert(context.Background(), Team{
+		ID:      4,
+		Name:    "SRE",
+		Email:   "sre@example.com",
+		Members: []string{"jane", "ada"},
+	})
+	req.NoError(err)
+
+	req.Equal(int64(4), id)
+	req.Equal(rawTeam{ID: 4, Name: "SRE", Email: "sre@example.com"}, updated)
+	req.Len(added, 1)
+	req.Len(removed, 1)
+}
+
+func TestUpsertTeamDoesNotAdoptExistingTeams(t *testing.T) {
+	req := require.New(t)
+
+	ownership := newFakeOwnership(t, "team:4")
+	teams := testTeams(t, func(w http.ResponseWriter, r *http.Request) {
+		if ownership.serve(w, r) {
+			return
+		}
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/teams/search":
+			writeJSON(t, w, map[string]interface{}{"teams": []rawTeam{{ID: 1, Name: "SRE"}}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	_, err := teams.Upsert(context.Background(), Team{Name: "SRE"})
+	req.ErrorIs(err, ErrTeamNotManaged)
+}
+
+func TestDeleteTeamIgnoresUnknownTeams(t *testing.T) {
+	req := require.New(t)
+
+	ownership := newFakeOwnership(t)
+	teams := testTeams(t, func(w http.ResponseWriter, r *http.Request) {
+		if ownership.serve(w, r) {
+			return
+		}
+
+		req.Equal("DELETE /api/teams/4", r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	req.NoError(teams.Delete(context.Background(), 4))
+}
+
+func TestDeleteTeamForgetsItsOwnership(t *testing.T) {
+	req := require.New(t)
+
+	ownership := newFakeOwnership(t, "team:4", "team:5")
+	teams := testTeams(t, func(w http.ResponseWriter, r *http.Request) {
+		if ownership.serve(w, r) {
+			return
+		}
+
+		req.Equal("DELETE /api/teams/4", r.Method+" "+r.URL.Path)
+		writeJSON(t, w, map[string]string{})
+	})
+
+	req.NoError(teams.Delete(context.Background(), 4))
+	req.False(ownership.owns("team:4"))
+	req.True(ownership.owns("team:5"))
+}
+
+func TestUpsertTeamAdoptsTeamsCreatedByDARK(t *testing.T) {
+	req := require.New(t)
+
+	ownership := newFakeOwnership(t, "team:4")
+	teams := testTeams(t, func(w http.ResponseWriter, r *http.Request) {
+		if ownership.serve(w, r) {
+			return
+		}
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/teams/search":
+			writeJSON(t, w, map[string]interface{}{"teams": []rawTeam{{ID: 4, Name: "SRE"}}})
+		case "GET /api/teams/4/members":
+			writeJSON(t, w, []teamMember{})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	// the status of the manifest was lost
+	id, err := teams.Upsert(context.Background(), Team{Name: "SRE"})
+	req.NoError(err)
+	req.Equal(int64(4), id)
+}
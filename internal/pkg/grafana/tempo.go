@@ -17,7 +17,12 @@ func (datasources *Datasources) tempoSpecToModel(ctx context.Context, objectRef
 		return nil, err
 	}
 
-	return tempo.New(objectRef.Name, ds.URL, opts...), nil
+	url, err := datasources.url(ctx, objectRef.Namespace, ds.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	return tempo.New(objectRef.Name, url, opts...), nil
 }
 
 func (datasources *Datasources) tempoSpecToOptions(ctx context.Context, objectRef types.NamespacedName, spec *v1alpha1.TempoDatasource) ([]tempo.Option, error) {
@@ -64,7 +69,7 @@ func (datasources *Datasources) tempoSpecToOptions(ctx context.Context, objectRe
 	}
 
 	if spec.TraceToLogs != nil {
-		opt, err := datasources.tempoTraceToLogs(ctx, spec.TraceToLogs)
+		opt, err := datasources.tempoTraceToLogs(ctx, objectRef.Namespace, spec.TraceToLogs)
 		if err != nil {
 			return nil, err
 		}
@@ -76,10 +81,10 @@ func (datasources *Datasources) tempoSpecToOptions(ctx context.Context, objectRe
 }
 
 //nolint:dupl
-func (datasources *Datasources) tempoTraceToLogs(ctx context.Context, spec *v1alpha1.TraceToLogs) (tempo.Option, error) {
+func (datasources *Datasources) tempoTraceToLogs(ctx context.Context, namespace string, spec *v1alpha1.TraceToLogs) (tempo.Option, error) {
 	opts := []tempo.TraceToLogsOption{}
 
-	datasourceUID, err := datasources.datasourceUIDFromRef(ctx, &spec.Datasource)
+	datasourceUID, err := datasources.datasourceUIDFromRef(ctx, namespace, &spec.Datasource)
 	if err != nil {
 		return nil, fmt.Errorf("could not infer datasource UID from reference: %w", err)
 	}
@@ -0,0 +1,274 @@
+package grafana
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/go-logr/logr"
+)
+
+var ErrUserNotFound = fmt.Errorf("user not found")
+var ErrUserNotManaged = fmt.Errorf("user already exists and is not managed by DARK")
+
+// User describes a user to create or update in Grafana.
+type User struct {
+	// ID of the user in Grafana, if it is already known.
+	ID    int64
+	Login string
+	Email string
+	Name  string
+
+	// Password is only used when the user is created.
+	Password v1alpha1.ValueOrRef
+
+	OrgRole string
+}
+
+type rawUser struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
+
+type Users struct {
+	logger        logr.Logger
+	grafanaClient *Client
+	refReader     refReader
+}
+
+func NewUsers(logger logr.Logger, grafanaClient *Client, refReader refReader) *Users {
+	return &Users{
+		logger:        logger,
+		grafanaClient: grafanaClient,
+		refReader:     refReader,
+	}
+}
+
+// Upsert creates or updates the given user, and sets its role in the
+// organization. It returns the ID of the user in Grafana.
+func (users *Users) Upsert(ctx context.Context, namespace string, user User) (int64, error) {
+	users.logger.Info("upserting user", "login", user.Login)
+
+	existing, err := users.find(ctx, user)
+	if err != nil && err != ErrUserNotFound {
+		return 0, err
+	}
+
+	desired := rawUser{Login: user.Login, Email: user.Email, Name: user.Name}
+
+	if existing == nil {
+		password, err := users.refReader.RefToValue(ctx, namespace, user.Password)
+		if err != nil {
+			return 0, fmt.Errorf("could not extract password: %w", err)
+		}
+
+		existing, err = users.grafanaClient.createUser(ctx, desired, password)
+		if err != nil {
+			return 0, err
+		}
+
+		if err := users.grafanaClient.ensureOwned(ctx, ownedUser, existing.ID, existing.Login); err != nil {
+			return existing.ID, err
+		}
+	} else if existing.Login != desired.Login || existing.Email != desired.Email || existing.Name != desired.Name {
+		desired.ID = existing.ID
+		if err := users.grafanaClient.updateUser(ctx, desired); err != nil {
+			return 0, err
+		}
+	}
+
+	if user.OrgRole != "" {
+		if err := users.grafanaClient.setOrgRole(ctx, *existing, user.OrgRole); err != nil {
+			return existing.ID, err
+		}
+	}
+
+	return existing.ID, nil
+}
+
+// Delete removes the given user from the organization. The user itself is
+// kept, since it might belong to other organizations, and so is the record of
+// DARK having created it: a manifest declaring it again adopts it.
+func (users *Users) Delete(ctx context.Context, id int64) error {
+	users.logger.Info("removing user from organization", "id", id)
+
+	resp, err := users.grafanaClient.delete(ctx, "/api/org/users/"+strconv.FormatInt(id, 10))
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return users.grafanaClient.httpError(resp)
+	}
+
+	return nil
+}
+
+// find looks for the user by the ID it was given when DARK created it, or
+// by its login if DARK recorded creating it.
+// Users created outside of DARK are never adopted: they could be admins or
+// SSO users that no manifest should be able to take over.
+func (users *Users) find(ctx context.Context, user User) (*rawUser, error) {
+	if user.ID != 0 {
+		existing, err := users.grafanaClient.userByID(ctx, user.ID)
+		if err == nil {
+			// users created before DARK recorded ownership are only known by ID
+			return existing, users.grafanaClient.ensureOwned(ctx, ownedUser, existing.ID, existing.Login)
+		}
+		if err != ErrUserNotFound {
+			return nil, err
+		}
+	}
+
+	existing, err := users.grafanaClient.userByLoginOrEmail(ctx, user.Login)
+	if errors.Is(err, ErrUserNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	owned, err := users.grafanaClient.isOwned(ctx, ownedUser, existing.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !owned {
+		return nil, fmt.Errorf("%s: %w", user.Login, ErrUserNotManaged)
+	}
+
+	return existing, nil
+}
+
+func (client *Client) userByID(ctx context.Context, id int64) (*rawUser, error) {
+	return client.fetchUser(ctx, "/api/users/"+strconv.FormatInt(id, 10))
+}
+
+func (client *Client) userByLoginOrEmail(ctx context.Context, loginOrEmail string) (*rawUser, error) {
+	user, err := client.fetchUser(ctx, "/api/users/lookup?loginOrEmail="+url.QueryEscape(loginOrEmail))
+	if err == ErrUserNotFound {
+		return nil, fmt.Errorf("%s: %w", loginOrEmail, ErrUserNotFound)
+	}
+
+	return user, err
+}
+
+func (client *Client) fetchUser(ctx context.Context, path string) (*rawUser, error) {
+	resp, err := client.get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrUserNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, client.httpError(resp)
+	}
+
+	var user rawUser
+	if err := decodeJSON(resp.Body, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (client *Client) createUser(ctx context.Context, user rawUser, password string) (*rawUser, error) {
+	resp, err := client.sendJSON(ctx, http.MethodPost, "/api/admin/users", struct {
+		rawUser
+		Password string `json:"password"`
+	}{
+		rawUser:  user,
+		Password: password,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, client.httpError(resp)
+	}
+
+	var response struct {
+		ID int64 `json:"id"`
+	}
+	if err := decodeJSON(resp.Body, &response); err != nil {
+		return nil, err
+	}
+
+	user.ID = response.ID
+
+	return &user, nil
+}
+
+func (client *Client) updateUser(ctx context.Context, user rawUser) error {
+	resp, err := client.sendJSON(ctx, http.MethodPut, "/api/users/"+strconv.FormatInt(user.ID, 10), user)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return client.httpError(resp)
+	}
+
+	return nil
+}
+
+// setOrgRole sets the role of the user in the organization, adding it to the
+// organization if needed.
+func (client *Client) setOrgRole(ctx context.Context, user rawUser, role string) error {
+	resp, err := client.sendJSON(ctx, http.MethodPatch, "/api/org/users/"+strconv.FormatInt(user.ID, 10), struct {
+		Role string `json:"role"`
+	}{
+		Role: role,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return client.addOrgUser(ctx, user, role)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return client.httpError(resp)
+	}
+
+	return nil
+}
+
+func (client *Client) addOrgUser(ctx context.Context, user rawUser, role string) error {
+	resp, err := client.sendJSON(ctx, http.MethodPost, "/api/org/users", struct {
+		LoginOrEmail string `json:"loginOrEmail"`
+		Role         string `json:"role"`
+	}{
+		LoginOrEmail: user.Login,
+		Role:         role,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return client.httpError(resp)
+	}
+
+	return nil
+}
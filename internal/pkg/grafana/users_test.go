@@ -0,0 +1,172 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+)
+
+func testUsers(t *testing.T, handler http.HandlerFunc) *Users {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(ClientConfig{Host: server.URL})
+	require.NoError(t, err)
+
+	return NewUsers(logr.Discard(), client, valuesOnlyRefReader{})
+}
+
+func TestUpsertUserCreatesUserWithRole(t *testing.T) {
+	req := require.New(t)
+
+	var created map[string]interface{}
+	var role map[string]string
+	ownership := newFakeOwnership(t)
+	users := testUsers(t, func(w http.ResponseWriter, r *http.Request) {
+		if ownership.serve(w, r) {
+			return
+		}
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/users/lookup":
+			req.Equal("jane", r.URL.Query().Get("loginOrEmail"))
+			w.WriteHeader(http.StatusNotFound)
+		case "POST /api/admin/users":
+			req.NoError(json.NewDecoder(r.Body).Decode(&created))
+			writeJSON(t, w, map[string]int64{"id": 12})
+		case "PATCH /api/org/users/12":
+			req.NoError(json.NewDecoder(r.Body).Decode(&role))
+			writeJSON(t, w, map[string]string{})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	id, err := users.Upsert(context.Background(), "default", User{
+		Login:    "jane",
+		Email:    "jane@example.com",
+		Password: v1alpha1.ValueOrRef{Value: "secret"},
+		OrgRole:  "Editor",
+	})
+	req.NoError(err)
+
+	req.Equal(int64(12), id)
+	req.Equal("jane", created["login"])
+	req.Equal("secret", created["password"])
+	req.Equal(map[string]string{"role": "Editor"}, role)
+	req.True(ownership.owns("user:12"))
+}
+
+func TestUpsertUserUpdatesKnownUser(t *testing.T) {
+	req := require.New(t)
+
+	var updated rawUser
+	ownership := newFakeOwnership(t)
+	users := testUsers(t, func(w http.ResponseWriter, r *http.Request) {
+		if ownership.serve(w, r) {
+			return
+		}
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/users/12":
+			writeJSON(t, w, rawUser{ID: 12, Login: "jane", Email: "old@example.com"})
+		case "PUT /api/users/12":
+			req.NoError(json.NewDecoder(r.Body).Decode(&updated))
+			writeJSON(t, w, map[string]string{})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	id, err := users.Upsert(context.Background(), "default", User{ID: 12, Login: "jane", Email: "jane@example.com"})
+	req.NoError(err)
+
+	req.Equal(int64(12), id)
+	req.Equal(rawUser{ID: 12, Login: "jane", Email: "jane@example.com"}, updated)
+	// users created before ownership was recorded are known by ID only
+	req.True(ownership.owns("user:12"))
+}
+
+func TestUpsertUserDoesNotAdoptExistingUsers(t *testing.T) {
+	req := require.New(t)
+
+	ownership := newFakeOwnership(t, "user:12")
+	users := testUsers(t, func(w http.ResponseWriter, r *http.Request) {
+		if ownership.serve(w, r) {
+			return
+		}
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/users/lookup":
+			writeJSON(t, w, rawUser{ID: 1, Login: "admin"})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	_, err := users.Upsert(context.Background(), "default", User{Login: "admin", Password: v1alpha1.ValueOrRef{Value: "secret"}})
+	req.ErrorIs(err, ErrUserNotManaged)
+}
+
+func TestDeleteUserOnlyRemovesOrganizationMembership(t *testing.T) {
+	req := require.New(t)
+
+	removed := false
+	users := testUsers(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "DELETE /api/org/users/12":
+			removed = true
+			writeJSON(t, w, map[string]string{})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	req.NoError(users.Delete(context.Background(), 12))
+	req.True(removed)
+}
+
+func TestUpsertUserAdoptsUsersCreatedByDARK(t *testing.T) {
+	req := require.New(t)
+
+	var added map[string]string
+	ownership := newFakeOwnership(t, "user:12")
+	users := testUsers(t, func(w http.ResponseWriter, r *http.Request) {
+		if ownership.serve(w, r) {
+			return
+		}
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/users/lookup":
+			writeJSON(t, w, rawUser{ID: 12, Login: "jane", Email: "jane@example.com"})
+		case "PATCH /api/org/users/12":
+			// removed from the organization when its manifest was deleted
+			w.WriteHeader(http.StatusNotFound)
+		case "POST /api/org/users":
+			req.NoError(json.NewDecoder(r.Body).Decode(&added))
+			writeJSON(t, w, map[string]string{})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	// the manifest was deleted and applied again: its status was lost
+	id, err := users.Upsert(context.Background(), "default", User{
+		Login:    "jane",
+		Email:    "jane@example.com",
+		Password: v1alpha1.ValueOrRef{Value: "secret"},
+		OrgRole:  "Viewer",
+	})
+	req.NoError(err)
+
+	req.Equal(int64(12), id)
+	req.Equal(map[string]string{"loginOrEmail": "jane", "role": "Viewer"}, added)
+}
@@ -0,0 +1,55 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var ErrConfigMapNotFound = fmt.Errorf("config map not found")
+var ErrKeyNotFoundInConfigMap = fmt.Errorf("key not found in config map")
+
+type ConfigMaps struct {
+	logger logr.Logger
+	client client.Reader
+}
+
+func NewConfigMaps(logger logr.Logger, client client.Reader) *ConfigMaps {
+	return &ConfigMaps{
+		logger: logger,
+		client: client,
+	}
+}
+
+func (configMaps *ConfigMaps) Read(ctx context.Context, namespace string, ref v1.ConfigMapKeySelector) (string, error) {
+	logger := configMaps.logger.WithValues("namespace", namespace, "name", ref.Name)
+	logger.Info("fetching config map")
+
+	configMap := &v1.ConfigMap{}
+	if err := configMaps.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", fmt.Errorf("%s: %w", ref.Name, ErrConfigMapNotFound)
+		}
+
+		logger.Error(err, "unable to fetch config map")
+		return "", err
+	}
+
+	if value, ok := configMap.Data[ref.Key]; ok {
+		return value, nil
+	}
+	if value, ok := configMap.BinaryData[ref.Key]; ok {
+		return string(value), nil
+	}
+
+	// key doesn't exist in config map, but the ref was marked as optional
+	if ref.Optional != nil && *ref.Optional {
+		return "", nil
+	}
+
+	return "", fmt.Errorf("key '%s' does not exist: %w", ref.Key, ErrKeyNotFoundInConfigMap)
+}
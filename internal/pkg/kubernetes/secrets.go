@@ -9,6 +9,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 var ErrSecretNotFound = fmt.Errorf("secret not found")
@@ -18,6 +19,10 @@ type SecretUpsertRequest struct {
 	Name      string
 	Namespace string
 	Data      map[string][]byte
+
+	Labels          map[string]string
+	Annotations     map[string]string
+	OwnerReferences []metav1.OwnerReference
 }
 
 type Secrets struct {
@@ -32,45 +37,93 @@ func NewSecrets(logger logr.Logger, client client.Client) *Secrets {
 	}
 }
 
+// Upsert creates the requested secret, or patches it in place if it already
+// exists: consumers watching it see the new value without it disappearing, and
+// the metadata added by other tools is preserved.
 func (secrets *Secrets) Upsert(ctx context.Context, request SecretUpsertRequest) error {
 	logger := secrets.logger.WithValues("namespace", request.Namespace, "name", request.Name)
 	logger.Info("upserting secret")
 
-	secret := &v1.Secret{}
-
-	// if a secret for this API key already exists, we delete it
-	err := secrets.client.Get(ctx, client.ObjectKey{Namespace: request.Namespace, Name: request.Name}, secret)
-	if err != nil && !apierrors.IsNotFound(err) {
-		logger.Error(err, "unable to check secret existence")
-		return err
-	}
-	// the secret was found
-	if err == nil {
-		if err := secrets.client.Delete(ctx, secret); err != nil {
-			logger.Error(err, "unable to delete existing secret")
-			return err
-		}
-	}
-
-	// now we can safely re-create the secret with a new value
-	err = secrets.client.Create(ctx, &v1.Secret{
+	secret := &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      request.Name,
 			Namespace: request.Namespace,
-			Annotations: map[string]string{
-				"app.kubernetes.io/managed-by": "dark",
-			},
 		},
-		Data: request.Data,
+	}
+
+	result, err := controllerutil.CreateOrPatch(ctx, secrets.client, secret, func() error {
+		secret.Labels = mergeStringMaps(secret.Labels, request.Labels)
+		secret.Annotations = mergeStringMaps(secret.Annotations, request.Annotations, managedByAnnotations())
+		secret.OwnerReferences = mergeOwnerReferences(secret.OwnerReferences, request.OwnerReferences)
+		secret.Data = request.Data
+
+		return nil
 	})
 	if err != nil {
-		logger.Error(err, "unable to create secret")
+		logger.Error(err, "unable to upsert secret")
 		return err
 	}
 
+	logger.Info("secret upserted", "result", result)
+
 	return nil
 }
 
+func managedByAnnotations() map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/managed-by": "dark",
+	}
+}
+
+// mergeStringMaps adds the values of the given maps to the base one, later
+// maps taking precedence.
+func mergeStringMaps(base map[string]string, others ...map[string]string) map[string]string {
+	for _, other := range others {
+		for key, value := range other {
+			if base == nil {
+				base = make(map[string]string)
+			}
+
+			base[key] = value
+		}
+	}
+
+	return base
+}
+
+// mergeOwnerReferences adds the missing owner references to the existing ones.
+func mergeOwnerReferences(existing []metav1.OwnerReference, references []metav1.OwnerReference) []metav1.OwnerReference {
+	for _, reference := range references {
+		found := false
+		for _, existingReference := range existing {
+			if existingReference.UID == reference.UID {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			existing = append(existing, reference)
+		}
+	}
+
+	return existing
+}
+
+// Annotations returns the annotations of the given secret.
+func (secrets *Secrets) Annotations(ctx context.Context, namespace string, name string) (map[string]string, error) {
+	secret := &v1.Secret{}
+	if err := secrets.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, ErrSecretNotFound
+		}
+
+		return nil, err
+	}
+
+	return secret.Annotations, nil
+}
+
 func (secrets *Secrets) Read(ctx context.Context, namespace string, ref v1.SecretKeySelector) (string, error) {
 	logger := secrets.logger.WithValues("namespace", namespace, "name", ref.Name)
 	logger.Info("fetching secret")
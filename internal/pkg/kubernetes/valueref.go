@@ -15,27 +15,64 @@ type secretsReader interface {
 	Read(ctx context.Context, namespace string, ref v1.SecretKeySelector) (string, error)
 }
 
+type configMapsReader interface {
+	Read(ctx context.Context, namespace string, ref v1.ConfigMapKeySelector) (string, error)
+}
+
+// externalSecretsReader reads values from secret backends living outside of
+// Kubernetes.
+type externalSecretsReader interface {
+	Read(ctx context.Context, namespace string, ref v1alpha1.ExternalKeySelector) (string, error)
+}
+
 type ValueRefReader struct {
 	logger logr.Logger
 
-	secrets secretsReader
+	secrets    secretsReader
+	configMaps configMapsReader
+	external   externalSecretsReader
 }
 
-func NewValueRefReader(logger logr.Logger, secrets secretsReader) *ValueRefReader {
+// NewValueRefReader creates a reader for ValueOrRef. external can be nil if no
+// external secret backend is configured.
+func NewValueRefReader(logger logr.Logger, secrets secretsReader, configMaps configMapsReader, external externalSecretsReader) *ValueRefReader {
 	return &ValueRefReader{
-		logger:  logger,
-		secrets: secrets,
+		logger:     logger,
+		secrets:    secrets,
+		configMaps: configMaps,
+		external:   external,
 	}
 }
 
 func (reader *ValueRefReader) RefToValue(ctx context.Context, namespace string, ref v1alpha1.ValueOrRef) (string, error) {
-	if ref.Value == "" && (ref.ValueRef == nil || ref.ValueRef.SecretKeyRef == nil) {
+	if ref.Value != "" {
+		return ref.Value, nil
+	}
+
+	if ref.ValueRef == nil {
 		return "", ErrInvalidValueRef
 	}
 
-	if ref.Value != "" {
-		return ref.Value, nil
+	refs := 0
+	for _, set := range []bool{ref.ValueRef.SecretKeyRef != nil, ref.ValueRef.ConfigMapKeyRef != nil, ref.ValueRef.ExternalKeyRef != nil} {
+		if set {
+			refs++
+		}
+	}
+	if refs != 1 {
+		return "", ErrInvalidValueRef
+	}
+
+	switch {
+	case ref.ValueRef.SecretKeyRef != nil:
+		return reader.secrets.Read(ctx, namespace, *ref.ValueRef.SecretKeyRef)
+	case ref.ValueRef.ConfigMapKeyRef != nil:
+		return reader.configMaps.Read(ctx, namespace, *ref.ValueRef.ConfigMapKeyRef)
+	}
+
+	if reader.external == nil {
+		return "", fmt.Errorf("no external secret backend configured: %w", ErrInvalidValueRef)
 	}
 
-	return reader.secrets.Read(ctx, namespace, *ref.ValueRef.SecretKeyRef)
+	return reader.external.Read(ctx, namespace, *ref.ValueRef.ExternalKeyRef)
 }
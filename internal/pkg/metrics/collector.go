@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var managedObjectsDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "managed_objects"),
+	"Number of objects managed by the operator, per kind and namespace.",
+	[]string{"kind", "namespace"}, nil,
+)
+
+var lastSyncDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "last_sync_timestamp_seconds"),
+	"Timestamp of the last successful synchronization of an object with Grafana.",
+	[]string{"kind", "namespace", "name"}, nil,
+)
+
+type syncedObject interface {
+	client.Object
+
+	GetSyncStatus() *v1alpha1.SyncStatus
+}
+
+// ManagedKind describes a kind of objects managed by the operator.
+type ManagedKind struct {
+	Kind string
+	List func() client.ObjectList
+}
+
+// ManagedObjectsCollector exposes the number of managed objects and their last
+// sync time.
+// Objects are listed from the manager's cache when the metrics are collected.
+type ManagedObjectsCollector struct {
+	logger    logr.Logger
+	k8sClient client.Reader
+	kinds     []ManagedKind
+}
+
+var _ prometheus.Collector = &ManagedObjectsCollector{}
+
+func NewManagedObjectsCollector(logger logr.Logger, k8sClient client.Reader, kinds ...ManagedKind) *ManagedObjectsCollector {
+	return &ManagedObjectsCollector{
+		logger:    logger,
+		k8sClient: k8sClient,
+		kinds:     kinds,
+	}
+}
+
+func (collector *ManagedObjectsCollector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- managedObjectsDesc
+	descs <- lastSyncDesc
+}
+
+func (collector *ManagedObjectsCollector) Collect(metrics chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, kind := range collector.kinds {
+		list := kind.List()
+		if err := collector.k8sClient.List(ctx, list); err != nil {
+			collector.logger.Error(err, "could not list objects", "kind", kind.Kind)
+			continue
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			collector.logger.Error(err, "could not extract objects", "kind", kind.Kind)
+			continue
+		}
+
+		countByNamespace := make(map[string]int)
+
+		for _, item := range items {
+			object, ok := item.(client.Object)
+			if !ok {
+				continue
+			}
+
+			countByNamespace[object.GetNamespace()]++
+
+			synced, ok := item.(syncedObject)
+			if !ok || synced.GetSyncStatus().LastSyncTime == nil {
+				continue
+			}
+
+			lastSync := synced.GetSyncStatus().LastSyncTime.Time
+			metrics <- prometheus.MustNewConstMetric(lastSyncDesc, prometheus.GaugeValue, float64(lastSync.Unix()), kind.Kind, object.GetNamespace(), object.GetName())
+		}
+
+		for objectNamespace, count := range countByNamespace {
+			metrics <- prometheus.MustNewConstMetric(managedObjectsDesc, prometheus.GaugeValue, float64(count), kind.Kind, objectNamespace)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"reflect"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "dark"
+
+// Outcomes of a reconciliation.
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+	ResultDrift   = "drift"
+)
+
+var reconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "reconcile_total",
+	Help:      "Number of reconciliations, per kind, namespace and result (success, error or drift).",
+}, []string{"kind", "namespace", "result"})
+
+var grafanaRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Subsystem: "grafana",
+	Name:      "request_duration_seconds",
+	Help:      "Latency of the requests sent to Grafana's API, per endpoint, method and status code.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"endpoint", "method", "code"})
+
+var grafanaRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Subsystem: "grafana",
+	Name:      "request_errors_total",
+	Help:      "Number of requests to Grafana's API that failed or resulted in a server error, per endpoint and method.",
+}, []string{"endpoint", "method"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		reconcileTotal,
+		grafanaRequestDuration,
+		grafanaRequestErrors,
+	)
+}
+
+// RecordReconcile records the outcome of the reconciliation of an object.
+func RecordReconcile(object client.Object, result string) {
+	reconcileTotal.WithLabelValues(kindOf(object), object.GetNamespace(), result).Inc()
+}
+
+func kindOf(object client.Object) string {
+	if kind := object.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+
+	// objects read from the cache usually don't have their TypeMeta set
+	objectType := reflect.TypeOf(object)
+	if objectType.Kind() == reflect.Pointer {
+		objectType = objectType.Elem()
+	}
+
+	return objectType.Name()
+}
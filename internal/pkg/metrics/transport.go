@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var numericSegment = regexp.MustCompile(`^[0-9]+$`)
+
+// segments followed by identifiers, and the placeholders used for them.
+var identifiedSegments = map[string][]string{
+	"uid":         {":uid"},
+	"name":        {":name"},
+	"folders":     {":uid"},
+	"rules":       {":folder", ":group"},
+	"silence":     {":id"},
+	"annotations": {":id"},
+}
+
+type instrumentedTransport struct {
+	next http.RoundTripper
+}
+
+// InstrumentTransport wraps an HTTP transport used to talk to Grafana to
+// measure the latency and errors of each API endpoint.
+func InstrumentTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &instrumentedTransport{next: next}
+}
+
+func (transport *instrumentedTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	endpoint := Endpoint(request.URL.Path)
+	start := time.Now()
+
+	resp, err := transport.next.RoundTrip(request)
+	if err != nil {
+		grafanaRequestErrors.WithLabelValues(endpoint, request.Method).Inc()
+		return nil, err
+	}
+
+	grafanaRequestDuration.WithLabelValues(endpoint, request.Method, strconv.Itoa(resp.StatusCode)).Observe(time.Since(start).Seconds())
+	if resp.StatusCode >= http.StatusInternalServerError {
+		grafanaRequestErrors.WithLabelValues(endpoint, request.Method).Inc()
+	}
+
+	return resp, nil
+}
+
+// Endpoint turns the path of a request into an endpoint name, replacing the
+// identifiers it contains with placeholders to keep the metrics' cardinality
+// under control.
+// Ex: /api/dashboards/uid/some-dashboard -> /api/dashboards/uid/:uid
+func Endpoint(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for i := 0; i < len(segments); i++ {
+		if numericSegment.MatchString(segments[i]) {
+			segments[i] = ":id"
+			continue
+		}
+
+		for _, placeholder := range identifiedSegments[segments[i]] {
+			if i+1 >= len(segments) {
+				break
+			}
+
+			segments[i+1] = placeholder
+			i++
+		}
+	}
+
+	return "/" + strings.Join(segments, "/")
+}
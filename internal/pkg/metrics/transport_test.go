@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointReplacesIdentifiersWithPlaceholders(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/api/health", expected: "/api/health"},
+		{path: "/api/dashboards/db", expected: "/api/dashboards/db"},
+		{path: "/api/dashboards/uid/some-dashboard", expected: "/api/dashboards/uid/:uid"},
+		{path: "/api/datasources/42", expected: "/api/datasources/:id"},
+		{path: "/api/datasources/name/prometheus", expected: "/api/datasources/name/:name"},
+		{path: "/api/folders/some-folder/permissions", expected: "/api/folders/:uid/permissions"},
+		{path: "/api/ruler/grafana/api/v1/rules/some-folder", expected: "/api/ruler/grafana/api/v1/rules/:folder"},
+		{path: "/api/ruler/grafana/api/v1/rules/some-folder/some-group", expected: "/api/ruler/grafana/api/v1/rules/:folder/:group"},
+		{path: "/api/annotations/12", expected: "/api/annotations/:id"},
+		{path: "/api/annotations", expected: "/api/annotations"},
+		{path: "/api/auth/keys/3/", expected: "/api/auth/keys/:id"},
+		{path: "/api/alertmanager/grafana/api/v2/silence/5c5a5b2f-8a5e-4d6c-9d3c-2a1e5f7b9c0d", expected: "/api/alertmanager/grafana/api/v2/silence/:id"},
+		{path: "/api/alertmanager/grafana/api/v2/silences", expected: "/api/alertmanager/grafana/api/v2/silences"},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+
+		t.Run(tc.path, func(t *testing.T) {
+			require.Equal(t, tc.expected, Endpoint(tc.path))
+		})
+	}
+}
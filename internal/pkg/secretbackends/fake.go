@@ -0,0 +1,100 @@
+package secretbackends
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// FakeVault emulates the few endpoints of a Vault server used by
+// VaultBackend: the KV version 2 secrets engine mounted at "secret", and the
+// Kubernetes auth method mounted at "kubernetes".
+// It allows testing the backend, or code relying on it, without a real Vault.
+type FakeVault struct {
+	// Token expected by the fake, and returned by logins.
+	Token string
+	// Role accepted by Kubernetes logins.
+	KubernetesRole string
+
+	lock    sync.Mutex
+	secrets map[string]map[string]interface{}
+	reads   int
+	logins  int
+}
+
+func NewFakeVault(token string) *FakeVault {
+	return &FakeVault{
+		Token:   token,
+		secrets: make(map[string]map[string]interface{}),
+	}
+}
+
+// Put stores a secret at the given path.
+func (fake *FakeVault) Put(secretPath string, data map[string]interface{}) {
+	fake.lock.Lock()
+	defer fake.lock.Unlock()
+
+	fake.secrets[secretPath] = data
+}
+
+// Reads returns the number of secrets read so far.
+func (fake *FakeVault) Reads() int {
+	fake.lock.Lock()
+	defer fake.lock.Unlock()
+
+	return fake.reads
+}
+
+// Logins returns the number of successful logins so far.
+func (fake *FakeVault) Logins() int {
+	fake.lock.Lock()
+	defer fake.lock.Unlock()
+
+	return fake.logins
+}
+
+func (fake *FakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fake.lock.Lock()
+	defer fake.lock.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/auth/kubernetes/login":
+		var request struct {
+			Role string `json:"role"`
+			JWT  string `json:"jwt"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.JWT == "" || request.Role != fake.KubernetesRole {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		fake.logins++
+		writeFakeJSON(w, map[string]interface{}{
+			"auth": map[string]interface{}{"client_token": fake.Token, "lease_duration": 3600},
+		})
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v1/secret/data/"):
+		if r.Header.Get("X-Vault-Token") != fake.Token {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		fake.reads++
+		secret, ok := fake.secrets[strings.TrimPrefix(r.URL.Path, "/v1/secret/data/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		writeFakeJSON(w, map[string]interface{}{
+			"data": map[string]interface{}{"data": secret},
+		})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func writeFakeJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
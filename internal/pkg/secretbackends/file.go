@@ -0,0 +1,79 @@
+package secretbackends
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileBackend reads secrets from files, typically mounted in the operator's
+// pod by a CSI driver.
+// A path designates either a file, exposed under the empty key, or a
+// directory whose files are exposed under their name.
+type FileBackend struct {
+	root string
+}
+
+// NewFileBackend creates a backend reading files under the given root
+// directory only.
+func NewFileBackend(root string) *FileBackend {
+	return &FileBackend{root: root}
+}
+
+func (backend *FileBackend) Fetch(_ context.Context, secretPath string) (map[string]string, error) {
+	fullPath := filepath.Join(backend.root, filepath.FromSlash(secretPath))
+	if !strings.HasPrefix(fullPath, filepath.Clean(backend.root)+string(filepath.Separator)) {
+		return nil, fmt.Errorf("%s: %w", secretPath, ErrInvalidPath)
+	}
+
+	info, err := os.Stat(fullPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%s: %w", secretPath, ErrSecretNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		content, err := os.ReadFile(fullPath)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]string{"": string(content)}, nil
+	}
+
+	entries, err := os.ReadDir(fullPath)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		// projected volumes hold their actual content in hidden directories
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		entryPath := filepath.Join(fullPath, entry.Name())
+
+		// entries are often symlinks: their target must be checked
+		entryInfo, err := os.Stat(entryPath)
+		if err != nil || entryInfo.IsDir() {
+			continue
+		}
+
+		content, err := os.ReadFile(entryPath)
+		if err != nil {
+			return nil, err
+		}
+
+		secret[entry.Name()] = string(content)
+	}
+
+	return secret, nil
+}
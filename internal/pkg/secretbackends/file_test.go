@@ -0,0 +1,40 @@
+package secretbackends
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileBackendReadsFilesAndDirectories(t *testing.T) {
+	req := require.New(t)
+
+	root := t.TempDir()
+	req.NoError(os.MkdirAll(filepath.Join(root, "apps", "grafana", "..data"), 0o700))
+	req.NoError(os.WriteFile(filepath.Join(root, "apps", "token"), []byte("t0k3n"), 0o600))
+	req.NoError(os.WriteFile(filepath.Join(root, "apps", "grafana", "password"), []byte("s3cr3t"), 0o600))
+
+	backend := NewFileBackend(root)
+
+	secret, err := backend.Fetch(context.Background(), "apps/token")
+	req.NoError(err)
+	req.Equal(map[string]string{"": "t0k3n"}, secret)
+
+	secret, err = backend.Fetch(context.Background(), "apps/grafana")
+	req.NoError(err)
+	req.Equal(map[string]string{"password": "s3cr3t"}, secret)
+
+	_, err = backend.Fetch(context.Background(), "apps/unknown")
+	req.ErrorIs(err, ErrSecretNotFound)
+}
+
+func TestFileBackendStaysInItsRoot(t *testing.T) {
+	req := require.New(t)
+
+	_, err := NewFileBackend(t.TempDir()).Fetch(context.Background(), "../../etc/passwd")
+
+	req.ErrorIs(err, ErrInvalidPath)
+}
@@ -0,0 +1,157 @@
+package secretbackends
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/go-logr/logr"
+)
+
+var ErrUnknownBackend = fmt.Errorf("unknown secret backend")
+var ErrSecretNotFound = fmt.Errorf("external secret not found")
+var ErrKeyNotFoundInSecret = fmt.Errorf("key not found in external secret")
+var ErrInvalidPath = fmt.Errorf("invalid external secret path")
+
+// NamespacePlaceholder is replaced by the namespace of the object reading a
+// secret in the path prefixes of the backends.
+const NamespacePlaceholder = "{namespace}"
+
+// Backend fetches secrets from an external secret store.
+type Backend interface {
+	// Fetch returns the entries of the secret stored at the given path.
+	// The path is already scoped to the namespace reading the secret.
+	Fetch(ctx context.Context, secretPath string) (map[string]string, error)
+}
+
+// Reader reads values from the external secret backends configured in the
+// operator, and caches the secrets it fetched.
+type Reader struct {
+	logger logr.Logger
+
+	lock     sync.RWMutex
+	backends map[string]*cachedBackend
+}
+
+func NewReader(logger logr.Logger) *Reader {
+	return &Reader{
+		logger:   logger,
+		backends: make(map[string]*cachedBackend),
+	}
+}
+
+// Register makes a backend available under the given name.
+// Paths read from the backend are prefixed with pathPrefix, in which
+// NamespacePlaceholder is replaced by the namespace of the reader: it keeps
+// namespaces from reading each other's secrets.
+// Secrets are cached for cacheTTL. Zero disables caching.
+func (reader *Reader) Register(name string, backend Backend, pathPrefix string, cacheTTL time.Duration) {
+	reader.lock.Lock()
+	defer reader.lock.Unlock()
+
+	reader.backends[name] = &cachedBackend{
+		backend:    backend,
+		pathPrefix: pathPrefix,
+		ttl:        cacheTTL,
+		now:        time.Now,
+		entries:    make(map[string]cacheEntry),
+	}
+}
+
+// Read returns the value designated by the given selector, for an object
+// living in the given namespace.
+func (reader *Reader) Read(ctx context.Context, namespace string, ref v1alpha1.ExternalKeySelector) (string, error) {
+	logger := reader.logger.WithValues("backend", ref.Backend, "namespace", namespace, "path", ref.Path)
+	logger.Info("fetching external secret")
+
+	reader.lock.RLock()
+	backend, ok := reader.backends[ref.Backend]
+	reader.lock.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("%s: %w", ref.Backend, ErrUnknownBackend)
+	}
+
+	secretPath, err := scopedPath(backend.pathPrefix, namespace, ref.Path)
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := backend.fetch(ctx, secretPath)
+	if err != nil {
+		logger.Error(err, "unable to fetch external secret")
+		return "", err
+	}
+
+	if value, ok := secret[ref.Key]; ok {
+		return value, nil
+	}
+
+	// key doesn't exist in secret, but the ref was marked as optional
+	if ref.Optional != nil && *ref.Optional {
+		return "", nil
+	}
+
+	return "", fmt.Errorf("key '%s' does not exist: %w", ref.Key, ErrKeyNotFoundInSecret)
+}
+
+// scopedPath joins the path prefix of a backend and the path of a secret,
+// making sure the result doesn't escape the prefix.
+func scopedPath(pathPrefix string, namespace string, secretPath string) (string, error) {
+	if secretPath == "" {
+		return "", fmt.Errorf("empty path: %w", ErrInvalidPath)
+	}
+
+	for _, segment := range strings.Split(secretPath, "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("%s: %w", secretPath, ErrInvalidPath)
+		}
+	}
+
+	prefix := strings.ReplaceAll(pathPrefix, NamespacePlaceholder, namespace)
+
+	return strings.TrimPrefix(path.Join(prefix, secretPath), "/"), nil
+}
+
+type cacheEntry struct {
+	secret    map[string]string
+	fetchedAt time.Time
+}
+
+type cachedBackend struct {
+	backend    Backend
+	pathPrefix string
+	ttl        time.Duration
+	now        func() time.Time
+
+	lock    sync.Mutex
+	entries map[string]cacheEntry
+}
+
+func (cache *cachedBackend) fetch(ctx context.Context, secretPath string) (map[string]string, error) {
+	if cache.ttl == 0 {
+		return cache.backend.Fetch(ctx, secretPath)
+	}
+
+	cache.lock.Lock()
+	entry, ok := cache.entries[secretPath]
+	cache.lock.Unlock()
+
+	if ok && cache.now().Sub(entry.fetchedAt) < cache.ttl {
+		return entry.secret, nil
+	}
+
+	secret, err := cache.backend.Fetch(ctx, secretPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.lock.Lock()
+	cache.entries[secretPath] = cacheEntry{secret: secret, fetchedAt: cache.now()}
+	cache.lock.Unlock()
+
+	return secret, nil
+}
@@ -0,0 +1,93 @@
+package secretbackends
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+)
+
+// countingBackend serves static secrets and counts fetches.
+type countingBackend struct {
+	secrets map[string]map[string]string
+	fetches []string
+}
+
+func (backend *countingBackend) Fetch(_ context.Context, secretPath string) (map[string]string, error) {
+	backend.fetches = append(backend.fetches, secretPath)
+
+	secret, ok := backend.secrets[secretPath]
+	if !ok {
+		return nil, ErrSecretNotFound
+	}
+
+	return secret, nil
+}
+
+func TestReadScopesPathsToTheNamespace(t *testing.T) {
+	req := require.New(t)
+
+	backend := &countingBackend{secrets: map[string]map[string]string{
+		"dark/apps/grafana": {"password": "s3cr3t"},
+	}}
+	reader := NewReader(logr.Discard())
+	reader.Register("vault", backend, "dark/"+NamespacePlaceholder, 0)
+
+	value, err := reader.Read(context.Background(), "apps", v1alpha1.ExternalKeySelector{Backend: "vault", Path: "grafana", Key: "password"})
+	req.NoError(err)
+	req.Equal("s3cr3t", value)
+
+	_, err = reader.Read(context.Background(), "other", v1alpha1.ExternalKeySelector{Backend: "vault", Path: "grafana", Key: "password"})
+	req.ErrorIs(err, ErrSecretNotFound)
+
+	_, err = reader.Read(context.Background(), "other", v1alpha1.ExternalKeySelector{Backend: "vault", Path: "../apps/grafana", Key: "password"})
+	req.ErrorIs(err, ErrInvalidPath)
+}
+
+func TestReadHandlesMissingKeys(t *testing.T) {
+	req := require.New(t)
+
+	optional := true
+	backend := &countingBackend{secrets: map[string]map[string]string{
+		"apps/grafana": {"password": "s3cr3t"},
+	}}
+	reader := NewReader(logr.Discard())
+	reader.Register("vault", backend, NamespacePlaceholder, 0)
+
+	_, err := reader.Read(context.Background(), "apps", v1alpha1.ExternalKeySelector{Backend: "vault", Path: "grafana", Key: "token"})
+	req.ErrorIs(err, ErrKeyNotFoundInSecret)
+
+	value, err := reader.Read(context.Background(), "apps", v1alpha1.ExternalKeySelector{Backend: "vault", Path: "grafana", Key: "token", Optional: &optional})
+	req.NoError(err)
+	req.Empty(value)
+
+	_, err = reader.Read(context.Background(), "apps", v1alpha1.ExternalKeySelector{Backend: "unknown", Path: "grafana", Key: "token"})
+	req.ErrorIs(err, ErrUnknownBackend)
+}
+
+func TestReadCachesSecrets(t *testing.T) {
+	req := require.New(t)
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	backend := &countingBackend{secrets: map[string]map[string]string{
+		"apps/grafana": {"username": "admin", "password": "s3cr3t"},
+	}}
+	reader := NewReader(logr.Discard())
+	reader.Register("vault", backend, NamespacePlaceholder, time.Minute)
+	reader.backends["vault"].now = func() time.Time { return now }
+
+	for _, key := range []string{"username", "password"} {
+		_, err := reader.Read(context.Background(), "apps", v1alpha1.ExternalKeySelector{Backend: "vault", Path: "grafana", Key: key})
+		req.NoError(err)
+	}
+	req.Len(backend.fetches, 1)
+
+	now = now.Add(2 * time.Minute)
+
+	_, err := reader.Read(context.Background(), "apps", v1alpha1.ExternalKeySelector{Backend: "vault", Path: "grafana", Key: "password"})
+	req.NoError(err)
+	req.Len(backend.fetches, 2)
+}
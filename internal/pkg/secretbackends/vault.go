@@ -0,0 +1,241 @@
+package secretbackends
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrVaultNotConfigured = fmt.Errorf("vault backend not configured")
+
+// DefaultKubernetesTokenPath is the path of the service account token of the
+// operator, used to authenticate to Vault with its Kubernetes auth method.
+//
+//nolint:gosec
+const DefaultKubernetesTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token" // (not a hardcoded credential)
+
+// VaultConfig describes how to reach and authenticate to a Vault server.
+type VaultConfig struct {
+	// Address of the Vault server.
+	Address string
+	// Mount path of the KV version 2 secrets engine.
+	Mount string
+
+	// Static token to authenticate with. Takes precedence over the Kubernetes auth method.
+	Token string
+
+	// Role to authenticate as, with the Kubernetes auth method.
+	KubernetesRole string
+	// Mount path of the Kubernetes auth method.
+	KubernetesAuthPath string
+	// Path of the service account token presented to Vault.
+	KubernetesTokenPath string
+
+	HTTPClient *http.Client
+}
+
+// VaultBackend reads secrets from the KV version 2 secrets engine of a
+// HashiCorp Vault server.
+type VaultBackend struct {
+	config VaultConfig
+	http   *http.Client
+	now    func() time.Time
+
+	lock        sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+func NewVaultBackend(config VaultConfig) (*VaultBackend, error) {
+	if config.Address == "" || (config.Token == "" && config.KubernetesRole == "") {
+		return nil, ErrVaultNotConfigured
+	}
+
+	if config.Mount == "" {
+		config.Mount = "secret"
+	}
+	if config.KubernetesAuthPath == "" {
+		config.KubernetesAuthPath = "kubernetes"
+	}
+	if config.KubernetesTokenPath == "" {
+		config.KubernetesTokenPath = DefaultKubernetesTokenPath
+	}
+
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &VaultBackend{
+		config: config,
+		http:   httpClient,
+		now:    time.Now,
+		token:  config.Token,
+	}, nil
+}
+
+func (backend *VaultBackend) Fetch(ctx context.Context, secretPath string) (map[string]string, error) {
+	resp, err := backend.readSecret(ctx, secretPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// the login might have expired or been revoked: let's try again with a new one
+	if resp.StatusCode == http.StatusForbidden && backend.config.Token == "" {
+		_ = resp.Body.Close()
+
+		backend.forgetToken()
+
+		resp, err = backend.readSecret(ctx, secretPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s: %w", secretPath, ErrSecretNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, vaultError(resp)
+	}
+
+	var response struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	secret := make(map[string]string, len(response.Data.Data))
+	for key, value := range response.Data.Data {
+		if str, ok := value.(string); ok {
+			secret[key] = str
+			continue
+		}
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		secret[key] = string(encoded)
+	}
+
+	return secret, nil
+}
+
+func (backend *VaultBackend) readSecret(ctx context.Context, secretPath string) (*http.Response, error) {
+	token, err := backend.authToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, backend.url(backend.config.Mount+"/data/"+secretPath), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("X-Vault-Token", token)
+
+	return backend.http.Do(request)
+}
+
+func (backend *VaultBackend) authToken(ctx context.Context) (string, error) {
+	backend.lock.Lock()
+	defer backend.lock.Unlock()
+
+	if backend.config.Token != "" {
+		return backend.config.Token, nil
+	}
+
+	if backend.token != "" && backend.now().Before(backend.tokenExpiry) {
+		return backend.token, nil
+	}
+
+	token, ttl, err := backend.kubernetesLogin(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	backend.token = token
+	// renew the token a bit before it expires
+	backend.tokenExpiry = backend.now().Add(ttl * 9 / 10)
+
+	return token, nil
+}
+
+func (backend *VaultBackend) forgetToken() {
+	backend.lock.Lock()
+	defer backend.lock.Unlock()
+
+	backend.token = ""
+	backend.tokenExpiry = time.Time{}
+}
+
+func (backend *VaultBackend) kubernetesLogin(ctx context.Context) (string, time.Duration, error) {
+	jwt, err := os.ReadFile(backend.config.KubernetesTokenPath)
+	if err != nil {
+		return "", 0, fmt.Errorf("could not read service account token: %w", err)
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"role": backend.config.KubernetesRole,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	})
+	if err != nil {
+		return "", 0, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, backend.url("auth/"+backend.config.KubernetesAuthPath+"/login"), bytes.NewReader(body))
+	if err != nil {
+		return "", 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	resp, err := backend.http.Do(request)
+	if err != nil {
+		return "", 0, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, vaultError(resp)
+	}
+
+	var response struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int64  `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", 0, err
+	}
+
+	return response.Auth.ClientToken, time.Duration(response.Auth.LeaseDuration) * time.Second, nil
+}
+
+func (backend *VaultBackend) url(path string) string {
+	return strings.TrimSuffix(backend.config.Address, "/") + "/v1/" + path
+}
+
+func vaultError(resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("could not query vault: %s (HTTP status %d)", body, resp.StatusCode)
+}
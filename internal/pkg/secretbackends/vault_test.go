@@ -0,0 +1,68 @@
+package secretbackends
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultBackendReadsKVSecrets(t *testing.T) {
+	req := require.New(t)
+
+	fake := NewFakeVault("root-token")
+	fake.Put("apps/grafana", map[string]interface{}{"password": "s3cr3t", "port": 3000})
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	backend, err := NewVaultBackend(VaultConfig{Address: server.URL, Token: "root-token"})
+	req.NoError(err)
+
+	secret, err := backend.Fetch(context.Background(), "apps/grafana")
+	req.NoError(err)
+	req.Equal(map[string]string{"password": "s3cr3t", "port": "3000"}, secret)
+
+	_, err = backend.Fetch(context.Background(), "apps/unknown")
+	req.ErrorIs(err, ErrSecretNotFound)
+}
+
+func TestVaultBackendAuthenticatesWithKubernetes(t *testing.T) {
+	req := require.New(t)
+
+	fake := NewFakeVault("k8s-token")
+	fake.KubernetesRole = "dark"
+	fake.Put("apps/grafana", map[string]interface{}{"password": "s3cr3t"})
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	req.NoError(os.WriteFile(tokenPath, []byte("service-account-jwt\n"), 0o600))
+
+	backend, err := NewVaultBackend(VaultConfig{
+		Address:             server.URL,
+		KubernetesRole:      "dark",
+		KubernetesTokenPath: tokenPath,
+	})
+	req.NoError(err)
+
+	for i := 0; i < 2; i++ {
+		secret, err := backend.Fetch(context.Background(), "apps/grafana")
+		req.NoError(err)
+		req.Equal("s3cr3t", secret["password"])
+	}
+
+	// the token obtained by the first login is reused
+	req.Equal(1, fake.Logins())
+	req.Equal(2, fake.Reads())
+}
+
+func TestVaultBackendRequiresCredentials(t *testing.T) {
+	req := require.New(t)
+
+	_, err := NewVaultBackend(VaultConfig{Address: "http://vault:8200"})
+
+	req.ErrorIs(err, ErrVaultNotConfigured)
+}
@@ -0,0 +1,49 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/dark/internal/pkg/grafana"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-k8s-kevingomez-fr-v1alpha1-alertmanager,mutating=false,failurePolicy=fail,sideEffects=None,groups=k8s.kevingomez.fr,resources=alertmanagers,verbs=create;update,versions=v1alpha1,name=valertmanager.kb.io,admissionReviewVersions=v1
+
+// AlertManagerValidator rejects AlertManager objects that can not be synchronized with
+// Grafana.
+type AlertManagerValidator struct {
+}
+
+var _ admission.CustomValidator = &AlertManagerValidator{}
+
+func SetupAlertManagerWebhook(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.AlertManager{}).
+		WithValidator(&AlertManagerValidator{}).
+		Complete()
+}
+
+func (validator *AlertManagerValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return validator.validate(obj)
+}
+
+func (validator *AlertManagerValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	return validator.validate(newObj)
+}
+
+func (validator *AlertManagerValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func (validator *AlertManagerValidator) validate(obj runtime.Object) error {
+	manifest, ok := obj.(*v1alpha1.AlertManager)
+	if !ok {
+		return fmt.Errorf("expected a AlertManager, got %T", obj)
+	}
+
+	return invalid("AlertManager", manifest.Name, grafana.ValidateAlertManagerSpec(manifest.Spec))
+}
@@ -0,0 +1,49 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/dark/internal/pkg/grafana"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-k8s-kevingomez-fr-v1alpha1-apikey,mutating=false,failurePolicy=fail,sideEffects=None,groups=k8s.kevingomez.fr,resources=apikeys,verbs=create;update,versions=v1alpha1,name=vapikey.kb.io,admissionReviewVersions=v1
+
+// APIKeyValidator rejects APIKey objects that can not be synchronized with
+// Grafana.
+type APIKeyValidator struct {
+}
+
+var _ admission.CustomValidator = &APIKeyValidator{}
+
+func SetupAPIKeyWebhook(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.APIKey{}).
+		WithValidator(&APIKeyValidator{}).
+		Complete()
+}
+
+func (validator *APIKeyValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return validator.validate(obj)
+}
+
+func (validator *APIKeyValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	return validator.validate(newObj)
+}
+
+func (validator *APIKeyValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func (validator *APIKeyValidator) validate(obj runtime.Object) error {
+	manifest, ok := obj.(*v1alpha1.APIKey)
+	if !ok {
+		return fmt.Errorf("expected a APIKey, got %T", obj)
+	}
+
+	return invalid("APIKey", manifest.Name, grafana.ValidateAPIKeySpec(manifest.Spec))
+}
@@ -0,0 +1,49 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/dark/internal/pkg/grafana"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-k8s-kevingomez-fr-v1alpha1-datasource,mutating=false,failurePolicy=fail,sideEffects=None,groups=k8s.kevingomez.fr,resources=datasources,verbs=create;update,versions=v1alpha1,name=vdatasource.kb.io,admissionReviewVersions=v1
+
+// DatasourceValidator rejects Datasource objects that can not be synchronized with
+// Grafana.
+type DatasourceValidator struct {
+}
+
+var _ admission.CustomValidator = &DatasourceValidator{}
+
+func SetupDatasourceWebhook(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.Datasource{}).
+		WithValidator(&DatasourceValidator{}).
+		Complete()
+}
+
+func (validator *DatasourceValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return validator.validate(obj)
+}
+
+func (validator *DatasourceValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	return validator.validate(newObj)
+}
+
+func (validator *DatasourceValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func (validator *DatasourceValidator) validate(obj runtime.Object) error {
+	manifest, ok := obj.(*v1alpha1.Datasource)
+	if !ok {
+		return fmt.Errorf("expected a Datasource, got %T", obj)
+	}
+
+	return invalid("Datasource", manifest.Name, grafana.ValidateDatasourceSpec(manifest.Spec))
+}
@@ -0,0 +1,49 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/dark/internal/pkg/grafana"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-k8s-kevingomez-fr-v1alpha1-grafanaalertrulegroup,mutating=false,failurePolicy=fail,sideEffects=None,groups=k8s.kevingomez.fr,resources=grafanaalertrulegroups,verbs=create;update,versions=v1alpha1,name=vgrafanaalertrulegroup.kb.io,admissionReviewVersions=v1
+
+// GrafanaAlertRuleGroupValidator rejects GrafanaAlertRuleGroup objects that can not be
+// synchronized with Grafana.
+type GrafanaAlertRuleGroupValidator struct {
+}
+
+var _ admission.CustomValidator = &GrafanaAlertRuleGroupValidator{}
+
+func SetupGrafanaAlertRuleGroupWebhook(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.GrafanaAlertRuleGroup{}).
+		WithValidator(&GrafanaAlertRuleGroupValidator{}).
+		Complete()
+}
+
+func (validator *GrafanaAlertRuleGroupValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return validator.validate(obj)
+}
+
+func (validator *GrafanaAlertRuleGroupValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	return validator.validate(newObj)
+}
+
+func (validator *GrafanaAlertRuleGroupValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func (validator *GrafanaAlertRuleGroupValidator) validate(obj runtime.Object) error {
+	manifest, ok := obj.(*v1alpha1.GrafanaAlertRuleGroup)
+	if !ok {
+		return fmt.Errorf("expected a GrafanaAlertRuleGroup, got %T", obj)
+	}
+
+	return invalid("GrafanaAlertRuleGroup", manifest.Name, grafana.ValidateAlertRuleGroupSpec(manifest.Spec))
+}
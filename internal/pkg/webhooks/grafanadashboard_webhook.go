@@ -0,0 +1,91 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	k8skevingomezfrv1 "github.com/K-Phoen/dark/api/v1"
+	"github.com/K-Phoen/dark/internal/pkg/converter"
+	"github.com/K-Phoen/dark/internal/pkg/grafana"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-k8s-kevingomez-fr-v1-grafanadashboard,mutating=false,failurePolicy=fail,sideEffects=None,groups=k8s.kevingomez.fr,resources=grafanadashboards,verbs=create;update,versions=v1,name=vgrafanadashboard.kb.io,admissionReviewVersions=v1
+
+// GrafanaDashboardValidator rejects GrafanaDashboard objects that can not be
+// converted into Grafana dashboards.
+type GrafanaDashboardValidator struct {
+}
+
+var _ admission.CustomValidator = &GrafanaDashboardValidator{}
+
+func SetupGrafanaDashboardWebhook(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&k8skevingomezfrv1.GrafanaDashboard{}).
+		WithValidator(&GrafanaDashboardValidator{}).
+		Complete()
+}
+
+func (validator *GrafanaDashboardValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return validator.validate(obj)
+}
+
+func (validator *GrafanaDashboardValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	return validator.validate(newObj)
+}
+
+func (validator *GrafanaDashboardValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func (validator *GrafanaDashboardValidator) validate(obj runtime.Object) error {
+	dashboard, ok := obj.(*k8skevingomezfrv1.GrafanaDashboard)
+	if !ok {
+		return fmt.Errorf("expected a GrafanaDashboard, got %T", obj)
+	}
+
+	if errs := grafana.ValidatePermissions(field.NewPath("permissions"), dashboard.Permissions); len(errs) != 0 {
+		return invalid("GrafanaDashboard", dashboard.Name, errs)
+	}
+
+	source, err := dashboard.Source()
+	if err != nil {
+		return fmt.Errorf("invalid dashboard spec: %w", err)
+	}
+
+	if ref := dashboard.InstanceRef(); ref != nil && ref.Name == "" {
+		return fmt.Errorf("invalid dashboard spec: instanceRef: name is required")
+	}
+
+	spec, err := dashboard.DashboardSpec()
+	if err != nil {
+		return fmt.Errorf("invalid dashboard spec: %w", err)
+	}
+
+	// the content of ConfigMaps is only validated during reconciliation
+	if source.FromConfigMap != nil {
+		if source.FromConfigMap.Name == "" || source.FromConfigMap.Key == "" {
+			return fmt.Errorf("invalid dashboard spec: fromConfigMap: name and key are required")
+		}
+
+		return nil
+	}
+
+	if source.FromJSON != "" {
+		spec, err = converter.NewJSON(zap.NewNop()).ToSpec(strings.NewReader(source.FromJSON))
+		if err != nil {
+			return fmt.Errorf("invalid dashboard spec: fromJSON: %w", err)
+		}
+	}
+
+	if err := grafana.ValidateRawSpec(spec); err != nil {
+		return fmt.Errorf("invalid dashboard spec: %w", err)
+	}
+
+	return nil
+}
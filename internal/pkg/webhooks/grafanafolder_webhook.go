@@ -0,0 +1,49 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/dark/internal/pkg/grafana"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-k8s-kevingomez-fr-v1alpha1-grafanafolder,mutating=false,failurePolicy=fail,sideEffects=None,groups=k8s.kevingomez.fr,resources=grafanafolders,verbs=create;update,versions=v1alpha1,name=vgrafanafolder.kb.io,admissionReviewVersions=v1
+
+// GrafanaFolderValidator rejects GrafanaFolder objects that can not be
+// synchronized with Grafana.
+type GrafanaFolderValidator struct {
+}
+
+var _ admission.CustomValidator = &GrafanaFolderValidator{}
+
+func SetupGrafanaFolderWebhook(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.GrafanaFolder{}).
+		WithValidator(&GrafanaFolderValidator{}).
+		Complete()
+}
+
+func (validator *GrafanaFolderValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return validator.validate(obj)
+}
+
+func (validator *GrafanaFolderValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	return validator.validate(newObj)
+}
+
+func (validator *GrafanaFolderValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func (validator *GrafanaFolderValidator) validate(obj runtime.Object) error {
+	manifest, ok := obj.(*v1alpha1.GrafanaFolder)
+	if !ok {
+		return fmt.Errorf("expected a GrafanaFolder, got %T", obj)
+	}
+
+	return invalid("GrafanaFolder", manifest.Name, grafana.ValidateFolderSpec(manifest.Spec))
+}
@@ -0,0 +1,49 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	"github.com/K-Phoen/dark/internal/pkg/grafana"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-k8s-kevingomez-fr-v1alpha1-grafanasilence,mutating=false,failurePolicy=fail,sideEffects=None,groups=k8s.kevingomez.fr,resources=grafanasilences,verbs=create;update,versions=v1alpha1,name=vgrafanasilence.kb.io,admissionReviewVersions=v1
+
+// GrafanaSilenceValidator rejects GrafanaSilence objects that can not be
+// synchronized with Grafana.
+type GrafanaSilenceValidator struct {
+}
+
+var _ admission.CustomValidator = &GrafanaSilenceValidator{}
+
+func SetupGrafanaSilenceWebhook(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.GrafanaSilence{}).
+		WithValidator(&GrafanaSilenceValidator{}).
+		Complete()
+}
+
+func (validator *GrafanaSilenceValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return validator.validate(obj)
+}
+
+func (validator *GrafanaSilenceValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	return validator.validate(newObj)
+}
+
+func (validator *GrafanaSilenceValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func (validator *GrafanaSilenceValidator) validate(obj runtime.Object) error {
+	manifest, ok := obj.(*v1alpha1.GrafanaSilence)
+	if !ok {
+		return fmt.Errorf("expected a GrafanaSilence, got %T", obj)
+	}
+
+	return invalid("GrafanaSilence", manifest.Name, grafana.ValidateSilenceSpec(manifest.Spec))
+}
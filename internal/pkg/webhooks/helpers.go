@@ -0,0 +1,17 @@
+package webhooks
+
+import (
+	"github.com/K-Phoen/dark/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// invalid turns a list of validation errors into an API error, or nil if the
+// list is empty.
+func invalid(kind string, name string, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(v1alpha1.GroupVersion.WithKind(kind).GroupKind(), name, errs)
+}